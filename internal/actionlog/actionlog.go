@@ -132,3 +132,13 @@ func (actionLogRepo *ActionLogRepository) DeleteLogsLessThanTimestamp(timestamp
 
 	return nil
 }
+
+// DeleteByUserIdsWithContext Deletes the logs of actions done by the users, context is used for transactions
+func (actionLogRepo *ActionLogRepository) DeleteByUserIdsWithContext(ctx context.Context, userIds []string) (int64, error) {
+	filter := common.CombineFilters(actionLogRepo.DefaultFilter, bson.M{"userid": bson.M{"$in": userIds}})
+	result, err := Collection().DeleteMany(ctx, filter)
+	if err != nil {
+		return 0, err
+	}
+	return result.DeletedCount, nil
+}
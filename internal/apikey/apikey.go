@@ -1,70 +1,131 @@
 package apikey
 
 import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/bb-consent/api/internal/config"
+	"github.com/bb-consent/api/internal/rbac"
 	"github.com/dgrijalva/jwt-go"
 )
 
+// ApiKey Only the hash of the key is persisted, the key itself is
+// returned to the caller once when it is issued.
 type ApiKey struct {
-	Id              string   `json:"id" bson:"_id,omitempty"`
-	Name            string   `json:"name"`
-	Scopes          []string `json:"scopes" valid:"required"`
-	Apikey          string   `json:"apiKey"`
-	ExpiryInDays    int      `json:"expiryInDays"`
-	OrganisationId  string   `json:"-"`
-	IsDeleted       bool     `json:"-"`
-	Timestamp       string   `json:"timestamp"`
-	ExpiryTimestamp string   `json:"expiryTimestamp"`
+	Id                  string   `json:"id" bson:"_id,omitempty"`
+	Name                string   `json:"name"`
+	Scopes              []string `json:"scopes" valid:"required"`
+	DataAgreementIds    []string `json:"dataAgreementIds"`
+	Apikey              string   `json:"apiKey,omitempty" bson:"-"`
+	KeyHash             string   `json:"-"`
+	ExpiryInDays        int      `json:"expiryInDays"`
+	OrganisationId      string   `json:"-"`
+	OrganisationAdminId string   `json:"-"`
+	IsDeleted           bool     `json:"-"`
+	Timestamp           string   `json:"timestamp"`
+	ExpiryTimestamp     string   `json:"expiryTimestamp"`
+	Revoked             bool     `json:"revoked"`
+	RevokedTimestamp    string   `json:"revokedTimestamp"`
+	LastUsedTimestamp   string   `json:"lastUsedTimestamp"`
+	LastUsedIp          string   `json:"lastUsedIp"`
+	UsageCount          int64    `json:"usageCount"`
 }
 
+var ErrApiKeyNotFound = errors.New("api key not found")
+var ErrApiKeyRevoked = errors.New("api key revoked")
+var ErrApiKeyExpired = errors.New("api key expired")
+
+var ErrLegacyApiKeyDisabled = errors.New("api keys signed with the api secret key are disabled")
+
 var ApiSecretKey string
 
+// ApiKeyConfiguration Stores api key configuration
+var ApiKeyConfiguration config.ApiKeyConfig
+
 func Init(config *config.Configuration) {
 	ApiSecretKey = config.ApiSecretKey
+	ApiKeyConfiguration = config.ApiKey
+	if len(ApiKeyConfiguration.LegacySecretKeySunsetTimestamp) > 0 {
+		_, err := time.Parse("2006-01-02T15:04:05Z", ApiKeyConfiguration.LegacySecretKeySunsetTimestamp)
+		if err != nil {
+			panic("api key legacy secret key sunset timestamp should be in the format 2006-01-02T15:04:05Z")
+		}
+	}
+}
 
+// isLegacySecretKeyAllowed Checks if api keys without a kid, which are
+// signed with the api secret key, are still accepted
+func isLegacySecretKeyAllowed() bool {
+	if ApiKeyConfiguration.DisableLegacySecretKey {
+		return false
+	}
+	sunset := ApiKeyConfiguration.LegacySecretKeySunsetTimestamp
+	return len(sunset) == 0 || time.Now().UTC().Format("2006-01-02T15:04:05Z") < sunset
 }
 
 type Claims struct {
 	Scopes              []string
 	OrganisationId      string
 	OrganisationAdminId string
+	DataAgreementIds    []string `json:",omitempty"`
 	// Add other fields as needed
 	jwt.StandardClaims
 }
 
-// Create Create apikey
-func Create(scopes []string, expiresAt int64, organisationId string, organisationAdminId string) (string, error) {
-	var SigningKey = []byte(ApiSecretKey)
-	// Create the Claims
-	claims := Claims{
-		scopes,
-		organisationId,
-		organisationAdminId,
-		jwt.StandardClaims{
+// Prefix of opaque api keys, api keys without it are JWTs issued earlier
+const opaqueKeyPrefix = "bbk_"
+
+// Generate Generates an opaque api key. The key is random and carries no
+// claims, it is looked up by its hash on every request.
+func Generate() (string, error) {
+	b := make([]byte, 32)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", err
+	}
+	return opaqueKeyPrefix + base64.RawURLEncoding.EncodeToString(b), nil
+}
 
-			ExpiresAt: expiresAt,
-		},
+// getSigningKey Returns the secret the apikey was signed with. Api keys
+// issued before the keyring was introduced don't have a `kid` and are
+// signed with the configured api secret key, they are accepted until
+// disabled or the configured sunset.
+func getSigningKey(token *jwt.Token) (interface{}, error) {
+	if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+		return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	ss, err := token.SignedString(SigningKey)
+	kid, _ := token.Header["kid"].(string)
+	if len(kid) == 0 {
+		if !isLegacySecretKeyAllowed() {
+			return nil, ErrLegacyApiKeyDisabled
+		}
+		return []byte(ApiSecretKey), nil
+	}
 
-	return ss, err
+	claims, ok := token.Claims.(*Claims)
+	if !ok {
+		return nil, errors.New("unexpected claims")
+	}
+	signingSecret, err := GetSigningSecret(claims.OrganisationId, kid)
+	if err != nil {
+		return nil, err
+	}
+	if signingSecret.Retired {
+		return nil, ErrSigningSecretRetired
+	}
+	return []byte(signingSecret.Secret), nil
 }
 
 // Decode Decodes the apikey
 func Decode(apiKey string) (claims Claims, err error) {
-	var SigningKey = []byte(ApiSecretKey)
-	token, err := jwt.ParseWithClaims(apiKey, &claims, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-		}
-		return SigningKey, nil
-	})
+	token, err := jwt.ParseWithClaims(apiKey, &claims, getSigningKey)
 
 	if err != nil || !token.Valid {
 		return claims, err
@@ -77,8 +138,58 @@ func Decode(apiKey string) (claims Claims, err error) {
 	return claims, nil
 }
 
+// Hash Returns the hash of the apikey which is persisted in db
+func Hash(apiKey string) string {
+	sum := sha256.Sum256([]byte(apiKey))
+	return hex.EncodeToString(sum[:])
+}
+
+// Authenticate Looks up the api key stored in db by the hash of the key and
+// returns its claims. Api keys issued as JWTs are verified with the secret
+// they were signed with first. Deleted, revoked and expired api keys are
+// rejected.
+func Authenticate(key string) (Claims, error) {
+	var claims Claims
+	var err error
+	if !strings.HasPrefix(key, opaqueKeyPrefix) {
+		claims, err = Decode(key)
+		if err != nil {
+			return claims, err
+		}
+	}
+
+	apiKey, err := GetByKeyHash(Hash(key))
+	if err != nil {
+		return claims, ErrApiKeyNotFound
+	}
+	if len(claims.OrganisationId) > 0 && claims.OrganisationId != apiKey.OrganisationId {
+		return claims, ErrApiKeyNotFound
+	}
+	if apiKey.Revoked {
+		return claims, ErrApiKeyRevoked
+	}
+	expiryTime, err := time.Parse("2006-01-02T15:04:05Z", apiKey.ExpiryTimestamp)
+	if err == nil && expiryTime.Before(time.Now()) {
+		return claims, ErrApiKeyExpired
+	}
+
+	// Stored api key is authoritative
+	claims.Id = apiKey.Id
+	claims.OrganisationId = apiKey.OrganisationId
+	if len(apiKey.OrganisationAdminId) > 0 {
+		claims.OrganisationAdminId = apiKey.OrganisationAdminId
+	}
+	claims.Scopes = apiKey.Scopes
+	claims.DataAgreementIds = apiKey.DataAgreementIds
+	if err == nil {
+		claims.ExpiresAt = expiryTime.Unix()
+	}
+	return claims, nil
+}
+
+// ValidateScopes Checks the scopes can be granted to an api key
 func ValidateScopes(scopes []string) bool {
-	allowedScopes := []string{"service", "audit", "config", "onboard"}
+	allowedScopes := rbac.GetApiKeyScopes()
 
 	for _, scope := range scopes {
 		found := false
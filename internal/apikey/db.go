@@ -50,8 +50,22 @@ func (apiKeyRepo *ApiKeyRepository) Update(apiKey ApiKey) (ApiKey, error) {
 
 	apiKey.Timestamp = time.Now().UTC().Format("2006-01-02T15:04:05Z")
 
+	// Usage of the api key is recorded concurrently, so only the fields
+	// which can be changed through the api are updated
 	filter := common.CombineFilters(apiKeyRepo.DefaultFilter, bson.M{"_id": apiKey.Id})
-	update := bson.M{"$set": apiKey}
+	update := bson.M{"$set": bson.M{
+		"name":                apiKey.Name,
+		"scopes":              apiKey.Scopes,
+		"dataagreementids":    apiKey.DataAgreementIds,
+		"keyhash":             apiKey.KeyHash,
+		"organisationadminid": apiKey.OrganisationAdminId,
+		"expiryindays":        apiKey.ExpiryInDays,
+		"expirytimestamp":     apiKey.ExpiryTimestamp,
+		"timestamp":           apiKey.Timestamp,
+		"isdeleted":           apiKey.IsDeleted,
+		"revoked":             apiKey.Revoked,
+		"revokedtimestamp":    apiKey.RevokedTimestamp,
+	}}
 
 	_, err := Collection().UpdateOne(context.TODO(), filter, update)
 	if err != nil {
@@ -59,3 +73,30 @@ func (apiKeyRepo *ApiKeyRepository) Update(apiKey ApiKey) (ApiKey, error) {
 	}
 	return apiKey, nil
 }
+
+// GetByKeyHash Gets a single api key by the hash of the key across
+// organisations, the organisation of the api key is known only after lookup
+func GetByKeyHash(keyHash string) (ApiKey, error) {
+
+	filter := bson.M{"keyhash": keyHash, "isdeleted": false}
+
+	var result ApiKey
+	err := Collection().FindOne(context.TODO(), filter).Decode(&result)
+	return result, err
+}
+
+// RecordUsage Records the timestamp and ip address of the latest request made using the api key
+func (apiKeyRepo *ApiKeyRepository) RecordUsage(apiKeyId string, ipAddress string) error {
+
+	filter := common.CombineFilters(apiKeyRepo.DefaultFilter, bson.M{"_id": apiKeyId})
+	update := bson.M{
+		"$set": bson.M{
+			"lastusedtimestamp": time.Now().UTC().Format("2006-01-02T15:04:05Z"),
+			"lastusedip":        ipAddress,
+		},
+		"$inc": bson.M{"usagecount": 1},
+	}
+
+	_, err := Collection().UpdateOne(context.TODO(), filter, update)
+	return err
+}
@@ -0,0 +1,82 @@
+package apikey
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/bb-consent/api/internal/database"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// SigningSecret Secret the api keys of an organisation which were issued as
+// JWTs are signed with. The id of the secret is set as `kid` in the header of
+// the api key. Api keys are issued as opaque keys now, so no new secrets are
+// added; the secrets are kept for verifying the earlier api keys until they
+// are retired.
+type SigningSecret struct {
+	Id               string `json:"id" bson:"_id,omitempty"`
+	OrganisationId   string `json:"-"`
+	Secret           string `json:"-"`
+	Timestamp        string `json:"timestamp"`
+	Retired          bool   `json:"retired"`
+	RetiredTimestamp string `json:"retiredTimestamp"`
+}
+
+var ErrSigningSecretRetired = errors.New("signing secret retired")
+
+func SigningSecretCollection() *mongo.Collection {
+	return database.DB.Client.Database(database.DB.Name).Collection("apiKeySigningSecrets")
+}
+
+// GetSigningSecret Gets signing secret by id
+func GetSigningSecret(organisationId string, signingSecretId string) (SigningSecret, error) {
+	var result SigningSecret
+
+	filter := bson.M{"_id": signingSecretId, "organisationid": organisationId}
+	err := SigningSecretCollection().FindOne(context.TODO(), filter).Decode(&result)
+	return result, err
+}
+
+// ListSigningSecrets Lists signing secrets of the organisation, newest first
+func ListSigningSecrets(organisationId string) ([]SigningSecret, error) {
+	results := []SigningSecret{}
+
+	opts := options.Find().SetSort(bson.D{{Key: "timestamp", Value: -1}, {Key: "_id", Value: -1}})
+	cursor, err := SigningSecretCollection().Find(context.TODO(), bson.M{"organisationid": organisationId}, opts)
+	if err != nil {
+		return results, err
+	}
+	defer cursor.Close(context.TODO())
+
+	if err := cursor.All(context.TODO(), &results); err != nil {
+		return results, err
+	}
+	return results, nil
+}
+
+// RetireSigningSecret Retires the signing secret, api keys signed with it
+// stop working. Any secret can be retired, new api keys aren't signed.
+func RetireSigningSecret(organisationId string, signingSecretId string) (SigningSecret, error) {
+	signingSecret, err := GetSigningSecret(organisationId, signingSecretId)
+	if err != nil {
+		return signingSecret, err
+	}
+	if signingSecret.Retired {
+		return signingSecret, nil
+	}
+
+	signingSecret.Retired = true
+	signingSecret.RetiredTimestamp = time.Now().UTC().Format("2006-01-02T15:04:05Z")
+
+	filter := bson.M{"_id": signingSecret.Id, "organisationid": organisationId}
+	update := bson.M{"$set": bson.M{"retired": true, "retiredtimestamp": signingSecret.RetiredTimestamp}}
+
+	_, err = SigningSecretCollection().UpdateOne(context.TODO(), filter, update)
+	if err != nil {
+		return signingSecret, err
+	}
+	return signingSecret, nil
+}
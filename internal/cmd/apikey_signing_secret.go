@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"text/tabwriter"
+
+	"github.com/bb-consent/api/internal/apikey"
+	"github.com/bb-consent/api/internal/config"
+	"github.com/bb-consent/api/internal/database"
+	"github.com/spf13/cobra"
+)
+
+// Flags for api key signing secret commands
+var (
+	SigningSecretOrganisationId string
+	SigningSecretId             string
+)
+
+// initApiKeySigningSecretCmd Loads configuration and initialises the packages required for managing signing secrets
+func initApiKeySigningSecretCmd() {
+
+	// Load configuration
+	configFile := "/opt/bb-consent/api/config/" + ConfigFileName
+	loadedConfig, err := config.Load(configFile)
+	if err != nil {
+		log.Printf("Failed to load config file %s \n", configFile)
+		panic(err)
+	}
+
+	// Database
+	err = database.Init(loadedConfig)
+	if err != nil {
+		panic(err)
+	}
+
+	apikey.Init(loadedConfig)
+}
+
+func printSigningSecrets(signingSecrets ...apikey.SigningSecret) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tTIMESTAMP\tRETIRED\tRETIRED TIMESTAMP")
+	for _, s := range signingSecrets {
+		fmt.Fprintf(w, "%s\t%s\t%v\t%s\n", s.Id, s.Timestamp, s.Retired, s.RetiredTimestamp)
+	}
+	w.Flush()
+}
+
+// ApiKeySigningSecretListCmdHandler Lists signing secrets of the organisation
+func ApiKeySigningSecretListCmdHandler(cmd *cobra.Command, args []string) {
+	initApiKeySigningSecretCmd()
+
+	signingSecrets, err := apikey.ListSigningSecrets(SigningSecretOrganisationId)
+	if err != nil {
+		log.Printf("Failed to list signing secrets for organisation: %v", SigningSecretOrganisationId)
+		panic(err)
+	}
+
+	printSigningSecrets(signingSecrets...)
+}
+
+// ApiKeySigningSecretRetireCmdHandler Retires a signing secret of the organisation
+func ApiKeySigningSecretRetireCmdHandler(cmd *cobra.Command, args []string) {
+	initApiKeySigningSecretCmd()
+
+	signingSecret, err := apikey.RetireSigningSecret(SigningSecretOrganisationId, SigningSecretId)
+	if err != nil {
+		log.Printf("Failed to retire signing secret: %v", SigningSecretId)
+		panic(err)
+	}
+
+	printSigningSecrets(signingSecret)
+}
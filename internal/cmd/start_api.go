@@ -6,16 +6,24 @@ import (
 
 	"github.com/bb-consent/api/internal/apikey"
 	"github.com/bb-consent/api/internal/config"
+	consentexpiry "github.com/bb-consent/api/internal/consent_expiry"
+	dataexport "github.com/bb-consent/api/internal/data_export"
 	"github.com/bb-consent/api/internal/database"
 	"github.com/bb-consent/api/internal/email"
+	"github.com/bb-consent/api/internal/eventlog"
+	"github.com/bb-consent/api/internal/eventsink"
 	v2HttpPaths "github.com/bb-consent/api/internal/http_path/v2"
 	"github.com/bb-consent/api/internal/iam"
+	"github.com/bb-consent/api/internal/invitation"
 	"github.com/bb-consent/api/internal/middleware"
 	"github.com/bb-consent/api/internal/migrate"
+	"github.com/bb-consent/api/internal/outbox"
 	privacyDashboard "github.com/bb-consent/api/internal/privacy_dashboard"
 	"github.com/bb-consent/api/internal/rbac"
+	"github.com/bb-consent/api/internal/signingkey"
 	"github.com/bb-consent/api/internal/tenant"
 	"github.com/bb-consent/api/internal/webhook"
+	"github.com/bb-consent/api/internal/webhook_dispatcher"
 	"github.com/casbin/casbin/v2"
 	"github.com/gorilla/mux"
 	"github.com/spf13/cobra"
@@ -50,6 +58,43 @@ func StartApiCmdHandler(cmd *cobra.Command, args []string) {
 	webhook.Init(loadedConfig)
 	log.Println("Webhooks configuration initialized")
 
+	// Webhook delivery workers
+	webhook_dispatcher.Init(loadedConfig)
+	// Secrets encrypted with previous encryption keys are re-encrypted
+	err = webhook.ReencryptClientKeys()
+	if err != nil {
+		panic(err)
+	}
+	err = signingkey.ReencryptPrivateKeys()
+	if err != nil {
+		panic(err)
+	}
+	webhook_dispatcher.StartWorkers()
+	log.Println("Webhook delivery workers initialized")
+
+	// Event sinks
+	err = eventsink.Init(loadedConfig)
+	if err != nil {
+		panic(err)
+	}
+	// Consent and data agreement events are recorded for streaming
+	eventlog.Init(loadedConfig)
+	eventsink.Register(eventlog.NewSink())
+	log.Println("Event sinks initialized")
+
+	// Consent events are published from the outbox
+	outbox.StartRelay()
+	log.Println("Outbox relay initialized")
+
+	// Consent records expire after the data retention period
+	consentexpiry.Init(loadedConfig)
+	consentexpiry.Start()
+	log.Println("Consent expiry job initialized")
+
+	// Expired data export archives are deleted periodically
+	dataexport.StartCleanup()
+	log.Println("Data export cleanup job initialized")
+
 	// IAM
 	iam.Init(loadedConfig)
 	log.Println("Iam initialized")
@@ -58,6 +103,10 @@ func StartApiCmdHandler(cmd *cobra.Command, args []string) {
 	email.Init(loadedConfig)
 	log.Println("Email initialized")
 
+	// Admin invitations
+	invitation.Init(loadedConfig)
+	log.Println("Admin invitations initialized")
+
 	// Privacy Dashboard
 	privacyDashboard.Init(loadedConfig)
 	log.Println("Privacy Dashboard initialized")
@@ -66,6 +115,14 @@ func StartApiCmdHandler(cmd *cobra.Command, args []string) {
 	middleware.ApplicationModeInit(loadedConfig)
 	log.Println("Application mode initialized")
 
+	// Platform operator
+	middleware.PlatformOperatorInit(loadedConfig)
+	log.Println("Platform operator initialized")
+
+	// Tenant provisioning
+	tenant.Init(loadedConfig)
+	log.Println("Tenant provisioning initialized")
+
 	apikey.Init(loadedConfig)
 	log.Println("Api key initialized")
 
@@ -89,6 +146,7 @@ func StartApiCmdHandler(cmd *cobra.Command, args []string) {
 	case config.SingleTenant:
 		tenant.SingleTenantConfiguration(loadedConfig)
 	case config.MultiTenant:
+		tenant.MultiTenantConfiguration(loadedConfig)
 	default:
 		tenant.SingleTenantConfiguration(loadedConfig)
 	}
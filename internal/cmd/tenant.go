@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"text/tabwriter"
+
+	"github.com/bb-consent/api/internal/config"
+	"github.com/bb-consent/api/internal/database"
+	"github.com/bb-consent/api/internal/iam"
+	"github.com/bb-consent/api/internal/tenant"
+	"github.com/spf13/cobra"
+)
+
+// Flags for tenant commands
+var (
+	TenantOrganisation config.Organization
+	TenantAdmin        config.User
+	TenantId           string
+	ReuseExistingAdmin bool
+)
+
+// initTenantCmd Loads configuration and initialises the packages required for provisioning tenants
+func initTenantCmd() {
+
+	// Load configuration
+	configFile := "/opt/bb-consent/api/config/" + ConfigFileName
+	loadedConfig, err := config.Load(configFile)
+	if err != nil {
+		log.Printf("Failed to load config file %s \n", configFile)
+		panic(err)
+	}
+
+	// Database
+	err = database.Init(loadedConfig)
+	if err != nil {
+		panic(err)
+	}
+
+	// IAM
+	iam.Init(loadedConfig)
+
+	// Tenant provisioning
+	tenant.Init(loadedConfig)
+}
+
+func printTenants(tenants ...tenant.Tenant) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tNAME\tLOCATION\tENABLED\tADMINS")
+	for _, t := range tenants {
+		var admins []string
+		for _, admin := range t.Admins {
+			admins = append(admins, admin.Email)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%v\t%v\n", t.Organisation.ID, t.Organisation.Name, t.Organisation.Location, t.Organisation.Enabled, admins)
+	}
+	w.Flush()
+}
+
+// TenantCreateCmdHandler Creates an organisation along with its admin
+func TenantCreateCmdHandler(cmd *cobra.Command, args []string) {
+	initTenantCmd()
+
+	t, err := tenant.CreateTenant(TenantOrganisation, TenantAdmin, config.GlobalPolicy{}, ReuseExistingAdmin)
+	if err != nil {
+		log.Printf("Failed to create tenant: %v", TenantOrganisation.Name)
+		panic(err)
+	}
+
+	printTenants(t)
+}
+
+// TenantListCmdHandler Lists all organisations
+func TenantListCmdHandler(cmd *cobra.Command, args []string) {
+	initTenantCmd()
+
+	tenants, err := tenant.ListTenants()
+	if err != nil {
+		log.Println("Failed to list tenants")
+		panic(err)
+	}
+
+	printTenants(tenants...)
+}
+
+// TenantDisableCmdHandler Disables an organisation
+func TenantDisableCmdHandler(cmd *cobra.Command, args []string) {
+	initTenantCmd()
+
+	t, err := tenant.DisableTenant(TenantId)
+	if err != nil {
+		log.Printf("Failed to disable tenant: %v", TenantId)
+		panic(err)
+	}
+
+	printTenants(t)
+}
+
+// TenantDeleteCmdHandler Deletes a disabled organisation
+func TenantDeleteCmdHandler(cmd *cobra.Command, args []string) {
+	initTenantCmd()
+
+	t, err := tenant.DeleteTenant(TenantId)
+	if err != nil {
+		log.Printf("Failed to delete tenant: %v", TenantId)
+		panic(err)
+	}
+
+	printTenants(t)
+}
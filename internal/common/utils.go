@@ -58,7 +58,9 @@ type OrgRole struct {
 var orgRoles = []OrgRole{
 	{ID: 1, Role: "Admin"},
 	{ID: 2, Role: "Dpo"},
-	{ID: 3, Role: "Developer"}}
+	{ID: 3, Role: "Developer"},
+	{ID: 4, Role: "Auditor"},
+	{ID: 5, Role: "ReadOnlyAdmin"}}
 
 // PaginationLinks pagination links
 type PaginationLinks struct {
@@ -68,6 +70,9 @@ type PaginationLinks struct {
 
 // GetRole Gets Role details by ID
 func GetRole(roleID int) OrgRole {
+	if !IsValidRoleID(roleID) {
+		return OrgRole{}
+	}
 	return orgRoles[roleID-1]
 }
 
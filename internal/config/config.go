@@ -30,9 +30,74 @@ type SmtpConfig struct {
 	AdminEmail string
 }
 
+// WebhookDeliveryConfig webhook delivery queue configuration
+type WebhookDeliveryConfig struct {
+	Workers                 int `json:"workers"`                 // Number of workers delivering webhook payloads
+	MaxAttempts             int `json:"maxAttempts"`             // Delivery attempts before the delivery is marked as failed
+	InitialBackoffInSeconds int `json:"initialBackoffInSeconds"` // Delay before the first retry, doubled for every retry
+	MaxBackoffInSeconds     int `json:"maxBackoffInSeconds"`     // Upper limit for the delay between retries
+	PollIntervalInSeconds   int `json:"pollIntervalInSeconds"`   // Interval at which workers look for pending deliveries
+
+	DisableAfterConsecutiveFailures int `json:"disableAfterConsecutiveFailures"` // Failed attempts in a row after which the webhook is disabled
+}
+
+// WebhookNetworkPolicyConfig outbound network policy for webhook endpoints
+type WebhookNetworkPolicyConfig struct {
+	AllowPrivateNetworks        bool     `json:"allowPrivateNetworks"`        // Allow private, loopback and link-local addresses
+	AllowList                   []string `json:"allowList"`                   // Hosts (e.g. hooks.example.com or .example.com) or CIDRs allowed even if private
+	BlockList                   []string `json:"blockList"`                   // Hosts or CIDRs which are never allowed
+	RequireHTTPS                bool     `json:"requireHttps"`                // Allow only https:// endpoints
+	DisallowSkipSSLVerification bool     `json:"disallowSkipSslVerification"` // SSL certificate is always verified
+}
+
+// KafkaSinkConfig kafka event sink configuration
+type KafkaSinkConfig struct {
+	Enabled bool     `json:"enabled"`
+	Brokers []string `json:"brokers"` // Broker addresses for e.g. kafka:9092
+	Topic   string   `json:"topic"`
+}
+
+// NatsSinkConfig nats event sink configuration
+type NatsSinkConfig struct {
+	Enabled bool   `json:"enabled"`
+	Url     string `json:"url"`     // Server URL for e.g. nats://nats:4222
+	Subject string `json:"subject"` // Subject prefix, event type is appended for e.g. consentbb.events.consent.allowed
+	Token   string `json:"token"`   // Authentication token, optional
+}
+
+// EventSinksConfig message bus sinks to which webhook events are published
+type EventSinksConfig struct {
+	Kafka KafkaSinkConfig `json:"kafka"`
+	Nats  NatsSinkConfig  `json:"nats"`
+}
+
 // WebhooksConfig webhooks configuration (kafka broker cluster, topic e.t.c)
 type WebhooksConfig struct {
-	Events []string `json:"events"`
+	Events                 []string                   `json:"events"`
+	Delivery               WebhookDeliveryConfig      `json:"delivery"`
+	NetworkPolicy          WebhookNetworkPolicyConfig `json:"networkPolicy"`
+	Sinks                  EventSinksConfig           `json:"sinks"`
+	EncryptionKey          string                     `json:"encryptionKey"`          // Base64 encoded 32 byte key for encrypting webhook secrets, required
+	EncryptionKeyId        string                     `json:"encryptionKeyId"`        // Id of the encryption key stored along with the encrypted secrets
+	PreviousEncryptionKeys map[string]string          `json:"previousEncryptionKeys"` // Previous encryption keys by id, secrets encrypted with them are re-encrypted on startup
+}
+
+// ConsentExpiryConfig consent expiry job configuration, consent records
+// expire after the data retention period of the data agreement policy
+type ConsentExpiryConfig struct {
+	Disabled          bool `json:"disabled"`
+	IntervalInMinutes int  `json:"intervalInMinutes"` // Interval at which expired consent records are looked up
+}
+
+// EventStreamConfig configuration for streaming events over server-sent events and websocket
+type EventStreamConfig struct {
+	AllowedOrigins []string `json:"allowedOrigins"` // Origins for e.g. https://dashboard.example.com allowed to open websocket, besides the same origin
+}
+
+// ApiKeyConfig api key configuration
+type ApiKeyConfig struct {
+	DisableLegacySecretKey         bool   `json:"disableLegacySecretKey"`         // Reject api keys without a kid, which are signed with the api secret key
+	LegacySecretKeySunsetTimestamp string `json:"legacySecretKeySunsetTimestamp"` // UTC timestamp after which api keys without a kid are rejected for e.g. 2025-01-01T00:00:00Z
 }
 
 // Organization organization data type
@@ -48,10 +113,27 @@ type User struct {
 	Password string `valid:"required"`
 }
 
+// MultiTenancy Multi tenant deployment configuration
+type MultiTenancy struct {
+	HostMapping map[string]string `json:"hostMapping"` // Request host to organisation ID
+}
+
+// PlatformOperator Users allowed to provision tenants in multi tenant mode
+type PlatformOperator struct {
+	Usernames []string `json:"usernames"`
+}
+
 type PrivacyDashboard struct {
 	Hostname string
 	Version  string
 }
+
+// AdminInvitation Configuration for inviting organisation admins
+type AdminInvitation struct {
+	ActivationUrl string `json:"activationUrl"`
+	ExpiryInHours int    `json:"expiryInHours"`
+}
+
 type GlobalPolicy struct {
 	Name                  string `json:"name"`
 	Url                   string `json:"url"`
@@ -69,15 +151,21 @@ type Configuration struct {
 		Password string
 	}
 	ApplicationMode            string
+	MultiTenancy               MultiTenancy
+	PlatformOperator           PlatformOperator
 	TestMode                   bool
 	Organization               Organization
 	User                       User
 	ApiSecretKey               string
+	ApiKey                     ApiKeyConfig `json:"apiKey"`
 	Iam                        Iam
 	PrivacyDashboardDeployment PrivacyDashboard
+	AdminInvitation            AdminInvitation
 	Smtp                       SmtpConfig
 	Webhooks                   WebhooksConfig
 	Policy                     GlobalPolicy
+	ConsentExpiry              ConsentExpiryConfig `json:"consentExpiry"`
+	EventStream                EventStreamConfig   `json:"eventStream"`
 }
 
 // Load the config file
@@ -5,6 +5,7 @@ const (
 	ContentTypeHeader         = "Content-Type"
 	ContentTypeJSON           = "application/json"
 	ContentTypeImage          = "image/jpeg"
+	ContentTypeZip            = "application/zip"
 	ContentTypeFormURLEncoded = "application/x-www-form-urlencoded"
 )
 
@@ -27,7 +28,10 @@ const (
 	DeliveryId            = "deliveryId"
 	IdpId                 = "idpId"
 	ApiKeyId              = "apiKeyId"
+	SigningSecretId       = "signingSecretId"
+	UserId                = "userId"
 	IndividualHeaderKey   = "X-ConsentBB-IndividualId"
+	OrganisationHeaderKey = "X-ConsentBB-OrganisationId"
 	RevisionId            = "revisionId"
 	LawfulBasis           = "lawfulBasis"
 	Id                    = "id"
@@ -35,6 +39,10 @@ const (
 	RedirectUri           = "redirectUri"
 	IncludeRevisions      = "includeRevisions"
 	ConsentRecordId       = "consentRecordId"
+	RedeliveryJobId       = "redeliveryJobId"
+	GuardianId            = "guardianId"
+	GuardianHeaderKey     = "X-ConsentBB-GuardianId"
+	DataExportId          = "exportId"
 )
 
 // Schemas
@@ -52,6 +60,16 @@ const (
 	DataUsingService = "data_using_service"
 )
 
+// Lawful basis of processing
+const (
+	LawfulBasisConsent            = "consent"
+	LawfulBasisContract           = "contract"
+	LawfulBasisLegalObligation    = "legal_obligation"
+	LawfulBasisVitalInterest      = "vital_interest"
+	LawfulBasisPublicTask         = "public_task"
+	LawfulBasisLegitimateInterest = "legitimate_interest"
+)
+
 // Lifecycle
 const (
 	Draft    = "draft"
@@ -68,6 +86,8 @@ const (
 
 // Data agreement record state
 const (
-	Unsigned = "unsigned"
-	Signed   = "signed"
+	Unsigned        = "unsigned"
+	Signed          = "signed"
+	Expired         = "expired"
+	RenewalRequired = "renewal_required"
 )
@@ -0,0 +1,193 @@
+package consentexpiry
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/bb-consent/api/internal/config"
+	"github.com/bb-consent/api/internal/dataagreement"
+	daRecord "github.com/bb-consent/api/internal/dataagreement_record"
+	daRecordHistory "github.com/bb-consent/api/internal/dataagreement_record_history"
+	"github.com/bb-consent/api/internal/database"
+	"github.com/bb-consent/api/internal/outbox"
+	"github.com/bb-consent/api/internal/revision"
+	"github.com/bb-consent/api/internal/webhook"
+)
+
+// Configuration Stores consent expiry job configuration
+var Configuration config.ConsentExpiryConfig
+
+var startOnce sync.Once
+
+// Actor the expiry revisions are attributed to, expiry isn't authorised by the individual
+const systemActor = "system:consent-expiry"
+
+// Number of consent records whose latest revision is looked up at once
+const expiryCandidatesBatchSize = 500
+
+// Init Initializes consent expiry job configuration, defaults are used for unset values
+func Init(config *config.Configuration) {
+	Configuration = config.ConsentExpiry
+	if Configuration.IntervalInMinutes <= 0 {
+		Configuration.IntervalInMinutes = 60
+	}
+}
+
+// filterExpired Returns the consent records whose validity window has
+// passed, the window starts when the consent record was last updated. Latest
+// revisions are looked up in batches instead of for each consent record.
+func filterExpired(consentRecords []daRecord.DataAgreementRecord, cutoffTimestamp string) ([]daRecord.DataAgreementRecord, error) {
+	expiredRecords := []daRecord.DataAgreementRecord{}
+
+	for start := 0; start < len(consentRecords); start += expiryCandidatesBatchSize {
+		end := start + expiryCandidatesBatchSize
+		if end > len(consentRecords) {
+			end = len(consentRecords)
+		}
+		batch := consentRecords[start:end]
+
+		consentRecordIds := make([]string, len(batch))
+		for i, consentRecord := range batch {
+			consentRecordIds[i] = consentRecord.Id
+		}
+
+		expiredIds, err := revision.ListObjectIdsLastRevisedBefore(consentRecordIds, config.DataAgreementRecord, cutoffTimestamp)
+		if err != nil {
+			return expiredRecords, err
+		}
+		isExpired := make(map[string]bool, len(expiredIds))
+		for _, id := range expiredIds {
+			isExpired[id] = true
+		}
+
+		for _, consentRecord := range batch {
+			if isExpired[consentRecord.Id] {
+				expiredRecords = append(expiredRecords, consentRecord)
+			}
+		}
+	}
+	return expiredRecords, nil
+}
+
+// expireConsentRecord Expires the consent record, the record, revision,
+// history and webhook event are saved in a transaction. Consent record is
+// expired only if it isn't expired already, so replicas running the job at
+// the same time don't expire it twice.
+func expireConsentRecord(consentRecord daRecord.DataAgreementRecord, dataRetentionPeriodDays int) (bool, error) {
+	// Consent record is expired against the data agreement revision it was given for
+	dataAgreementRevision, err := revision.GetByRevisionIdAndSchema(consentRecord.DataAgreementRevisionId, config.DataAgreement)
+	if err != nil {
+		return false, err
+	}
+
+	consentRecord.OptIn = false
+	consentRecord.State = config.Expired
+
+	// Repository
+	darRepo := daRecord.DataAgreementRecordRepository{}
+	darRepo.Init(consentRecord.OrganisationId)
+
+	var expired bool
+	err = database.WithTransaction(func(ctx context.Context) error {
+		var err error
+		expired, err = darRepo.ExpireWithContext(ctx, consentRecord.Id)
+		if err != nil || !expired {
+			return err
+		}
+
+		newRevision, err := revision.UpdateRevisionForDataAgreementRecord(consentRecord, systemActor, dataAgreementRevision)
+		if err != nil {
+			return err
+		}
+		_, err = revision.AddWithContext(ctx, newRevision)
+		if err != nil {
+			return err
+		}
+
+		_, err = outbox.AddConsentRecordEvent(ctx, consentRecord, consentRecord.OrganisationId, webhook.EventTypes[webhook.EventTypeConsentAutoExpiry])
+		if err != nil {
+			return err
+		}
+
+		darH := daRecordHistory.DataAgreementRecordsHistory{}
+		darH.DataAgreementId = consentRecord.DataAgreementId
+		darH.OrganisationId = consentRecord.OrganisationId
+		darH.ConsentRecordId = consentRecord.Id
+		darH.IndividualId = consentRecord.IndividualId
+		return daRecordHistory.DataAgreementRecordHistoryAddExpiryWithContext(ctx, darH, dataRetentionPeriodDays)
+	})
+	return expired, err
+}
+
+// ExpireConsentRecords Expires the consent records whose validity window,
+// data retention period of the data agreement policy, has passed
+func ExpireConsentRecords() {
+	dataAgreements, err := dataagreement.ListWithDataRetentionPeriod()
+	if err != nil {
+		log.Printf("Failed to list data agreements for consent expiry: %v", err)
+		return
+	}
+
+	var expiredCount int
+	for _, da := range dataAgreements {
+		retentionPeriod := time.Duration(da.Policy.DataRetentionPeriodDays) * 24 * time.Hour
+		cutoffTimestamp := time.Now().UTC().Add(-retentionPeriod).Format("2006-01-02T15:04:05Z")
+
+		// Repository
+		darRepo := daRecord.DataAgreementRecordRepository{}
+		darRepo.Init(da.OrganisationId)
+
+		consentRecords, err := darRepo.ListExpiryCandidates(da.Id)
+		if err != nil {
+			log.Printf("Failed to list consent records of data agreement:%v for consent expiry: %v", da.Id, err)
+			continue
+		}
+
+		expiredRecords, err := filterExpired(consentRecords, cutoffTimestamp)
+		if err != nil {
+			log.Printf("Failed to check expiry of consent records of data agreement:%v err:%v", da.Id, err)
+			continue
+		}
+
+		for _, consentRecord := range expiredRecords {
+			expired, err := expireConsentRecord(consentRecord, da.Policy.DataRetentionPeriodDays)
+			if err != nil {
+				log.Printf("Failed to expire consent record:%v err:%v", consentRecord.Id, err)
+				continue
+			}
+			if expired {
+				expiredCount++
+			}
+		}
+	}
+
+	if expiredCount > 0 {
+		log.Printf("Expired %v consent records", expiredCount)
+		outbox.Notify()
+	}
+}
+
+// run Expires consent records at interval
+func run() {
+	ticker := time.NewTicker(time.Duration(Configuration.IntervalInMinutes) * time.Minute)
+	defer ticker.Stop()
+
+	for {
+		ExpireConsentRecords()
+		<-ticker.C
+	}
+}
+
+// Start Starts the consent expiry job unless it is disabled
+func Start() {
+	if Configuration.Disabled {
+		log.Println("Consent expiry job is disabled")
+		return
+	}
+	startOnce.Do(func() {
+		go run()
+		log.Printf("Started consent expiry job, runs every %v minutes", Configuration.IntervalInMinutes)
+	})
+}
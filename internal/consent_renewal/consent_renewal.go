@@ -0,0 +1,208 @@
+package consentrenewal
+
+import (
+	"context"
+	"log"
+
+	"github.com/bb-consent/api/internal/config"
+	"github.com/bb-consent/api/internal/dataagreement"
+	daRecord "github.com/bb-consent/api/internal/dataagreement_record"
+	daRecordHistory "github.com/bb-consent/api/internal/dataagreement_record_history"
+	"github.com/bb-consent/api/internal/database"
+	"github.com/bb-consent/api/internal/outbox"
+	"github.com/bb-consent/api/internal/revision"
+	"github.com/bb-consent/api/internal/webhook"
+)
+
+// dataAgreementVersion Version details of a data agreement revision
+type dataAgreementVersion struct {
+	revision                revision.Revision
+	version                 string
+	compatibleWithVersionId string
+}
+
+// getDataAgreementVersions Returns the versions of data agreement by revision ID
+func getDataAgreementVersions(dataAgreementId string) (map[string]dataAgreementVersion, error) {
+	revisions, err := revision.ListAllByObjectIdAndSchemaName(dataAgreementId, config.DataAgreement)
+	if err != nil {
+		return nil, err
+	}
+
+	versions := make(map[string]dataAgreementVersion)
+	for _, r := range revisions {
+		da, err := revision.RecreateDataAgreementFromRevision(r)
+		if err != nil {
+			return nil, err
+		}
+		versions[r.Id] = dataAgreementVersion{
+			revision:                r,
+			version:                 da.Version,
+			compatibleWithVersionId: da.CompatibleWithVersionId,
+		}
+	}
+	return versions, nil
+}
+
+// getCompatibleVersions Returns the revision IDs and versions the data
+// agreement is compatible with. Compatibility is transitive, if the new
+// version is compatible with a version which in turn is compatible with an
+// older version, the new version is compatible with the older version too.
+func getCompatibleVersions(dataAgreement dataagreement.DataAgreement, versions map[string]dataAgreementVersion) map[string]bool {
+	compatible := make(map[string]bool)
+
+	versionId := dataAgreement.CompatibleWithVersionId
+	for len(versionId) > 0 && !compatible[versionId] {
+		compatible[versionId] = true
+
+		// Version ID can be either the revision ID or version of data agreement
+		next := ""
+		for revisionId, v := range versions {
+			if revisionId == versionId || v.version == versionId {
+				compatible[revisionId] = true
+				compatible[v.version] = true
+				next = v.compatibleWithVersionId
+				break
+			}
+		}
+		versionId = next
+	}
+	return compatible
+}
+
+// isCompatible Checks if the data agreement revision of the consent record is
+// compatible with the new version
+func isCompatible(consentRecord daRecord.DataAgreementRecord, compatible map[string]bool, versions map[string]dataAgreementVersion) bool {
+	if compatible[consentRecord.DataAgreementRevisionId] {
+		return true
+	}
+	v, ok := versions[consentRecord.DataAgreementRevisionId]
+	return ok && compatible[v.version]
+}
+
+// carryForward Moves the consent record to the new data agreement revision
+func carryForward(consentRecord daRecord.DataAgreementRecord, dataAgreementRevision revision.Revision) (bool, error) {
+	previousRevisionId := consentRecord.DataAgreementRevisionId
+	consentRecord.DataAgreementRevisionId = dataAgreementRevision.Id
+	consentRecord.DataAgreementRevisionHash = dataAgreementRevision.SerializedHash
+
+	// Repository
+	darRepo := daRecord.DataAgreementRecordRepository{}
+	darRepo.Init(consentRecord.OrganisationId)
+
+	var updated bool
+	err := database.WithTransaction(func(ctx context.Context) error {
+		var err error
+		updated, err = darRepo.UpdateIfDataAgreementRevisionWithContext(ctx, consentRecord, previousRevisionId)
+		if err != nil || !updated {
+			return err
+		}
+
+		newRevision, err := revision.UpdateRevisionForDataAgreementRecord(consentRecord, consentRecord.IndividualId, dataAgreementRevision)
+		if err != nil {
+			return err
+		}
+		_, err = revision.AddWithContext(ctx, newRevision)
+		return err
+	})
+	return updated, err
+}
+
+// requireRenewal Marks the consent record for renewal, the record, revision,
+// history and webhook event are saved in a transaction
+func requireRenewal(consentRecord daRecord.DataAgreementRecord, signedRevision revision.Revision, version string) (bool, error) {
+	consentRecord.State = config.RenewalRequired
+
+	// Repository
+	darRepo := daRecord.DataAgreementRecordRepository{}
+	darRepo.Init(consentRecord.OrganisationId)
+
+	var updated bool
+	err := database.WithTransaction(func(ctx context.Context) error {
+		var err error
+		updated, err = darRepo.UpdateIfDataAgreementRevisionWithContext(ctx, consentRecord, consentRecord.DataAgreementRevisionId)
+		if err != nil || !updated {
+			return err
+		}
+
+		// Consent record still points to the revision it was signed for
+		newRevision, err := revision.UpdateRevisionForDataAgreementRecord(consentRecord, consentRecord.IndividualId, signedRevision)
+		if err != nil {
+			return err
+		}
+		_, err = revision.AddWithContext(ctx, newRevision)
+		if err != nil {
+			return err
+		}
+
+		_, err = outbox.AddConsentRecordEvent(ctx, consentRecord, consentRecord.OrganisationId, webhook.EventTypes[webhook.EventTypeConsentRenewalRequired])
+		if err != nil {
+			return err
+		}
+
+		darH := daRecordHistory.DataAgreementRecordsHistory{}
+		darH.DataAgreementId = consentRecord.DataAgreementId
+		darH.OrganisationId = consentRecord.OrganisationId
+		darH.ConsentRecordId = consentRecord.Id
+		darH.IndividualId = consentRecord.IndividualId
+		return daRecordHistory.DataAgreementRecordHistoryAddRenewalRequiredWithContext(ctx, darH, version)
+	})
+	return updated, err
+}
+
+// ProcessNewVersion Processes the consent records of older versions when a
+// new version of data agreement is published. Records for versions compatible
+// with the new version, and records which are opted out, are carried forward
+// to the new version. Opted in records for incompatible versions are marked
+// for renewal so that the individual is prompted to consent again.
+func ProcessNewVersion(dataAgreement dataagreement.DataAgreement, dataAgreementRevision revision.Revision) {
+	versions, err := getDataAgreementVersions(dataAgreement.Id)
+	if err != nil {
+		log.Printf("Failed to fetch versions of data agreement:%v for consent renewal: %v", dataAgreement.Id, err)
+		return
+	}
+	compatible := getCompatibleVersions(dataAgreement, versions)
+
+	// Repository
+	darRepo := daRecord.DataAgreementRecordRepository{}
+	darRepo.Init(dataAgreement.OrganisationId)
+
+	consentRecords, err := darRepo.ListNotOnDataAgreementRevision(dataAgreement.Id, dataAgreementRevision.Id)
+	if err != nil {
+		log.Printf("Failed to list consent records of data agreement:%v for consent renewal: %v", dataAgreement.Id, err)
+		return
+	}
+
+	var carriedForwardCount, renewalRequiredCount int
+	for _, consentRecord := range consentRecords {
+		if !consentRecord.OptIn || isCompatible(consentRecord, compatible, versions) {
+			updated, err := carryForward(consentRecord, dataAgreementRevision)
+			if err != nil {
+				log.Printf("Failed to carry forward consent record:%v to data agreement revision:%v err:%v", consentRecord.Id, dataAgreementRevision.Id, err)
+				continue
+			}
+			if updated {
+				carriedForwardCount++
+			}
+			continue
+		}
+
+		signedVersion, ok := versions[consentRecord.DataAgreementRevisionId]
+		if !ok {
+			log.Printf("Failed to find data agreement revision:%v of consent record:%v for consent renewal", consentRecord.DataAgreementRevisionId, consentRecord.Id)
+			continue
+		}
+		updated, err := requireRenewal(consentRecord, signedVersion.revision, dataAgreement.Version)
+		if err != nil {
+			log.Printf("Failed to mark consent record:%v for renewal err:%v", consentRecord.Id, err)
+			continue
+		}
+		if updated {
+			renewalRequiredCount++
+		}
+	}
+
+	if renewalRequiredCount > 0 {
+		outbox.Notify()
+	}
+	log.Printf("Data agreement:%v version:%v published, %v consent records carried forward and %v consent records require renewal", dataAgreement.Id, dataAgreement.Version, carriedForwardCount, renewalRequiredCount)
+}
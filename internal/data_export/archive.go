@@ -0,0 +1,292 @@
+package dataexport
+
+import (
+	"archive/zip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/bb-consent/api/internal/config"
+	daRecord "github.com/bb-consent/api/internal/dataagreement_record"
+	daRecordHistory "github.com/bb-consent/api/internal/dataagreement_record_history"
+	"github.com/bb-consent/api/internal/individual"
+	"github.com/bb-consent/api/internal/revision"
+	"github.com/bb-consent/api/internal/signature"
+	"github.com/bb-consent/api/internal/signingkey"
+	"github.com/dgrijalva/jwt-go"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Files in the data export archive
+const (
+	individualFile             = "individual.json"
+	consentRecordsFile         = "consent_records.json"
+	consentRecordRevisionsFile = "consent_record_revisions.json"
+	signaturesFile             = "signatures.json"
+	dataAgreementRevisionsFile = "data_agreement_revisions.json"
+	consentRecordHistoryFile   = "consent_record_history.json"
+	jsonLdFile                 = "export.jsonld"
+	manifestFile               = "manifest.json"
+	manifestSignatureFile      = "manifest.jws"
+)
+
+// exportData Data of the individual added to the archive
+type exportData struct {
+	Individual             individual.Individual
+	ConsentRecords         []daRecord.DataAgreementRecord
+	ConsentRecordRevisions []revision.Revision
+	Signatures             []signature.Signature
+	DataAgreementRevisions []revision.Revision
+	ConsentRecordHistory   []daRecordHistory.DataAgreementRecordsHistory
+}
+
+// archiveFile File added to the archive, content is serialised as JSON
+type archiveFile struct {
+	name    string
+	content interface{}
+}
+
+// ManifestFile File in the archive and its SHA-256
+type ManifestFile struct {
+	Name   string `json:"name"`
+	SHA256 string `json:"sha256"`
+}
+
+// Manifest Lists the files in the archive, the manifest is signed so the
+// archive can be verified later
+type Manifest struct {
+	ExportId       string         `json:"exportId"`
+	OrganisationId string         `json:"organisationId"`
+	IndividualId   string         `json:"individualId"`
+	Timestamp      string         `json:"timestamp"`
+	Files          []ManifestFile `json:"files"`
+}
+
+// manifestClaims Claims of the signed manifest
+type manifestClaims struct {
+	Manifest Manifest `json:"manifest"`
+	jwt.StandardClaims
+}
+
+// collectExportData Collects the data held about the individual, including
+// consent records which were deleted
+func collectExportData(organisationId string, individualId string) (exportData, error) {
+	data := exportData{
+		ConsentRecords:         []daRecord.DataAgreementRecord{},
+		ConsentRecordRevisions: []revision.Revision{},
+		Signatures:             []signature.Signature{},
+		DataAgreementRevisions: []revision.Revision{},
+		ConsentRecordHistory:   []daRecordHistory.DataAgreementRecordsHistory{},
+	}
+
+	// Repository
+	individualRepo := individual.IndividualRepository{}
+	individualRepo.Init(organisationId)
+
+	var err error
+	data.Individual, err = individualRepo.Get(individualId)
+	if err != nil {
+		return data, err
+	}
+
+	data.ConsentRecords, err = daRecord.ListAllByIndividualId(organisationId, individualId)
+	if err != nil {
+		return data, err
+	}
+
+	consentRecordIds := []string{}
+
+	// Data agreement revisions referred by the consent records and by every
+	// revision of them, each is added once
+	dataAgreementRevisionIds := []string{}
+	seenDataAgreementRevisionIds := make(map[string]bool)
+	referDataAgreementRevision := func(dataAgreementRevisionId string) {
+		if len(dataAgreementRevisionId) > 0 && !seenDataAgreementRevisionIds[dataAgreementRevisionId] {
+			seenDataAgreementRevisionIds[dataAgreementRevisionId] = true
+			dataAgreementRevisionIds = append(dataAgreementRevisionIds, dataAgreementRevisionId)
+		}
+	}
+
+	for _, consentRecord := range data.ConsentRecords {
+		consentRecordIds = append(consentRecordIds, consentRecord.Id)
+		referDataAgreementRevision(consentRecord.DataAgreementRevisionId)
+
+		revisions, err := revision.ListAllByObjectIdAndSchemaName(consentRecord.Id, config.DataAgreementRecord)
+		if err != nil {
+			return data, err
+		}
+		data.ConsentRecordRevisions = append(data.ConsentRecordRevisions, revisions...)
+
+		for _, consentRecordRevision := range revisions {
+			revisedConsentRecord, err := revision.RecreateConsentRecordFromObjectData(consentRecordRevision.ObjectData)
+			if err != nil {
+				return data, err
+			}
+			referDataAgreementRevision(revisedConsentRecord.DataAgreementRevisionId)
+		}
+
+		if len(consentRecord.SignatureId) > 0 {
+			s, err := signature.Get(consentRecord.SignatureId)
+			if err != nil && err != mongo.ErrNoDocuments {
+				return data, err
+			}
+			if err == nil {
+				data.Signatures = append(data.Signatures, s)
+			}
+		}
+	}
+
+	for _, dataAgreementRevisionId := range dataAgreementRevisionIds {
+		dataAgreementRevision, err := revision.GetByRevisionIdAndSchema(dataAgreementRevisionId, config.DataAgreement)
+		if err != nil && err != mongo.ErrNoDocuments {
+			return data, err
+		}
+		if err == nil {
+			data.DataAgreementRevisions = append(data.DataAgreementRevisions, dataAgreementRevision)
+		}
+	}
+
+	data.ConsentRecordHistory, err = daRecordHistory.ListByConsentRecordIds(organisationId, consentRecordIds)
+	if err != nil {
+		return data, err
+	}
+
+	return data, nil
+}
+
+// toJsonLd Returns the exported data as a JSON-LD document
+func toJsonLd(export DataExport, data exportData) map[string]interface{} {
+	graph := []map[string]interface{}{
+		{
+			"@id":   "urn:individual:" + data.Individual.Id,
+			"@type": "schema:Person",
+			"name":  data.Individual.Name,
+			"email": data.Individual.Email,
+			"phone": data.Individual.Phone,
+		},
+	}
+	for _, consentRecord := range data.ConsentRecords {
+		graph = append(graph, map[string]interface{}{
+			"@id":                   "urn:consent-record:" + consentRecord.Id,
+			"@type":                 "dpv:Consent",
+			"dataSubject":           "urn:individual:" + consentRecord.IndividualId,
+			"dataAgreement":         "urn:data-agreement:" + consentRecord.DataAgreementId,
+			"dataAgreementRevision": "urn:revision:" + consentRecord.DataAgreementRevisionId,
+			"optIn":                 consentRecord.OptIn,
+			"state":                 consentRecord.State,
+		})
+	}
+
+	return map[string]interface{}{
+		"@context": map[string]interface{}{
+			"dpv":    "https://w3id.org/dpv#",
+			"schema": "https://schema.org/",
+			"name":   "schema:name",
+			"email":  "schema:email",
+			"phone":  "schema:telephone",
+			"dataSubject": map[string]string{
+				"@id":   "dpv:hasDataSubject",
+				"@type": "@id",
+			},
+			"dataAgreement": map[string]string{
+				"@id":   "dpv:hasConsentNotice",
+				"@type": "@id",
+			},
+			"dataAgreementRevision": map[string]string{
+				"@id":   "dpv:hasVersion",
+				"@type": "@id",
+			},
+			"optIn": "dpv:hasConsentStatus",
+			"state": "dpv:hasStatus",
+		},
+		"@id":    "urn:data-export:" + export.Id,
+		"@graph": graph,
+	}
+}
+
+// signManifest Signs the manifest, the signed manifest is an ES256 JWT which
+// can be verified with the JWKS of the organisation, `kid` in the header
+// identifies the key it was signed with
+func signManifest(manifest Manifest) (string, error) {
+	claims := manifestClaims{
+		manifest,
+		jwt.StandardClaims{
+			Id:       manifest.ExportId,
+			Subject:  manifest.IndividualId,
+			IssuedAt: time.Now().Unix(),
+		},
+	}
+
+	return signingkey.Sign(manifest.OrganisationId, claims)
+}
+
+// buildArchive Writes the ZIP archive of the exported data along with a
+// signed manifest of the files in it. Files are encoded straight into the
+// archive, so neither the files nor the archive are held in memory.
+func buildArchive(export DataExport, data exportData, w io.Writer) error {
+	files := []archiveFile{
+		{individualFile, data.Individual},
+		{consentRecordsFile, data.ConsentRecords},
+		{consentRecordRevisionsFile, data.ConsentRecordRevisions},
+		{signaturesFile, data.Signatures},
+		{dataAgreementRevisionsFile, data.DataAgreementRevisions},
+		{consentRecordHistoryFile, data.ConsentRecordHistory},
+	}
+	if export.IncludeJsonLd {
+		files = append(files, archiveFile{jsonLdFile, toJsonLd(export, data)})
+	}
+
+	manifest := Manifest{
+		ExportId:       export.Id,
+		OrganisationId: export.OrganisationId,
+		IndividualId:   export.IndividualId,
+		Timestamp:      time.Now().UTC().Format("2006-01-02T15:04:05Z"),
+		Files:          []ManifestFile{},
+	}
+
+	zipWriter := zip.NewWriter(w)
+
+	// addFile Adds the file to the archive and returns its SHA-256
+	addFile := func(name string, content interface{}) (string, error) {
+		f, err := zipWriter.Create(name)
+		if err != nil {
+			return "", err
+		}
+
+		hash := sha256.New()
+		encoder := json.NewEncoder(io.MultiWriter(f, hash))
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(content); err != nil {
+			return "", err
+		}
+		return hex.EncodeToString(hash.Sum(nil)), nil
+	}
+
+	for _, file := range files {
+		hash, err := addFile(file.name, file.content)
+		if err != nil {
+			return err
+		}
+		manifest.Files = append(manifest.Files, ManifestFile{Name: file.name, SHA256: hash})
+	}
+
+	if _, err := addFile(manifestFile, manifest); err != nil {
+		return err
+	}
+
+	signedManifest, err := signManifest(manifest)
+	if err != nil {
+		return err
+	}
+	f, err := zipWriter.Create(manifestSignatureFile)
+	if err != nil {
+		return err
+	}
+	if _, err := io.WriteString(f, signedManifest); err != nil {
+		return err
+	}
+
+	return zipWriter.Close()
+}
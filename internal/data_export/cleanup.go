@@ -0,0 +1,48 @@
+package dataexport
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// Interval at which expired archives are deleted and interrupted exports are failed
+const cleanupInterval = time.Hour
+
+var startCleanupOnce sync.Once
+
+// cleanup Deletes the expired data exports and marks the interrupted data
+// exports of all organisations as failed
+func cleanup() {
+	count, err := deleteExpired()
+	if err != nil {
+		log.Printf("Failed to delete expired data exports: %v", err)
+	} else if count > 0 {
+		log.Printf("Deleted %v expired data exports", count)
+	}
+
+	if err := failInterrupted(bson.M{}); err != nil {
+		log.Printf("Failed to mark interrupted data exports as failed: %v", err)
+	}
+}
+
+// runCleanup Cleans up data exports at interval
+func runCleanup() {
+	ticker := time.NewTicker(cleanupInterval)
+	defer ticker.Stop()
+
+	for {
+		cleanup()
+		<-ticker.C
+	}
+}
+
+// StartCleanup Starts the job deleting expired data exports
+func StartCleanup() {
+	startCleanupOnce.Do(func() {
+		go runCleanup()
+		log.Println("Started data export cleanup job")
+	})
+}
@@ -0,0 +1,231 @@
+package dataexport
+
+import (
+	"context"
+	"time"
+
+	"github.com/bb-consent/api/internal/common"
+	"github.com/bb-consent/api/internal/database"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/gridfs"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Data export status const
+const (
+	StatusPending   = "pending"
+	StatusRunning   = "running"
+	StatusCompleted = "completed"
+	StatusFailed    = "failed"
+)
+
+// Number of days a completed export can be downloaded
+const archiveRetentionDays = 7
+
+// Exports which haven't completed within the timeout are considered interrupted
+const exportTimeout = time.Hour
+
+// DataExport Details of an export of the data of an individual
+type DataExport struct {
+	Id                 string `json:"id" bson:"_id,omitempty"` // Data export ID
+	OrganisationId     string `json:"-"`
+	IndividualId       string `json:"individualId"`      // ID of the individual whose data is exported
+	UserId             string `json:"userId"`            // ID of user who requested the export
+	IncludeJsonLd      bool   `json:"includeJsonLd"`     // Add a JSON-LD document to the archive
+	Status             string `json:"status"`            // Status of the export for e.g. running or completed
+	StatusDescription  string `json:"statusDescription"` // Describe the status for e.g. Reason for failure
+	ArchiveHash        string `json:"archiveHash"`       // SHA-256 of the archive
+	ArchiveSize        int64  `json:"archiveSize"`       // Size of the archive in bytes
+	DownloadUrl        string `json:"downloadUrl,omitempty" bson:"-"`
+	TimeStamp          string `json:"timestamp"`          // UTC timestamp when the export was requested
+	StartedTimeStamp   string `json:"startedTimestamp"`   // UTC timestamp when the export started
+	CompletedTimeStamp string `json:"completedTimestamp"` // UTC timestamp when the export completed
+	ExpiresTimeStamp   string `json:"expiresTimestamp"`   // UTC timestamp after which the archive can't be downloaded
+}
+
+// IsExpired Checks if the archive of the export can't be downloaded anymore
+func (export DataExport) IsExpired() bool {
+	return len(export.ExpiresTimeStamp) > 0 && export.ExpiresTimeStamp <= time.Now().UTC().Format("2006-01-02T15:04:05Z")
+}
+
+func Collection() *mongo.Collection {
+	return database.DB.Client.Database(database.DB.Name).Collection("dataExports")
+}
+
+// archiveBucket GridFS bucket archives of data exports are streamed into, the
+// archive is stored with the data export ID as file ID so reading the status
+// doesn't load the archive. Bucket isn't safe for concurrent use, so a bucket
+// is created for each archive read or written.
+func archiveBucket() (*gridfs.Bucket, error) {
+	return gridfs.NewBucket(database.DB.Client.Database(database.DB.Name), options.GridFSBucket().SetName("dataExportArchives"))
+}
+
+type DataExportRepository struct {
+	DefaultFilter bson.M
+}
+
+// Init
+func (exportRepo *DataExportRepository) Init(organisationId string) {
+	exportRepo.DefaultFilter = bson.M{"organisationid": organisationId}
+}
+
+// Add Adds a data export
+func (exportRepo *DataExportRepository) Add(export DataExport) (DataExport, error) {
+	_, err := Collection().InsertOne(context.TODO(), &export)
+	return export, err
+}
+
+// Get Gets a data export of the individual by ID
+func (exportRepo *DataExportRepository) Get(individualId string, exportId string) (DataExport, error) {
+	var result DataExport
+
+	filter := bson.M{"organisationid": exportRepo.DefaultFilter["organisationid"], "_id": exportId, "individualid": individualId}
+	err := Collection().FindOne(context.TODO(), filter).Decode(&result)
+
+	return result, err
+}
+
+// GetInProgress Gets the data export of the individual which is yet to
+// complete. Exports requested before the timeout are ignored, since they
+// were interrupted for e.g. by a restart.
+func (exportRepo *DataExportRepository) GetInProgress(individualId string) (DataExport, error) {
+	var result DataExport
+
+	cutoffTimestamp := time.Now().UTC().Add(-exportTimeout).Format("2006-01-02T15:04:05Z")
+	filter := bson.M{
+		"organisationid": exportRepo.DefaultFilter["organisationid"],
+		"individualid":   individualId,
+		"status":         bson.M{"$in": []string{StatusPending, StatusRunning}},
+		"timestamp":      bson.M{"$gte": cutoffTimestamp},
+	}
+	err := Collection().FindOne(context.TODO(), filter).Decode(&result)
+
+	return result, err
+}
+
+// FailInterrupted Marks the data exports of the organisation which haven't
+// completed within the timeout as failed
+func (exportRepo *DataExportRepository) FailInterrupted() error {
+	return failInterrupted(exportRepo.DefaultFilter)
+}
+
+// failInterrupted Marks the data exports matching the filter which haven't
+// completed within the timeout as failed, they were interrupted for e.g. by
+// a restart and won't complete
+func failInterrupted(filter bson.M) error {
+	now := time.Now().UTC()
+	cutoffTimestamp := now.Add(-exportTimeout).Format("2006-01-02T15:04:05Z")
+
+	filter = common.CombineFilters(bson.M{
+		"status":    bson.M{"$in": []string{StatusPending, StatusRunning}},
+		"timestamp": bson.M{"$lt": cutoffTimestamp},
+	}, filter)
+	update := bson.M{"$set": bson.M{
+		"status":             StatusFailed,
+		"statusdescription":  "Data export was interrupted, please request a new data export",
+		"completedtimestamp": now.Format("2006-01-02T15:04:05Z"),
+	}}
+
+	_, err := Collection().UpdateMany(context.TODO(), filter, update)
+	return err
+}
+
+// deleteExpired Deletes the expired data exports of all organisations and
+// their archives
+func deleteExpired() (int, error) {
+	filter := bson.M{"expirestimestamp": bson.M{"$gt": "", "$lte": time.Now().UTC().Format("2006-01-02T15:04:05Z")}}
+
+	cursor, err := Collection().Find(context.TODO(), filter)
+	if err != nil {
+		return 0, err
+	}
+	defer cursor.Close(context.TODO())
+
+	var exports []DataExport
+	if err := cursor.All(context.TODO(), &exports); err != nil {
+		return 0, err
+	}
+	if len(exports) == 0 {
+		return 0, nil
+	}
+
+	exportIds := make([]string, len(exports))
+	for i, export := range exports {
+		exportIds[i] = export.Id
+	}
+
+	err = deleteArchivesWithContext(context.TODO(), exportIds)
+	if err != nil {
+		return 0, err
+	}
+	_, err = Collection().DeleteMany(context.TODO(), bson.M{"_id": bson.M{"$in": exportIds}})
+	if err != nil {
+		return 0, err
+	}
+	return len(exportIds), nil
+}
+
+// OpenArchive Opens the archive of a data export for reading, the archive is
+// streamed from GridFS in chunks
+func OpenArchive(exportId string) (*gridfs.DownloadStream, error) {
+	bucket, err := archiveBucket()
+	if err != nil {
+		return nil, err
+	}
+	return bucket.OpenDownloadStream(exportId)
+}
+
+// updateDataExport Updates a data export
+func updateDataExport(export DataExport) error {
+	_, err := Collection().ReplaceOne(context.TODO(), bson.M{"_id": export.Id}, export)
+	return err
+}
+
+// deleteArchivesWithContext Deletes the archives of the data exports, the
+// files and chunks are deleted with the context so it can be used in
+// transactions
+func deleteArchivesWithContext(ctx context.Context, exportIds []string) error {
+	bucket, err := archiveBucket()
+	if err != nil {
+		return err
+	}
+
+	_, err = bucket.GetFilesCollection().DeleteMany(ctx, bson.M{"_id": bson.M{"$in": exportIds}})
+	if err != nil {
+		return err
+	}
+	_, err = bucket.GetChunksCollection().DeleteMany(ctx, bson.M{"files_id": bson.M{"$in": exportIds}})
+	return err
+}
+
+// DeleteByIndividualIdWithContext Deletes the data exports of the individual
+// and their archives, context is used for transactions
+func DeleteByIndividualIdWithContext(ctx context.Context, organisationId string, individualId string) (int64, error) {
+	var exports []DataExport
+
+	cursor, err := Collection().Find(ctx, bson.M{"organisationid": organisationId, "individualid": individualId})
+	if err != nil {
+		return 0, err
+	}
+	defer cursor.Close(ctx)
+
+	if err := cursor.All(ctx, &exports); err != nil {
+		return 0, err
+	}
+
+	exportIds := []string{}
+	for _, export := range exports {
+		exportIds = append(exportIds, export.Id)
+	}
+
+	err = deleteArchivesWithContext(ctx, exportIds)
+	if err != nil {
+		return 0, err
+	}
+	result, err := Collection().DeleteMany(ctx, bson.M{"_id": bson.M{"$in": exportIds}})
+	if err != nil {
+		return 0, err
+	}
+	return result.DeletedCount, nil
+}
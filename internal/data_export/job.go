@@ -0,0 +1,96 @@
+package dataexport
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log"
+	"time"
+
+	daRecord "github.com/bb-consent/api/internal/dataagreement_record"
+)
+
+// RunDataExport Builds the archive of the data of the individual and saves it
+func RunDataExport(export DataExport) DataExport {
+	export.Status = StatusRunning
+	export.StartedTimeStamp = time.Now().UTC().Format("2006-01-02T15:04:05Z")
+	if err := updateDataExport(export); err != nil {
+		log.Printf("Failed to update data export:%v err:%v", export.Id, err)
+	}
+
+	data, err := collectExportData(export.OrganisationId, export.IndividualId)
+	if err != nil {
+		return dataExportFailed(export, err)
+	}
+
+	bucket, err := archiveBucket()
+	if err != nil {
+		return dataExportFailed(export, err)
+	}
+	uploadStream, err := bucket.OpenUploadStreamWithID(export.Id, fmt.Sprintf("data-export-%v.zip", export.Id))
+	if err != nil {
+		return dataExportFailed(export, err)
+	}
+
+	// Archive is streamed into GridFS while its hash and size are computed
+	hash := sha256.New()
+	size := &sizeWriter{}
+	err = buildArchive(export, data, io.MultiWriter(uploadStream, hash, size))
+	if err != nil {
+		uploadStream.Abort()
+		return dataExportFailed(export, err)
+	}
+	if err := uploadStream.Close(); err != nil {
+		return dataExportFailed(export, err)
+	}
+
+	now := time.Now().UTC()
+
+	export.Status = StatusCompleted
+	export.ArchiveHash = hex.EncodeToString(hash.Sum(nil))
+	export.ArchiveSize = size.size
+	export.CompletedTimeStamp = now.Format("2006-01-02T15:04:05Z")
+	export.ExpiresTimeStamp = now.AddDate(0, 0, archiveRetentionDays).Format("2006-01-02T15:04:05Z")
+	if err := updateDataExport(export); err != nil {
+		log.Printf("Failed to update data export:%v err:%v", export.Id, err)
+	}
+
+	return export
+}
+
+// sizeWriter Counts the bytes written to it
+type sizeWriter struct {
+	size int64
+}
+
+func (w *sizeWriter) Write(p []byte) (int, error) {
+	w.size += int64(len(p))
+	return len(p), nil
+}
+
+// dataExportFailed Marks the data export as failed
+func dataExportFailed(export DataExport, err error) DataExport {
+	log.Printf("Data export:%v failed err:%v", export.Id, err)
+
+	export.Status = StatusFailed
+	export.StatusDescription = err.Error()
+	export.CompletedTimeStamp = time.Now().UTC().Format("2006-01-02T15:04:05Z")
+	if err := updateDataExport(export); err != nil {
+		log.Printf("Failed to update data export:%v err:%v", export.Id, err)
+	}
+
+	return export
+}
+
+// Exports of individuals with more consent records are run in background
+const maxConsentRecordsForSyncExport = 50
+
+// IsLarge Checks if the export of the data of the individual should be run in background
+func IsLarge(organisationId string, individualId string) (bool, error) {
+	count, err := daRecord.CountAllByIndividualId(organisationId, individualId)
+	if err != nil {
+		return false, err
+	}
+	return count > maxConsentRecordsForSyncExport, nil
+}
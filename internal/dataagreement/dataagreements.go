@@ -385,3 +385,21 @@ func (darepo *DataAgreementRepository) CountDocumentsByPurposeExeptOneDataAgreem
 	}
 	return exists, nil
 }
+
+// ListWithDataRetentionPeriod Lists the data agreements across organisations
+// whose policy has a data retention period
+func ListWithDataRetentionPeriod() ([]DataAgreement, error) {
+	results := []DataAgreement{}
+
+	filter := bson.M{"isdeleted": false, "policy.dataretentionperioddays": bson.M{"$gt": 0}}
+	cursor, err := Collection().Find(context.TODO(), filter)
+	if err != nil {
+		return results, err
+	}
+	defer cursor.Close(context.TODO())
+
+	if err := cursor.All(context.TODO(), &results); err != nil {
+		return results, err
+	}
+	return results, nil
+}
@@ -14,13 +14,15 @@ type DataAgreementRecord struct {
 	OptIn                     bool   `json:"optIn"`
 	State                     string `json:"state" valid:"required"`
 	SignatureId               string `json:"signatureId"`
+	AuthorizedByIndividualId  string `json:"authorizedByIndividualId"` // Guardian who last updated the record on behalf of the individual
 	OrganisationId            string `json:"-"`
 	IsDeleted                 bool   `json:"-"`
 }
 
 type RevisionForListDataAgreementRecord struct {
-	ObjectData string
-	Timestamp  string `json:"timestamp"`
+	ObjectData               string
+	Timestamp                string `json:"timestamp"`
+	AuthorizedByIndividualId string `json:"authorizedByIndividualId"`
 }
 
 type DataAgreementRecordForAuditList struct {
@@ -26,8 +26,13 @@ func (darRepo *DataAgreementRecordRepository) Init(organisationId string) {
 
 // Add Adds the data agreement record to the db
 func (darRepo *DataAgreementRecordRepository) Add(dataAgreementRecord DataAgreementRecord) (DataAgreementRecord, error) {
+	return darRepo.AddWithContext(context.TODO(), dataAgreementRecord)
+}
+
+// AddWithContext Adds the data agreement record to the db, context is used for transactions
+func (darRepo *DataAgreementRecordRepository) AddWithContext(ctx context.Context, dataAgreementRecord DataAgreementRecord) (DataAgreementRecord, error) {
 
-	_, err := Collection().InsertOne(context.TODO(), dataAgreementRecord)
+	_, err := Collection().InsertOne(ctx, dataAgreementRecord)
 	if err != nil {
 		return DataAgreementRecord{}, err
 	}
@@ -48,17 +53,95 @@ func (darRepo *DataAgreementRecordRepository) Get(dataAgreementRecordId string)
 
 // Update Updates the data agreement record
 func (darRepo *DataAgreementRecordRepository) Update(dataAgreementRecord DataAgreementRecord) (DataAgreementRecord, error) {
+	return darRepo.UpdateWithContext(context.TODO(), dataAgreementRecord)
+}
+
+// UpdateWithContext Updates the data agreement record, context is used for transactions
+func (darRepo *DataAgreementRecordRepository) UpdateWithContext(ctx context.Context, dataAgreementRecord DataAgreementRecord) (DataAgreementRecord, error) {
 
 	filter := common.CombineFilters(darRepo.DefaultFilter, bson.M{"_id": dataAgreementRecord.Id})
 	update := bson.M{"$set": dataAgreementRecord}
 
-	_, err := Collection().UpdateOne(context.TODO(), filter, update)
+	_, err := Collection().UpdateOne(ctx, filter, update)
 	if err != nil {
 		return dataAgreementRecord, err
 	}
 	return dataAgreementRecord, err
 }
 
+// ExpireWithContext Marks the data agreement record as expired if it isn't
+// already, returns false if the record was expired by someone else
+func (darRepo *DataAgreementRecordRepository) ExpireWithContext(ctx context.Context, dataAgreementRecordId string) (bool, error) {
+	filter := common.CombineFilters(darRepo.DefaultFilter, bson.M{"_id": dataAgreementRecordId, "optin": true, "state": bson.M{"$ne": config.Expired}})
+	update := bson.M{"$set": bson.M{"optin": false, "state": config.Expired}}
+
+	result, err := Collection().UpdateOne(ctx, filter, update)
+	if err != nil {
+		return false, err
+	}
+	return result.ModifiedCount > 0, nil
+}
+
+// UpdateIfDataAgreementRevisionWithContext Updates the state and data
+// agreement revision of the data agreement record if it still points to the
+// data agreement revision, returns false if the record was updated by someone
+// else
+func (darRepo *DataAgreementRecordRepository) UpdateIfDataAgreementRevisionWithContext(ctx context.Context, dataAgreementRecord DataAgreementRecord, dataAgreementRevisionId string) (bool, error) {
+	filter := common.CombineFilters(darRepo.DefaultFilter, bson.M{"_id": dataAgreementRecord.Id, "dataagreementrevisionid": dataAgreementRevisionId, "state": bson.M{"$nin": []string{config.Expired, config.RenewalRequired}}})
+	update := bson.M{"$set": bson.M{
+		"state":                     dataAgreementRecord.State,
+		"dataagreementrevisionid":   dataAgreementRecord.DataAgreementRevisionId,
+		"dataagreementrevisionhash": dataAgreementRecord.DataAgreementRevisionHash,
+	}}
+
+	result, err := Collection().UpdateOne(ctx, filter, update)
+	if err != nil {
+		return false, err
+	}
+	return result.ModifiedCount > 0, nil
+}
+
+// ListNotOnDataAgreementRevision Lists the data agreement records of the data
+// agreement given for other revisions, expired records and records to be
+// renewed are skipped
+func (darRepo *DataAgreementRecordRepository) ListNotOnDataAgreementRevision(dataAgreementId string, dataAgreementRevisionId string) ([]DataAgreementRecord, error) {
+	results := []DataAgreementRecord{}
+
+	filter := common.CombineFilters(darRepo.DefaultFilter, bson.M{
+		"dataagreementid":         dataAgreementId,
+		"dataagreementrevisionid": bson.M{"$ne": dataAgreementRevisionId},
+		"state":                   bson.M{"$nin": []string{config.Expired, config.RenewalRequired}},
+	})
+	cursor, err := Collection().Find(context.TODO(), filter)
+	if err != nil {
+		return results, err
+	}
+	defer cursor.Close(context.TODO())
+
+	if err := cursor.All(context.TODO(), &results); err != nil {
+		return results, err
+	}
+	return results, nil
+}
+
+// ListExpiryCandidates Lists the opted in data agreement records of the data
+// agreement which aren't expired
+func (darRepo *DataAgreementRecordRepository) ListExpiryCandidates(dataAgreementId string) ([]DataAgreementRecord, error) {
+	results := []DataAgreementRecord{}
+
+	filter := common.CombineFilters(darRepo.DefaultFilter, bson.M{"dataagreementid": dataAgreementId, "optin": true, "state": bson.M{"$ne": config.Expired}})
+	cursor, err := Collection().Find(context.TODO(), filter)
+	if err != nil {
+		return results, err
+	}
+	defer cursor.Close(context.TODO())
+
+	if err := cursor.All(context.TODO(), &results); err != nil {
+		return results, err
+	}
+	return results, nil
+}
+
 // Get Gets a single data agreement record by data agreement id and individual id
 func (darRepo *DataAgreementRecordRepository) GetByDataAgreementIdandIndividualId(dataAgreementId string, individualId string) (DataAgreementRecord, error) {
 
@@ -332,3 +415,35 @@ func GetAllUsingPipeline(pipeline []bson.M) ([]DataAgreementRecord, error) {
 
 	return results, nil
 }
+
+// ListAllByIndividualId Lists all the data agreement records of the
+// individual including the deleted records
+func ListAllByIndividualId(organisationId string, individualId string) ([]DataAgreementRecord, error) {
+	results := []DataAgreementRecord{}
+
+	cursor, err := Collection().Find(context.TODO(), bson.M{"organisationid": organisationId, "individualid": individualId})
+	if err != nil {
+		return results, err
+	}
+	defer cursor.Close(context.TODO())
+
+	if err := cursor.All(context.TODO(), &results); err != nil {
+		return results, err
+	}
+	return results, nil
+}
+
+// DeleteByIdsWithContext Deletes the data agreement records, context is used for transactions
+func DeleteByIdsWithContext(ctx context.Context, dataAgreementRecordIds []string) (int64, error) {
+	result, err := Collection().DeleteMany(ctx, bson.M{"_id": bson.M{"$in": dataAgreementRecordIds}})
+	if err != nil {
+		return 0, err
+	}
+	return result.DeletedCount, nil
+}
+
+// CountAllByIndividualId Counts all the data agreement records of the
+// individual including the deleted records
+func CountAllByIndividualId(organisationId string, individualId string) (int64, error) {
+	return Collection().CountDocuments(context.TODO(), bson.M{"organisationid": organisationId, "individualid": individualId})
+}
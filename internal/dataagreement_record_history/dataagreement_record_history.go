@@ -1,6 +1,7 @@
 package dataagreementrecordhistory
 
 import (
+	"context"
 	"fmt"
 	"log"
 
@@ -11,16 +12,22 @@ import (
 
 // DataAgreementRecordsHistory
 type DataAgreementRecordsHistory struct {
-	Id              string `json:"id" bson:"_id,omitempty"`
-	OrganisationId  string `json:"organisationId"`
-	DataAgreementId string `json:"dataAgreementId"`
-	Log             string `json:"log"`
-	Timestamp       string `json:"timestamp"`
-	ConsentRecordId string `json:"consentRecordId"`
-	IndividualId    string `json:"individualId"`
+	Id                       string `json:"id" bson:"_id,omitempty"`
+	OrganisationId           string `json:"organisationId"`
+	DataAgreementId          string `json:"dataAgreementId"`
+	Log                      string `json:"log"`
+	Timestamp                string `json:"timestamp"`
+	ConsentRecordId          string `json:"consentRecordId"`
+	IndividualId             string `json:"individualId"`
+	AuthorizedByIndividualId string `json:"authorizedByIndividualId"` // Guardian who updated the consent on behalf of the individual
 }
 
 func DataAgreementRecordHistoryAdd(darH DataAgreementRecordsHistory, optIn bool) error {
+	return DataAgreementRecordHistoryAddWithContext(context.TODO(), darH, optIn)
+}
+
+// DataAgreementRecordHistoryAddWithContext Adds data agreement record history, context is used for transactions
+func DataAgreementRecordHistoryAddWithContext(ctx context.Context, darH DataAgreementRecordsHistory, optIn bool) error {
 	o, err := org.Get(darH.OrganisationId)
 	if err != nil {
 		return err
@@ -43,14 +50,67 @@ func DataAgreementRecordHistoryAdd(darH DataAgreementRecordsHistory, optIn bool)
 		darH.Log = fmt.Sprintf("Updated consent value to <%s> for the purpose <%s> in organization <%s>",
 			value, dataAgreement.Purpose, o.Name)
 	}
+	if len(darH.AuthorizedByIndividualId) > 0 {
+		darH.Log = fmt.Sprintf("%s by guardian <%s>", darH.Log, darH.AuthorizedByIndividualId)
+	}
 	log.Printf("The log is: %s", darH.Log)
 
 	darH.Id = primitive.NewObjectID().Hex()
 
-	_, err = Add(darH)
+	_, err = AddWithContext(ctx, darH)
 	if err != nil {
 		return err
 	}
 	return nil
 
 }
+
+// getPurposeAndOrganisationName Returns the purpose of data agreement and
+// name of organisation for the history log
+func getPurposeAndOrganisationName(darH DataAgreementRecordsHistory) (string, string, error) {
+	o, err := org.Get(darH.OrganisationId)
+	if err != nil {
+		return "", "", err
+	}
+	// Repository
+	darepo := dataagreement.DataAgreementRepository{}
+	darepo.Init(darH.OrganisationId)
+
+	dataAgreement, err := darepo.Get(darH.DataAgreementId)
+	if err != nil {
+		return "", "", err
+	}
+	return dataAgreement.Purpose, o.Name, nil
+}
+
+// DataAgreementRecordHistoryAddExpiryWithContext Adds data agreement record
+// history for consent expired after the data retention period
+func DataAgreementRecordHistoryAddExpiryWithContext(ctx context.Context, darH DataAgreementRecordsHistory, dataRetentionPeriodDays int) error {
+	purpose, organisationName, err := getPurposeAndOrganisationName(darH)
+	if err != nil {
+		return err
+	}
+
+	darH.Log = fmt.Sprintf("Consent expired after data retention period of <%d> days for the purpose <%s> in organization <%s>",
+		dataRetentionPeriodDays, purpose, organisationName)
+	darH.Id = primitive.NewObjectID().Hex()
+
+	_, err = AddWithContext(ctx, darH)
+	return err
+}
+
+// DataAgreementRecordHistoryAddRenewalRequiredWithContext Adds data agreement
+// record history for consent to be renewed for the new version of data agreement
+func DataAgreementRecordHistoryAddRenewalRequiredWithContext(ctx context.Context, darH DataAgreementRecordsHistory, version string) error {
+	purpose, organisationName, err := getPurposeAndOrganisationName(darH)
+	if err != nil {
+		return err
+	}
+
+	darH.Log = fmt.Sprintf("Consent renewal required for version <%s> of the purpose <%s> in organization <%s>",
+		version, purpose, organisationName)
+	darH.Id = primitive.NewObjectID().Hex()
+
+	_, err = AddWithContext(ctx, darH)
+	return err
+}
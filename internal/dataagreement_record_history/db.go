@@ -5,7 +5,9 @@ import (
 	"time"
 
 	"github.com/bb-consent/api/internal/database"
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 func Collection() *mongo.Collection {
@@ -14,12 +16,43 @@ func Collection() *mongo.Collection {
 
 // Add Adds the Data Agreement Records History to the db
 func Add(dataAgreementRecordsHistory DataAgreementRecordsHistory) (DataAgreementRecordsHistory, error) {
+	return AddWithContext(context.TODO(), dataAgreementRecordsHistory)
+}
+
+// AddWithContext Adds the Data Agreement Records History to the db, context is used for transactions
+func AddWithContext(ctx context.Context, dataAgreementRecordsHistory DataAgreementRecordsHistory) (DataAgreementRecordsHistory, error) {
 
 	dataAgreementRecordsHistory.Timestamp = time.Now().UTC().Format("2006-01-02T15:04:05Z")
-	_, err := Collection().InsertOne(context.TODO(), dataAgreementRecordsHistory)
+	_, err := Collection().InsertOne(ctx, dataAgreementRecordsHistory)
 	if err != nil {
 		return DataAgreementRecordsHistory{}, err
 	}
 
 	return dataAgreementRecordsHistory, nil
 }
+
+// DeleteByConsentRecordIdsWithContext Deletes the history of the consent records, context is used for transactions
+func DeleteByConsentRecordIdsWithContext(ctx context.Context, organisationId string, consentRecordIds []string) (int64, error) {
+	result, err := Collection().DeleteMany(ctx, bson.M{"organisationid": organisationId, "consentrecordid": bson.M{"$in": consentRecordIds}})
+	if err != nil {
+		return 0, err
+	}
+	return result.DeletedCount, nil
+}
+
+// ListByConsentRecordIds Lists the history of the consent records, oldest first
+func ListByConsentRecordIds(organisationId string, consentRecordIds []string) ([]DataAgreementRecordsHistory, error) {
+	results := []DataAgreementRecordsHistory{}
+
+	opts := options.Find().SetSort(bson.M{"timestamp": 1})
+	cursor, err := Collection().Find(context.TODO(), bson.M{"organisationid": organisationId, "consentrecordid": bson.M{"$in": consentRecordIds}}, opts)
+	if err != nil {
+		return results, err
+	}
+	defer cursor.Close(context.TODO())
+
+	if err := cursor.All(context.TODO(), &results); err != nil {
+		return results, err
+	}
+	return results, nil
+}
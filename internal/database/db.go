@@ -46,6 +46,8 @@ func Init(config *config.Configuration) error {
 		Name:   config.DataBase.Name,
 	}
 
+	initTransactions(ctx)
+
 	err = initCollection("organizations", []string{"name"}, true)
 	if err != nil {
 		log.Printf("initialising collection: %v", err)
@@ -122,6 +124,25 @@ func Init(config *config.Configuration) error {
 		return err
 	}
 
+	// Deliveries which are due are claimed by the workers
+	err = initCollection("webhookDeliveries", []string{"status", "nextattempttimestamp"}, false)
+	if err != nil {
+		return err
+	}
+
+	// Deliveries of a webhook are listed and counted
+	err = initCollection("webhookDeliveries", []string{"webhookid", "executionstarttimestamp"}, false)
+	if err != nil {
+		return err
+	}
+
+	// Events are queued once for a webhook, index is sparse so deliveries
+	// without the key aren't indexed
+	err = initCollection("webhookDeliveries", []string{"idempotencykey"}, true)
+	if err != nil {
+		return err
+	}
+
 	err = initCollection("policies", []string{"id"}, true)
 	if err != nil {
 		return err
@@ -132,6 +153,12 @@ func Init(config *config.Configuration) error {
 		return err
 	}
 
+	// Latest revisions of objects are looked up in batches
+	err = initCollection("revisions", []string{"objectid", "schemaname", "timestamp"}, false)
+	if err != nil {
+		return err
+	}
+
 	err = initCollection("dataAgreements", []string{"id"}, true)
 	if err != nil {
 		return err
@@ -157,6 +184,12 @@ func Init(config *config.Configuration) error {
 		return err
 	}
 
+	// Api keys are looked up by the hash of the key
+	err = initCollection("apiKeys", []string{"keyhash"}, false)
+	if err != nil {
+		return err
+	}
+
 	err = initCollection("dataAgreementRecords", []string{"id", "dataagreementid", "individualid"}, true)
 	if err != nil {
 		return err
@@ -172,6 +205,63 @@ func Init(config *config.Configuration) error {
 		return err
 	}
 
+	err = initCollection("eventLog", []string{"organisationid", "sequence"}, true)
+	if err != nil {
+		return err
+	}
+
+	// Event log is read in order of sequence across organisations to find gaps
+	err = initCollection("eventLog", []string{"sequence"}, false)
+	if err != nil {
+		return err
+	}
+
+	err = initCollection("outbox", []string{"status", "individualid"}, false)
+	if err != nil {
+		return err
+	}
+
+	err = initCollection("outbox", []string{"status", "nextattempttimestamp"}, false)
+	if err != nil {
+		return err
+	}
+
+	err = initCollection("outbox", []string{"status", "publishedtimestamp"}, false)
+	if err != nil {
+		return err
+	}
+
+	err = initCollection("guardians", []string{"organisationid", "individualid", "guardianindividualid"}, false)
+	if err != nil {
+		return err
+	}
+
+	err = initCollection("erasureReceipts", []string{"organisationid", "individualid"}, false)
+	if err != nil {
+		return err
+	}
+
+	err = initCollection("signingKeys", []string{"organisationid"}, false)
+	if err != nil {
+		return err
+	}
+
+	err = initCollection("dataExports", []string{"organisationid", "individualid", "status"}, false)
+	if err != nil {
+		return err
+	}
+
+	// Data exports are cleaned up across organisations by expiry and status
+	err = initCollection("dataExports", []string{"expirestimestamp"}, false)
+	if err != nil {
+		return err
+	}
+
+	err = initCollection("dataExports", []string{"status", "timestamp"}, false)
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -0,0 +1,50 @@
+package database
+
+import (
+	"context"
+	"log"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// supportsTransactions Transactions are supported only by replica sets and sharded clusters
+var supportsTransactions bool
+
+// initTransactions Checks if the deployment supports transactions
+func initTransactions(ctx context.Context) {
+	var result struct {
+		SetName string `bson:"setName"`
+		Msg     string `bson:"msg"`
+	}
+	err := DB.Client.Database("admin").RunCommand(ctx, bson.D{{Key: "isMaster", Value: 1}}).Decode(&result)
+	if err != nil {
+		log.Printf("Failed to check if database supports transactions: %v", err)
+		return
+	}
+
+	supportsTransactions = len(result.SetName) > 0 || result.Msg == "isdbgrid"
+	if !supportsTransactions {
+		log.Println("Database is a standalone server, writes are not done in transactions")
+	}
+}
+
+// WithTransaction Runs fn in a transaction, db operations in fn must use the
+// context passed to it. fn can be retried on transient errors. For standalone
+// servers, which don't support transactions, fn is run without a transaction.
+func WithTransaction(fn func(ctx context.Context) error) error {
+	if !supportsTransactions {
+		return fn(context.TODO())
+	}
+
+	session, err := DB.Client.StartSession()
+	if err != nil {
+		return err
+	}
+	defer session.EndSession(context.TODO())
+
+	_, err = session.WithTransaction(context.TODO(), func(sessCtx mongo.SessionContext) (interface{}, error) {
+		return nil, fn(sessCtx)
+	})
+	return err
+}
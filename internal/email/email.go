@@ -133,6 +133,120 @@ func SendWelcomeEmail(username string, firstname string, subject string, body st
 
 }
 
+// SendAdminInvitationEmail Send invitation email to organisation admin. Admins who
+// don't have an account yet activate it through the link, admins having an
+// account accept the invitation through it.
+func SendAdminInvitationEmail(username string, organisationName string, activationLink string, existingUser bool, subject string, from string) {
+	auth = smtp.PlainAuth("", SMTPConfig.Username, SMTPConfig.Password, SMTPConfig.Host)
+
+	r := NewRequest([]string{username}, subject, "", from)
+	escapedOrganisationName := template.HTMLEscapeString(organisationName)
+	escapedActivationLink := template.HTMLEscapeString(activationLink)
+
+	instructions := `<p>Please activate your account by setting a password:</p>
+                            <p><a href="` + escapedActivationLink + `">` + escapedActivationLink + `</a></p>`
+	if existingUser {
+		instructions = `<p>Please accept the invitation to join with your existing account:</p>
+                            <p><a href="` + escapedActivationLink + `">` + escapedActivationLink + `</a></p>`
+	}
+
+	emailTemplateString := `<!DOCTYPE html PUBLIC "-//W3C//DTD XHTML 1.0 Transitional//EN"
+        "http://www.w3.org/TR/xhtml1/DTD/xhtml1-transitional.dtd">
+<html>
+<head>
+    <meta name="viewport" content="width=device-width"/>
+    <meta http-equiv="Content-Type" content="text/html; charset=UTF-8"/>
+</head>
+<body>
+
+<table width="100%" border="0" cellspacing="0" cellpadding="0">
+    <tr>
+        <td align="center" style="background-color: #fff;">
+            <table style="width: 100%;" border="0" cellspacing="0" cellpadding="0">
+                <tr>
+                    <td></td>
+                    <td width="600" style="font-size: 16px;">
+                        <p style="font-weight: bold;font-size: 16px;color: #000;">Hi,</p>
+                        <div style="color:#8c8a8a">
+                            <p>You have been invited as an admin of ` + escapedOrganisationName + `.</p>
+                            ` + instructions + `
+                        </div>
+                    </td>
+                    <td></td>
+                </tr>
+            </table>
+        </td>
+    </tr>
+</table>
+
+</body>
+
+</html>`
+
+	_, err := r.SendEmail(emailTemplateString)
+
+	if err != nil {
+		// Sending email failed
+		log.Printf("Failed to send admin invitation email to username<%v> : %v", username, err)
+		return
+	}
+
+}
+
+// SendWebhookDisabledEmail Send email to organisation admin when a webhook is
+// disabled after repeated delivery failures
+func SendWebhookDisabledEmail(username string, organisationName string, payloadURL string, disabledReason string, subject string, from string) {
+	auth = smtp.PlainAuth("", SMTPConfig.Username, SMTPConfig.Password, SMTPConfig.Host)
+
+	r := NewRequest([]string{username}, subject, "", from)
+	escapedOrganisationName := template.HTMLEscapeString(organisationName)
+	escapedPayloadURL := template.HTMLEscapeString(payloadURL)
+	escapedDisabledReason := template.HTMLEscapeString(disabledReason)
+
+	emailTemplateString := `<!DOCTYPE html PUBLIC "-//W3C//DTD XHTML 1.0 Transitional//EN"
+        "http://www.w3.org/TR/xhtml1/DTD/xhtml1-transitional.dtd">
+<html>
+<head>
+    <meta name="viewport" content="width=device-width"/>
+    <meta http-equiv="Content-Type" content="text/html; charset=UTF-8"/>
+</head>
+<body>
+
+<table width="100%" border="0" cellspacing="0" cellpadding="0">
+    <tr>
+        <td align="center" style="background-color: #fff;">
+            <table style="width: 100%;" border="0" cellspacing="0" cellpadding="0">
+                <tr>
+                    <td></td>
+                    <td width="600" style="font-size: 16px;">
+                        <p style="font-weight: bold;font-size: 16px;color: #000;">Hi,</p>
+                        <div style="color:#8c8a8a">
+                            <p>The webhook ` + escapedPayloadURL + ` of ` + escapedOrganisationName + ` has been disabled.</p>
+                            <p>` + escapedDisabledReason + `.</p>
+                            <p>Events are not delivered to the webhook until it is enabled again from the admin dashboard.</p>
+                        </div>
+                    </td>
+                    <td></td>
+                </tr>
+            </table>
+        </td>
+    </tr>
+</table>
+
+</body>
+
+</html>`
+
+	_, err := r.SendEmail(emailTemplateString)
+
+	if err != nil {
+		// Sending email failed
+		log.Printf("Failed to send webhook disabled email to username<%v> : %v", username, err)
+		return
+	}
+
+}
+
 // Request Request struct for constructing payload for sending email
 type Request struct {
 	from    string
@@ -0,0 +1,258 @@
+package erasure
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/Nerzal/gocloak/v13"
+	"github.com/bb-consent/api/internal/actionlog"
+	"github.com/bb-consent/api/internal/config"
+	dataexport "github.com/bb-consent/api/internal/data_export"
+	"github.com/bb-consent/api/internal/dataagreement"
+	daRecord "github.com/bb-consent/api/internal/dataagreement_record"
+	daRecordHistory "github.com/bb-consent/api/internal/dataagreement_record_history"
+	"github.com/bb-consent/api/internal/database"
+	"github.com/bb-consent/api/internal/eventlog"
+	"github.com/bb-consent/api/internal/guardian"
+	"github.com/bb-consent/api/internal/iam"
+	"github.com/bb-consent/api/internal/individual"
+	"github.com/bb-consent/api/internal/outbox"
+	"github.com/bb-consent/api/internal/revision"
+	"github.com/bb-consent/api/internal/signature"
+	"github.com/bb-consent/api/internal/webhook_dispatcher"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const (
+	retainedReason  = "data agreement is not forgettable"
+	tombstoneReason = "personal data removed, kept as the individual retained consent records refer to"
+)
+
+// isForgettable Checks if the data agreement the consent record was given
+// for is forgettable. Data agreement revision the consent record was given
+// for is used, since the data agreement could have changed after that.
+func isForgettable(organisationId string, consentRecord daRecord.DataAgreementRecord) (bool, error) {
+	if len(consentRecord.DataAgreementRevisionId) > 0 {
+		dataAgreementRevision, err := revision.GetByRevisionIdAndSchema(consentRecord.DataAgreementRevisionId, config.DataAgreement)
+		if err == nil {
+			da, err := revision.RecreateDataAgreementFromRevision(dataAgreementRevision)
+			if err != nil {
+				return false, err
+			}
+			return da.Forgettable, nil
+		}
+		if err != mongo.ErrNoDocuments {
+			return false, err
+		}
+	}
+
+	// Fallback to the current data agreement, if it doesn't exist the
+	// consent record is retained
+	daRepo := dataagreement.DataAgreementRepository{}
+	daRepo.Init(organisationId)
+
+	da, err := daRepo.Get(consentRecord.DataAgreementId)
+	if err == mongo.ErrNoDocuments {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return da.Forgettable, nil
+}
+
+// unregisterIamUser Unregisters the individual in iam, an individual which
+// is already removed from iam is considered unregistered
+func unregisterIamUser(iamId string) (bool, error) {
+	if len(iamId) == 0 {
+		return false, nil
+	}
+
+	err := iam.UnregisterIndividual(iamId)
+	if err != nil {
+		var apiErr *gocloak.APIError
+		if errors.As(err, &apiErr) && apiErr.Code == http.StatusNotFound {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// pseudonymise Removes personal data of the individual, the individual is
+// kept as a tombstone the retained consent records refer to
+func pseudonymise(i individual.Individual) individual.Individual {
+	return individual.Individual{
+		Id:             i.Id,
+		OrganisationId: i.OrganisationId,
+		IsDeleted:      true,
+	}
+}
+
+// EraseIndividual Erases the individual and the consent records given for
+// forgettable data agreements. Consent records given for data agreements
+// which aren't forgettable are retained, along with a pseudonymised
+// individual they refer to. Returns the receipt listing what was removed
+// and retained along with the signed receipt, the receipt is signed and
+// stored in the transaction so it isn't lost once the data is removed.
+func EraseIndividual(organisationId string, individualId string, requestedBy string) (Receipt, error) {
+	// Repository
+	individualRepo := individual.IndividualRepository{}
+	individualRepo.Init(organisationId)
+
+	toBeErasedIndividual, err := individualRepo.Get(individualId)
+	if err != nil {
+		return Receipt{}, err
+	}
+
+	consentRecords, err := daRecord.ListAllByIndividualId(organisationId, individualId)
+	if err != nil {
+		return Receipt{}, err
+	}
+
+	receipt := newReceipt(organisationId, individualId, requestedBy)
+
+	forgettableRecordIds := []string{}
+	signatureIds := []string{}
+	retainedDataAgreementIds := []string{}
+	for _, consentRecord := range consentRecords {
+		forgettable, err := isForgettable(organisationId, consentRecord)
+		if err != nil {
+			return Receipt{}, err
+		}
+
+		if !forgettable {
+			retainedDataAgreementIds = append(retainedDataAgreementIds, consentRecord.DataAgreementId)
+			receipt.Retained = append(receipt.Retained, RetainedItem{
+				Type:            ConsentRecord,
+				Id:              consentRecord.Id,
+				DataAgreementId: consentRecord.DataAgreementId,
+				Reason:          retainedReason,
+			})
+			continue
+		}
+
+		forgettableRecordIds = append(forgettableRecordIds, consentRecord.Id)
+		if len(consentRecord.SignatureId) > 0 {
+			signatureIds = append(signatureIds, consentRecord.SignatureId)
+		}
+	}
+
+	// Individual is kept as a tombstone if any consent record is retained
+	keepTombstone := len(receipt.Retained) > 0
+	if keepTombstone {
+		receipt.Retained = append(receipt.Retained, RetainedItem{
+			Type:   Individual,
+			Id:     individualId,
+			Reason: tombstoneReason,
+		})
+	}
+
+	// Iam user is removed first, if it fails nothing is erased and the
+	// request can be retried
+	receipt.IamUserRemoved, err = unregisterIamUser(toBeErasedIndividual.IamId)
+	if err != nil {
+		return Receipt{}, err
+	}
+
+	err = database.WithTransaction(func(ctx context.Context) error {
+		// Receipt is built again if the transaction is retried
+		receipt.Removed = []RemovedItem{}
+
+		count, err := daRecord.DeleteByIdsWithContext(ctx, forgettableRecordIds)
+		if err != nil {
+			return err
+		}
+		receipt.addRemoved(ConsentRecord, count)
+
+		count, err = revision.DeleteByObjectIdsWithContext(ctx, forgettableRecordIds, config.DataAgreementRecord)
+		if err != nil {
+			return err
+		}
+		receipt.addRemoved(Revision, count)
+
+		count, err = signature.DeleteByIdsWithContext(ctx, signatureIds)
+		if err != nil {
+			return err
+		}
+		receipt.addRemoved(Signature, count)
+
+		count, err = daRecordHistory.DeleteByConsentRecordIdsWithContext(ctx, organisationId, forgettableRecordIds)
+		if err != nil {
+			return err
+		}
+		receipt.addRemoved(ConsentRecordHistory, count)
+
+		count, err = outbox.DeleteByConsentRecordIdsWithContext(ctx, organisationId, forgettableRecordIds)
+		if err != nil {
+			return err
+		}
+		receipt.addRemoved(OutboxMessage, count)
+
+		count, err = eventlog.DeleteByIndividualIdWithContext(ctx, organisationId, individualId, retainedDataAgreementIds)
+		if err != nil {
+			return err
+		}
+		receipt.addRemoved(EventLog, count)
+
+		actionLogRepo := actionlog.ActionLogRepository{}
+		actionLogRepo.Init(organisationId)
+		userIds := []string{individualId}
+		if len(toBeErasedIndividual.IamId) > 0 {
+			userIds = append(userIds, toBeErasedIndividual.IamId)
+		}
+		count, err = actionLogRepo.DeleteByUserIdsWithContext(ctx, userIds)
+		if err != nil {
+			return err
+		}
+		receipt.addRemoved(ActionLog, count)
+
+		guardianRepo := guardian.GuardianRepository{}
+		guardianRepo.Init(organisationId)
+		count, err = guardianRepo.DeleteByIndividualIdWithContext(ctx, individualId)
+		if err != nil {
+			return err
+		}
+		receipt.addRemoved(Guardian, count)
+
+		count, err = dataexport.DeleteByIndividualIdWithContext(ctx, organisationId, individualId)
+		if err != nil {
+			return err
+		}
+		receipt.addRemoved(DataExport, count)
+
+		count, err = webhook_dispatcher.DeleteByIndividualIdWithContext(ctx, organisationId, individualId, retainedDataAgreementIds)
+		if err != nil {
+			return err
+		}
+		receipt.addRemoved(WebhookDelivery, count)
+
+		if keepTombstone {
+			_, err = individualRepo.UpdateWithContext(ctx, pseudonymise(toBeErasedIndividual))
+			if err != nil {
+				return err
+			}
+		} else {
+			err = individualRepo.DeleteWithContext(ctx, individualId)
+			if err != nil {
+				return err
+			}
+			receipt.addRemoved(Individual, 1)
+		}
+
+		// Receipt is signed before commit, erasure is rolled back if it
+		// can't be signed
+		receipt.SignedReceipt, err = receipt.Sign()
+		if err != nil {
+			return err
+		}
+		_, err = AddReceiptWithContext(ctx, receipt)
+		return err
+	})
+	if err != nil {
+		return Receipt{}, err
+	}
+
+	return receipt, nil
+}
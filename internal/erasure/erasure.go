@@ -0,0 +1,111 @@
+package erasure
+
+import (
+	"context"
+	"time"
+
+	"github.com/bb-consent/api/internal/database"
+	"github.com/bb-consent/api/internal/signingkey"
+	"github.com/dgrijalva/jwt-go"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func Collection() *mongo.Collection {
+	return database.DB.Client.Database(database.DB.Name).Collection("erasureReceipts")
+}
+
+// Types of data removed or retained on erasure
+const (
+	ConsentRecord        = "consentRecord"
+	ConsentRecordHistory = "consentRecordHistory"
+	Revision             = "revision"
+	Signature            = "signature"
+	EventLog             = "eventLog"
+	OutboxMessage        = "outboxMessage"
+	ActionLog            = "actionLog"
+	Guardian             = "guardian"
+	DataExport           = "dataExport"
+	WebhookDelivery      = "webhookDelivery"
+	Individual           = "individual"
+)
+
+// RemovedItem Number of items of a type removed on erasure
+type RemovedItem struct {
+	Type  string `json:"type"`
+	Count int64  `json:"count"`
+}
+
+// RetainedItem Item retained on erasure and the reason it was retained
+type RetainedItem struct {
+	Type            string `json:"type"`
+	Id              string `json:"id"`
+	DataAgreementId string `json:"dataAgreementId,omitempty"`
+	Reason          string `json:"reason"`
+}
+
+// Receipt Receipt of an erasure request, lists what was removed and retained
+type Receipt struct {
+	Id             string         `json:"id" bson:"_id,omitempty"`
+	OrganisationId string         `json:"organisationId"`
+	IndividualId   string         `json:"individualId"`
+	RequestedBy    string         `json:"requestedBy"`
+	Timestamp      string         `json:"timestamp"`
+	IamUserRemoved bool           `json:"iamUserRemoved"`
+	Removed        []RemovedItem  `json:"removed"`
+	Retained       []RetainedItem `json:"retained"`
+	SignedReceipt  string         `json:"-"` // Receipt signed when the erasure was committed
+}
+
+// receiptClaims Claims of the signed erasure receipt
+type receiptClaims struct {
+	Receipt Receipt `json:"receipt"`
+	jwt.StandardClaims
+}
+
+// newReceipt Creates an erasure receipt for the individual
+func newReceipt(organisationId string, individualId string, requestedBy string) Receipt {
+	return Receipt{
+		Id:             primitive.NewObjectID().Hex(),
+		OrganisationId: organisationId,
+		IndividualId:   individualId,
+		RequestedBy:    requestedBy,
+		Timestamp:      time.Now().UTC().Format("2006-01-02T15:04:05Z"),
+		Removed:        []RemovedItem{},
+		Retained:       []RetainedItem{},
+	}
+}
+
+// addRemoved Adds the number of items of a type removed to the receipt
+func (receipt *Receipt) addRemoved(itemType string, count int64) {
+	receipt.Removed = append(receipt.Removed, RemovedItem{Type: itemType, Count: count})
+}
+
+// Sign Signs the erasure receipt, the signed receipt is an ES256 JWT which
+// can be verified with the JWKS of the organisation
+func (receipt Receipt) Sign() (string, error) {
+	issuedAt, err := time.Parse("2006-01-02T15:04:05Z", receipt.Timestamp)
+	if err != nil {
+		return "", err
+	}
+
+	claims := receiptClaims{
+		receipt,
+		jwt.StandardClaims{
+			Id:       receipt.Id,
+			Subject:  receipt.IndividualId,
+			IssuedAt: issuedAt.Unix(),
+		},
+	}
+
+	return signingkey.Sign(receipt.OrganisationId, claims)
+}
+
+// AddReceiptWithContext Adds the erasure receipt to the db, context is used for transactions
+func AddReceiptWithContext(ctx context.Context, receipt Receipt) (Receipt, error) {
+	_, err := Collection().InsertOne(ctx, receipt)
+	if err != nil {
+		return receipt, err
+	}
+	return receipt, nil
+}
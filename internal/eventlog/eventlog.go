@@ -0,0 +1,315 @@
+package eventlog
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bb-consent/api/internal/config"
+	"github.com/bb-consent/api/internal/database"
+	"github.com/bb-consent/api/internal/webhook_dispatcher"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// StreamConfiguration Stores event stream configuration
+var StreamConfiguration config.EventStreamConfig
+
+// Init Initializes event stream configuration
+func Init(config *config.Configuration) {
+	StreamConfiguration = config.EventStream
+}
+
+// IsAllowedOrigin Checks if browsers from the origin are allowed to stream
+// events, requests without origin aren't from browsers and are allowed
+func IsAllowedOrigin(origin string, host string) bool {
+	if len(origin) == 0 {
+		return true
+	}
+	u, err := url.Parse(origin)
+	if err != nil {
+		return false
+	}
+	if strings.EqualFold(u.Host, host) {
+		return true
+	}
+	for _, allowed := range StreamConfiguration.AllowedOrigins {
+		if strings.EqualFold(strings.TrimSuffix(allowed, "/"), origin) {
+			return true
+		}
+	}
+	return false
+}
+
+// Prefixes of event types which are recorded in event log
+var streamedEventTypePrefixes = []string{
+	"consent.",
+	"data_agreement.",
+}
+
+// Entry Event recorded in event log, sequence is increasing across the
+// organisations so that clients can resume from the last seen event
+type Entry struct {
+	ID              string                          `json:"id" bson:"_id,omitempty"`
+	Sequence        int64                           `json:"sequence"`
+	OrganisationId  string                          `json:"organisationId"`
+	DataAgreementId string                          `json:"dataAgreementId"`
+	IndividualId    string                          `json:"individualId"`
+	Type            string                          `json:"type"`
+	Timestamp       string                          `json:"timestamp"`
+	Event           webhook_dispatcher.WebhookEvent `json:"event"`
+}
+
+// Filter Filters for reading event log
+type Filter struct {
+	DataAgreementId string
+	IndividualId    string
+}
+
+func Collection() *mongo.Collection {
+	return database.DB.Client.Database(database.DB.Name).Collection("eventLog")
+}
+
+func counterCollection() *mongo.Collection {
+	return database.DB.Client.Database(database.DB.Name).Collection("counters")
+}
+
+// nextSequence Returns the next sequence of event log
+func nextSequence() (int64, error) {
+	var counter struct {
+		Seq int64 `bson:"seq"`
+	}
+
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+	err := counterCollection().FindOneAndUpdate(context.TODO(), bson.M{"_id": "eventLog"}, bson.M{"$inc": bson.M{"seq": 1}}, opts).Decode(&counter)
+	return counter.Seq, err
+}
+
+// IsStreamedEventType Checks if events of the type are recorded in event log
+func IsStreamedEventType(eventType string) bool {
+	for _, prefix := range streamedEventTypePrefixes {
+		if strings.HasPrefix(eventType, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// Add Records the event in event log and notifies the subscribers. Delivery
+// ID of the event is used as the ID of the entry, so an event published
+// again is recorded once.
+func Add(event webhook_dispatcher.WebhookEvent, organisationId string) (Entry, error) {
+	entryId := event.DeliveryID
+	if len(entryId) == 0 {
+		entryId = primitive.NewObjectID().Hex()
+	}
+
+	// Event is already recorded
+	var existing Entry
+	err := Collection().FindOne(context.TODO(), bson.M{"_id": entryId}).Decode(&existing)
+	if err == nil {
+		return existing, nil
+	}
+	if err != mongo.ErrNoDocuments {
+		return Entry{}, err
+	}
+
+	// Data agreement and individual are recorded for filtering
+	var data struct {
+		DataAgreementId string `json:"dataAgreementId"`
+		IndividualId    string `json:"individualId"`
+	}
+	json.Unmarshal(event.Data, &data)
+
+	sequence, err := nextSequence()
+	if err != nil {
+		return Entry{}, err
+	}
+
+	entry := Entry{
+		ID:              entryId,
+		Sequence:        sequence,
+		OrganisationId:  organisationId,
+		DataAgreementId: data.DataAgreementId,
+		IndividualId:    data.IndividualId,
+		Type:            event.Type,
+		Timestamp:       time.Now().UTC().Format("2006-01-02T15:04:05Z"),
+		Event:           event,
+	}
+
+	_, err = Collection().InsertOne(context.TODO(), &entry)
+	if mongo.IsDuplicateKeyError(err) {
+		// Recorded concurrently, the sequence allocated is skipped as a gap
+		return entry, nil
+	}
+	if err != nil {
+		return entry, err
+	}
+
+	notifySubscribers()
+	return entry, nil
+}
+
+// Events missing from the sequence for longer than this are considered lost
+const sequenceGapGracePeriod = time.Minute
+
+// committedSequence Returns the sequence up to which all the events after
+// the given sequence are recorded, reading at most limit events. Sequence is
+// allocated before the event is inserted, so an event can be recorded
+// before another event with a lower sequence. Reading stops at the first gap
+// in the sequence, so clients resuming from the last seen event don't skip
+// the event yet to be recorded. Gaps older than the grace period are
+// skipped, since the event was lost for e.g. the insert failed.
+func committedSequence(afterSequence int64, limit int64) (int64, error) {
+	var entries []Entry
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "sequence", Value: 1}}).
+		SetProjection(bson.M{"sequence": 1, "timestamp": 1}).
+		SetLimit(limit)
+	cursor, err := Collection().Find(context.TODO(), bson.M{"sequence": bson.M{"$gt": afterSequence}}, opts)
+	if err != nil {
+		return afterSequence, err
+	}
+	defer cursor.Close(context.TODO())
+
+	if err := cursor.All(context.TODO(), &entries); err != nil {
+		return afterSequence, err
+	}
+
+	gapCutoffTimestamp := time.Now().UTC().Add(-sequenceGapGracePeriod).Format("2006-01-02T15:04:05Z")
+
+	committed := afterSequence
+	for _, entry := range entries {
+		if entry.Sequence != committed+1 && entry.Timestamp > gapCutoffTimestamp {
+			break
+		}
+		committed = entry.Sequence
+	}
+	return committed, nil
+}
+
+// ListAfter Lists the events of organisation after the sequence in order,
+// returns the events and the sequence the events are read up to. Events are
+// read only up to the first gap in the sequence, see committedSequence.
+func ListAfter(organisationId string, afterSequence int64, filter Filter, limit int64) ([]Entry, int64, error) {
+	results := []Entry{}
+
+	readUpTo, err := committedSequence(afterSequence, limit)
+	if err != nil || readUpTo == afterSequence {
+		return results, afterSequence, err
+	}
+
+	query := bson.M{"organisationid": organisationId, "sequence": bson.M{"$gt": afterSequence, "$lte": readUpTo}}
+	if len(filter.DataAgreementId) > 0 {
+		query["dataagreementid"] = filter.DataAgreementId
+	}
+	if len(filter.IndividualId) > 0 {
+		query["individualid"] = filter.IndividualId
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "sequence", Value: 1}})
+	cursor, err := Collection().Find(context.TODO(), query, opts)
+	if err != nil {
+		return results, afterSequence, err
+	}
+	defer cursor.Close(context.TODO())
+
+	if err := cursor.All(context.TODO(), &results); err != nil {
+		return results, afterSequence, err
+	}
+	return results, readUpTo, nil
+}
+
+// LastSequence Returns the sequence of the latest event in event log, events
+// with a lower sequence yet to be recorded are excluded. Recent events are
+// read from the latest event older than the grace period.
+func LastSequence() (int64, error) {
+	var entry Entry
+
+	gapCutoffTimestamp := time.Now().UTC().Add(-sequenceGapGracePeriod).Format("2006-01-02T15:04:05Z")
+
+	opts := options.FindOne().SetSort(bson.D{{Key: "sequence", Value: -1}})
+	err := Collection().FindOne(context.TODO(), bson.M{"timestamp": bson.M{"$lte": gapCutoffTimestamp}}, opts).Decode(&entry)
+	if err != nil && err != mongo.ErrNoDocuments {
+		return 0, err
+	}
+
+	return committedSequence(entry.Sequence, 0)
+}
+
+var (
+	subscribersMu sync.Mutex
+	subscribers   = map[chan struct{}]struct{}{}
+)
+
+// Subscribe Returns a channel which is signalled when events are recorded
+// by this instance, and a function to unsubscribe
+func Subscribe() (<-chan struct{}, func()) {
+	ch := make(chan struct{}, 1)
+
+	subscribersMu.Lock()
+	subscribers[ch] = struct{}{}
+	subscribersMu.Unlock()
+
+	return ch, func() {
+		subscribersMu.Lock()
+		delete(subscribers, ch)
+		subscribersMu.Unlock()
+	}
+}
+
+func notifySubscribers() {
+	subscribersMu.Lock()
+	defer subscribersMu.Unlock()
+
+	for ch := range subscribers {
+		select {
+		case ch <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// Sink Records consent and data agreement events published to event sinks
+type Sink struct{}
+
+// NewSink Creates event log sink
+func NewSink() *Sink {
+	return &Sink{}
+}
+
+// Name Returns the name of the sink
+func (s *Sink) Name() string {
+	return "eventlog"
+}
+
+// Publish Records the event if it is a consent or data agreement event
+func (s *Sink) Publish(event webhook_dispatcher.WebhookEvent, organisationId string) error {
+	if !IsStreamedEventType(event.Type) {
+		return nil
+	}
+	_, err := Add(event, organisationId)
+	return err
+}
+
+// Close Nothing to release for event log
+func (s *Sink) Close() error {
+	return nil
+}
+
+// DeleteByIndividualIdWithContext Deletes the events of the individual which
+// aren't related to the data agreements to be retained, context is used for transactions
+func DeleteByIndividualIdWithContext(ctx context.Context, organisationId string, individualId string, retainedDataAgreementIds []string) (int64, error) {
+	filter := bson.M{"organisationid": organisationId, "individualid": individualId, "dataagreementid": bson.M{"$nin": retainedDataAgreementIds}}
+	result, err := Collection().DeleteMany(ctx, filter)
+	if err != nil {
+		return 0, err
+	}
+	return result.DeletedCount, nil
+}
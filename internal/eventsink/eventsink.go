@@ -0,0 +1,90 @@
+package eventsink
+
+import (
+	"log"
+	"sync"
+
+	"github.com/bb-consent/api/internal/config"
+	"github.com/bb-consent/api/internal/webhook_dispatcher"
+)
+
+// Sink Publishes webhook events to a message bus
+type Sink interface {
+	// Name Returns the name of the sink for e.g. kafka
+	Name() string
+
+	// Publish Publishes the event envelope for the organisation
+	Publish(event webhook_dispatcher.WebhookEvent, organisationId string) error
+
+	// Close Releases the connections held by the sink
+	Close() error
+}
+
+var (
+	sinksMu sync.RWMutex
+	sinks   []Sink
+)
+
+// Init Initializes the sinks enabled in configuration
+func Init(config *config.Configuration) error {
+	sinksConfig := config.Webhooks.Sinks
+
+	if sinksConfig.Kafka.Enabled {
+		kafkaSink, err := NewKafkaSink(sinksConfig.Kafka)
+		if err != nil {
+			return err
+		}
+		Register(kafkaSink)
+	}
+
+	if sinksConfig.Nats.Enabled {
+		natsSink, err := NewNatsSink(sinksConfig.Nats)
+		if err != nil {
+			return err
+		}
+		Register(natsSink)
+	}
+
+	return nil
+}
+
+// Register Adds a sink to which events are published
+func Register(sink Sink) {
+	sinksMu.Lock()
+	defer sinksMu.Unlock()
+
+	sinks = append(sinks, sink)
+}
+
+// Reset Closes and removes all the registered sinks
+func Reset() {
+	sinksMu.Lock()
+	defer sinksMu.Unlock()
+
+	for _, sink := range sinks {
+		if err := sink.Close(); err != nil {
+			log.Printf("Failed to close event sink:%s err:%v", sink.Name(), err)
+		}
+	}
+	sinks = nil
+}
+
+// Publish Publishes the event to all the registered sinks, failure to publish
+// to a sink doesn't affect the other sinks. Returns the first error, so the
+// caller can publish the event again; sinks which accepted the event receive
+// it again, delivery to sinks is at least once.
+func Publish(event webhook_dispatcher.WebhookEvent, organisationId string) error {
+	sinksMu.RLock()
+	defer sinksMu.RUnlock()
+
+	var publishErr error
+	for _, sink := range sinks {
+		if err := sink.Publish(event, organisationId); err != nil {
+			log.Printf("Failed to publish event:%s of org:%s to sink:%s err:%v", event.Type, organisationId, sink.Name(), err)
+			if publishErr == nil {
+				publishErr = err
+			}
+		}
+	}
+	return publishErr
+}
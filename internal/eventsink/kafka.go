@@ -0,0 +1,75 @@
+package eventsink
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/bb-consent/api/internal/config"
+	"github.com/bb-consent/api/internal/webhook_dispatcher"
+	"github.com/segmentio/kafka-go"
+)
+
+// Time allowed for the brokers to acknowledge an event
+const kafkaPublishTimeout = 10 * time.Second
+
+// Time the writer waits for more events before sending a batch. Events are
+// written synchronously so the publish error can be returned to the caller,
+// the default of 1s would delay every event by it.
+const kafkaBatchTimeout = 5 * time.Millisecond
+
+// KafkaSink Publishes events to a kafka topic, events are keyed by
+// organisation so that events of an organisation are ordered
+type KafkaSink struct {
+	writer *kafka.Writer
+}
+
+// NewKafkaSink Creates kafka sink from configuration
+func NewKafkaSink(sinkConfig config.KafkaSinkConfig) (*KafkaSink, error) {
+	if len(sinkConfig.Brokers) == 0 {
+		return nil, errors.New("kafka sink requires atleast 1 broker")
+	}
+	if len(sinkConfig.Topic) == 0 {
+		return nil, errors.New("kafka sink requires a topic")
+	}
+
+	writer := &kafka.Writer{
+		Addr:         kafka.TCP(sinkConfig.Brokers...),
+		Topic:        sinkConfig.Topic,
+		Balancer:     &kafka.Hash{},
+		RequiredAcks: kafka.RequireAll,
+		BatchTimeout: kafkaBatchTimeout,
+	}
+	return &KafkaSink{writer: writer}, nil
+}
+
+// Name Returns the name of the sink
+func (s *KafkaSink) Name() string {
+	return "kafka"
+}
+
+// Publish Publishes the event envelope to the topic
+func (s *KafkaSink) Publish(event webhook_dispatcher.WebhookEvent, organisationId string) error {
+	value, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), kafkaPublishTimeout)
+	defer cancel()
+
+	return s.writer.WriteMessages(ctx, kafka.Message{
+		Key:   []byte(organisationId),
+		Value: value,
+		Headers: []kafka.Header{
+			{Key: "type", Value: []byte(event.Type)},
+			{Key: "organisationId", Value: []byte(organisationId)},
+		},
+	})
+}
+
+// Close Flushes pending events and closes the connections to brokers
+func (s *KafkaSink) Close() error {
+	return s.writer.Close()
+}
@@ -0,0 +1,57 @@
+package eventsink
+
+import (
+	"sync"
+
+	"github.com/bb-consent/api/internal/webhook_dispatcher"
+)
+
+// PublishedEvent Event published to the in-memory sink
+type PublishedEvent struct {
+	OrganisationId string
+	Event          webhook_dispatcher.WebhookEvent
+}
+
+// MemorySink Keeps the published events in memory, meant for tests
+type MemorySink struct {
+	mu     sync.Mutex
+	events []PublishedEvent
+}
+
+// NewMemorySink Creates in-memory sink
+func NewMemorySink() *MemorySink {
+	return &MemorySink{}
+}
+
+// Name Returns the name of the sink
+func (s *MemorySink) Name() string {
+	return "memory"
+}
+
+// Publish Records the event
+func (s *MemorySink) Publish(event webhook_dispatcher.WebhookEvent, organisationId string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.events = append(s.events, PublishedEvent{OrganisationId: organisationId, Event: event})
+	return nil
+}
+
+// Events Returns the events published so far
+func (s *MemorySink) Events() []PublishedEvent {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	events := make([]PublishedEvent, len(s.events))
+	copy(events, s.events)
+	return events
+}
+
+// Close Discards the recorded events
+func (s *MemorySink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.events = nil
+	return nil
+}
@@ -0,0 +1,73 @@
+package eventsink
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"github.com/bb-consent/api/internal/config"
+	"github.com/bb-consent/api/internal/webhook_dispatcher"
+	"github.com/nats-io/nats.go"
+)
+
+// Default subject prefix for events published to nats
+const defaultNatsSubject = "consentbb.events"
+
+// NatsSink Publishes events to nats, the subject is the configured prefix
+// followed by event type for e.g. consentbb.events.consent.allowed
+type NatsSink struct {
+	conn    *nats.Conn
+	subject string
+}
+
+// NewNatsSink Creates nats sink from configuration
+func NewNatsSink(sinkConfig config.NatsSinkConfig) (*NatsSink, error) {
+	if len(sinkConfig.Url) == 0 {
+		return nil, errors.New("nats sink requires a server url")
+	}
+
+	subject := strings.TrimSuffix(sinkConfig.Subject, ".")
+	if len(subject) == 0 {
+		subject = defaultNatsSubject
+	}
+
+	opts := []nats.Option{
+		nats.Name("bb-consent-api"),
+		nats.RetryOnFailedConnect(true),
+		nats.MaxReconnects(-1),
+	}
+	if len(sinkConfig.Token) > 0 {
+		opts = append(opts, nats.Token(sinkConfig.Token))
+	}
+
+	conn, err := nats.Connect(sinkConfig.Url, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &NatsSink{conn: conn, subject: subject}, nil
+}
+
+// Name Returns the name of the sink
+func (s *NatsSink) Name() string {
+	return "nats"
+}
+
+// Publish Publishes the event envelope to the subject of the event type
+func (s *NatsSink) Publish(event webhook_dispatcher.WebhookEvent, organisationId string) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	msg := nats.NewMsg(s.subject + "." + event.Type)
+	msg.Data = data
+	msg.Header.Set("type", event.Type)
+	msg.Header.Set("organisationId", organisationId)
+	return s.conn.PublishMsg(msg)
+}
+
+// Close Flushes pending events and closes the connection
+func (s *NatsSink) Close() error {
+	s.conn.Close()
+	return nil
+}
@@ -126,6 +126,13 @@ func LoadImageAssetsForSingleTenantConfiguration() error {
 		return err
 	}
 
+	return LoadImageAssetsForOrganisation(o)
+}
+
+// LoadImageAssetsForOrganisation Loads default cover and logo image for organisation if not present
+func LoadImageAssetsForOrganisation(o org.Organization) error {
+	var err error
+
 	// Check if cover image is present
 	if len(strings.TrimSpace(o.CoverImageID)) == 0 {
 		err = loadCoverImageAssets(o.ID)
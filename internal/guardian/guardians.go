@@ -0,0 +1,165 @@
+package guardian
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/bb-consent/api/internal/common"
+	"github.com/bb-consent/api/internal/database"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Relationship of guardian to the individual
+const (
+	RelationshipParent        = "parent"
+	RelationshipLegalGuardian = "legal_guardian"
+	RelationshipDelegate      = "delegate"
+)
+
+// Relationships Supported relationships of guardian to the individual
+var Relationships = []string{
+	RelationshipParent,
+	RelationshipLegalGuardian,
+	RelationshipDelegate,
+}
+
+// ErrNotActingGuardian Guardian isn't allowed to act on behalf of the individual
+var ErrNotActingGuardian = errors.New("guardian is not allowed to act on behalf of the individual")
+
+func Collection() *mongo.Collection {
+	return database.DB.Client.Database(database.DB.Name).Collection("guardians")
+}
+
+// Evidence Evidence of the guardian relationship for e.g. birth certificate or court order
+type Evidence struct {
+	Type        string `json:"type" valid:"required"`
+	Reference   string `json:"reference"`
+	Description string `json:"description"`
+}
+
+// Guardian Individual who can consent on behalf of another individual for
+// e.g. parent of a minor or legal guardian
+type Guardian struct {
+	Id                   string   `json:"id" bson:"_id,omitempty"`
+	IndividualId         string   `json:"individualId"`                          // Individual on whose behalf the guardian consents
+	GuardianIndividualId string   `json:"guardianIndividualId" valid:"required"` // Individual acting as guardian
+	Relationship         string   `json:"relationship" valid:"required"`
+	Evidence             Evidence `json:"evidence"`
+	ValidFromTimestamp   string   `json:"validFrom"`  // UTC timestamp from which the guardian can act
+	ValidUntilTimestamp  string   `json:"validUntil"` // UTC timestamp until which the guardian can act, no expiry if empty
+	Timestamp            string   `json:"timestamp"`
+	OrganisationId       string   `json:"-"`
+	IsDeleted            bool     `json:"-"`
+}
+
+// IsActive Checks if the guardian can act at the time
+func (g Guardian) IsActive(now time.Time) bool {
+	timestamp := now.UTC().Format("2006-01-02T15:04:05Z")
+	if len(g.ValidFromTimestamp) > 0 && g.ValidFromTimestamp > timestamp {
+		return false
+	}
+	if len(g.ValidUntilTimestamp) > 0 && g.ValidUntilTimestamp <= timestamp {
+		return false
+	}
+	return true
+}
+
+type GuardianRepository struct {
+	DefaultFilter bson.M
+}
+
+// Init
+func (gRepo *GuardianRepository) Init(organisationId string) {
+	gRepo.DefaultFilter = bson.M{"organisationid": organisationId, "isdeleted": false}
+}
+
+// Add Adds the guardian to the db
+func (gRepo *GuardianRepository) Add(guardian Guardian) (Guardian, error) {
+	_, err := Collection().InsertOne(context.TODO(), guardian)
+	if err != nil {
+		return Guardian{}, err
+	}
+	return guardian, nil
+}
+
+// Get Gets a guardian of the individual by id
+func (gRepo *GuardianRepository) Get(individualId string, guardianId string) (Guardian, error) {
+	var result Guardian
+
+	filter := common.CombineFilters(gRepo.DefaultFilter, bson.M{"_id": guardianId, "individualid": individualId})
+	err := Collection().FindOne(context.TODO(), filter).Decode(&result)
+
+	return result, err
+}
+
+// Update Updates the guardian
+func (gRepo *GuardianRepository) Update(guardian Guardian) (Guardian, error) {
+	filter := common.CombineFilters(gRepo.DefaultFilter, bson.M{"_id": guardian.Id})
+	update := bson.M{"$set": guardian}
+
+	_, err := Collection().UpdateOne(context.TODO(), filter, update)
+	if err != nil {
+		return guardian, err
+	}
+	return guardian, nil
+}
+
+// ListByIndividualId Lists the guardians of the individual
+func (gRepo *GuardianRepository) ListByIndividualId(individualId string) ([]Guardian, error) {
+	results := []Guardian{}
+
+	filter := common.CombineFilters(gRepo.DefaultFilter, bson.M{"individualid": individualId})
+	cursor, err := Collection().Find(context.TODO(), filter)
+	if err != nil {
+		return results, err
+	}
+	defer cursor.Close(context.TODO())
+
+	if err := cursor.All(context.TODO(), &results); err != nil {
+		return results, err
+	}
+	return results, nil
+}
+
+// GetActingGuardian Returns the guardian relationship allowing the guardian
+// individual to act on behalf of the individual now
+func (gRepo *GuardianRepository) GetActingGuardian(individualId string, guardianIndividualId string) (Guardian, error) {
+	filter := common.CombineFilters(gRepo.DefaultFilter, bson.M{"individualid": individualId, "guardianindividualid": guardianIndividualId})
+	cursor, err := Collection().Find(context.TODO(), filter)
+	if err != nil {
+		return Guardian{}, err
+	}
+	defer cursor.Close(context.TODO())
+
+	var guardians []Guardian
+	if err := cursor.All(context.TODO(), &guardians); err != nil {
+		return Guardian{}, err
+	}
+
+	now := time.Now()
+	for _, g := range guardians {
+		if g.IsActive(now) {
+			return g, nil
+		}
+	}
+	return Guardian{}, ErrNotActingGuardian
+}
+
+// DeleteByIndividualIdWithContext Deletes the guardians of the individual and
+// the relationships where the individual is a guardian, context is used for transactions
+func (gRepo *GuardianRepository) DeleteByIndividualIdWithContext(ctx context.Context, individualId string) (int64, error) {
+	filter := bson.M{
+		"organisationid": gRepo.DefaultFilter["organisationid"],
+		"$or": []bson.M{
+			{"individualid": individualId},
+			{"guardianindividualid": individualId},
+		},
+	}
+	result, err := Collection().DeleteMany(ctx, filter)
+	if err != nil {
+		return 0, err
+	}
+	return result.DeletedCount, nil
+}
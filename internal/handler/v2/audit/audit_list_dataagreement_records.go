@@ -32,6 +32,8 @@ type listDataAgreementRecord struct {
 	SignatureId               string                                  `json:"signatureId"`
 	Timestamp                 string                                  `json:"timestamp"`
 	DataAgreement             dataAgreementForListDataAgreementRecord `json:"dataAgreement"`
+	AuthorizedByIndividualId  string                                  `json:"authorizedByIndividualId"` // Guardian who consented on behalf of the individual
+	Delegated                 bool                                    `json:"delegated"`
 }
 
 func dataAgreementRecordsToInterfaceSlice(dataAgreementRecords []listDataAgreementRecord) []interface{} {
@@ -64,6 +66,8 @@ func recreateDataAgreementRecordsFromRevisions(dataAgreementRecords []daRecord.D
 			consentRecord.State = tempDARecord.State
 			consentRecord.SignatureId = tempDARecord.SignatureId
 			consentRecord.Timestamp = dARevision.Timestamp
+			consentRecord.AuthorizedByIndividualId = dARevision.AuthorizedByIndividualId
+			consentRecord.Delegated = len(dARevision.AuthorizedByIndividualId) > 0
 			// fetch corresponding data agreement revision
 			dataAgreementRevision, err := revision.GetByRevisionId(tempDARecord.DataAgreementRevisionId)
 			if err != nil {
@@ -0,0 +1,224 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/bb-consent/api/internal/common"
+	"github.com/bb-consent/api/internal/config"
+	"github.com/bb-consent/api/internal/eventlog"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	// Interval at which event log is read for events recorded by other instances
+	streamPollInterval = 2 * time.Second
+
+	// Interval at which heartbeat is sent to keep the connection open
+	streamHeartbeatInterval = 15 * time.Second
+
+	// Events read from event log at a time
+	streamBatchSize = 100
+)
+
+// streamedEvent Defines the structure of event sent over websocket
+type streamedEvent struct {
+	Id   string      `json:"id"`   // Event ID to resume from using lastEventId
+	Type string      `json:"type"` // Event type for e.g. consent.allowed
+	Data interface{} `json:"data"` // Event envelope same as webhooks
+}
+
+// streamRequest Parameters for streaming events
+type streamRequest struct {
+	OrganisationId string
+	Filter         eventlog.Filter
+	LastSequence   int64
+}
+
+// parseStreamRequest Parses the filters and the event to resume from. The
+// event to resume from is read from Last-Event-ID header or lastEventId query
+// param, only new events are streamed if it isn't provided
+func parseStreamRequest(r *http.Request) (streamRequest, error) {
+	organisationId := r.Header.Get(config.OrganizationId)
+	organisationId = common.Sanitize(organisationId)
+
+	streamReq := streamRequest{
+		OrganisationId: organisationId,
+		Filter: eventlog.Filter{
+			DataAgreementId: common.Sanitize(r.URL.Query().Get("dataAgreementId")),
+			IndividualId:    common.Sanitize(r.URL.Query().Get("individualId")),
+		},
+	}
+
+	lastEventId := r.Header.Get("Last-Event-ID")
+	if len(lastEventId) == 0 {
+		lastEventId = r.URL.Query().Get("lastEventId")
+	}
+
+	if len(lastEventId) > 0 {
+		lastSequence, err := strconv.ParseInt(lastEventId, 10, 64)
+		if err != nil || lastSequence < 0 {
+			return streamReq, errors.New("invalid last event id")
+		}
+		streamReq.LastSequence = lastSequence
+		return streamReq, nil
+	}
+
+	lastSequence, err := eventlog.LastSequence()
+	if err != nil {
+		return streamReq, err
+	}
+	streamReq.LastSequence = lastSequence
+	return streamReq, nil
+}
+
+// streamEvents Sends the events recorded after the last sequence until the
+// context is done or sending fails
+func streamEvents(ctx context.Context, streamReq streamRequest, send func(entry eventlog.Entry) error, heartbeat func() error) error {
+	notifications, unsubscribe := eventlog.Subscribe()
+	defer unsubscribe()
+
+	poll := time.NewTicker(streamPollInterval)
+	defer poll.Stop()
+
+	heartbeatTicker := time.NewTicker(streamHeartbeatInterval)
+	defer heartbeatTicker.Stop()
+
+	lastSequence := streamReq.LastSequence
+	for {
+		entries, readUpTo, err := eventlog.ListAfter(streamReq.OrganisationId, lastSequence, streamReq.Filter, streamBatchSize)
+		if err != nil {
+			return err
+		}
+		for _, entry := range entries {
+			if err := send(entry); err != nil {
+				return err
+			}
+		}
+
+		// Reading the next batch without waiting if the client is behind
+		isBehind := readUpTo-lastSequence >= streamBatchSize
+		lastSequence = readUpTo
+		if isBehind {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-notifications:
+		case <-poll.C:
+		case <-heartbeatTicker.C:
+			if err := heartbeat(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// AuditStreamEvents Streams consent record and data agreement events of the
+// organisation as server-sent events. Api keys restricted to data agreements
+// must filter the events by one of the data agreements.
+func AuditStreamEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		m := "Streaming is not supported"
+		common.HandleErrorV2(w, http.StatusInternalServerError, m, errors.New(m))
+		return
+	}
+
+	streamReq, err := parseStreamRequest(r)
+	if err != nil {
+		common.HandleErrorV2(w, http.StatusBadRequest, err.Error(), err)
+		return
+	}
+
+	w.Header().Set(config.ContentTypeHeader, "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	send := func(entry eventlog.Entry) error {
+		data, err := json.Marshal(entry.Event)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", entry.Sequence, entry.Type, data)
+		flusher.Flush()
+		return err
+	}
+	heartbeat := func() error {
+		_, err := fmt.Fprint(w, ": heartbeat\n\n")
+		flusher.Flush()
+		return err
+	}
+
+	err = streamEvents(r.Context(), streamReq, send, heartbeat)
+	if err != nil {
+		log.Printf("Stopped streaming events for organisation: %v err: %v", streamReq.OrganisationId, err)
+	}
+}
+
+// Browsers can open websocket only from the same origin or the configured
+// allowed origins
+var upgrader = websocket.Upgrader{
+	ReadBufferSize:  1024,
+	WriteBufferSize: 1024,
+	CheckOrigin: func(r *http.Request) bool {
+		return eventlog.IsAllowedOrigin(r.Header.Get("Origin"), r.Host)
+	},
+}
+
+// AuditStreamEventsWebSocket Streams consent record and data agreement events
+// of the organisation over websocket
+func AuditStreamEventsWebSocket(w http.ResponseWriter, r *http.Request) {
+	streamReq, err := parseStreamRequest(r)
+	if err != nil {
+		common.HandleErrorV2(w, http.StatusBadRequest, err.Error(), err)
+		return
+	}
+
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		log.Printf("Failed to upgrade to websocket for organisation: %v err: %v", streamReq.OrganisationId, err)
+		return
+	}
+	defer conn.Close()
+
+	// Messages from client aren't expected, reading is required to process
+	// control messages and to detect the client closing the connection
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+	go func() {
+		defer cancel()
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	send := func(entry eventlog.Entry) error {
+		return conn.WriteJSON(streamedEvent{
+			Id:   strconv.FormatInt(entry.Sequence, 10),
+			Type: entry.Type,
+			Data: entry.Event,
+		})
+	}
+	heartbeat := func() error {
+		return conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(10*time.Second))
+	}
+
+	err = streamEvents(ctx, streamReq, send, heartbeat)
+	if err != nil {
+		log.Printf("Stopped streaming events for organisation: %v err: %v", streamReq.OrganisationId, err)
+	}
+}
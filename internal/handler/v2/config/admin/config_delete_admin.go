@@ -0,0 +1,75 @@
+package admin
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/bb-consent/api/internal/common"
+	"github.com/bb-consent/api/internal/config"
+	"github.com/bb-consent/api/internal/invitation"
+	"github.com/bb-consent/api/internal/org"
+	"github.com/bb-consent/api/internal/user"
+	"github.com/gorilla/mux"
+)
+
+type deleteAdminResp struct {
+	Admin organisationAdmin `json:"admin"`
+}
+
+// ConfigDeleteAdmin
+func ConfigDeleteAdmin(w http.ResponseWriter, r *http.Request) {
+	// Headers
+	organisationId := r.Header.Get(config.OrganizationId)
+	organisationId = common.Sanitize(organisationId)
+
+	userId := mux.Vars(r)[config.UserId]
+	userId = common.Sanitize(userId)
+
+	o, err := org.Get(organisationId)
+	if err != nil {
+		m := fmt.Sprintf("Failed to fetch organisation: %v", organisationId)
+		common.HandleErrorV2(w, http.StatusInternalServerError, m, err)
+		return
+	}
+
+	admin, err := getAdminForOrganisation(o, userId)
+	if err != nil {
+		m := fmt.Sprintf("Failed to fetch admin: %v", userId)
+		common.HandleErrorV2(w, http.StatusBadRequest, m, err)
+		return
+	}
+
+	// Organisation should be left with at least one admin
+	if admin.RoleID == common.GetRoleID("Admin") && countOrganisationAdmins(o) <= 1 {
+		m := "Last admin of the organisation can't be removed"
+		common.HandleErrorV2(w, http.StatusBadRequest, m, nil)
+		return
+	}
+
+	_, err = org.DeleteAdminUsers(organisationId, admin)
+	if err != nil {
+		m := fmt.Sprintf("Failed to remove admin: %v", userId)
+		common.HandleErrorV2(w, http.StatusInternalServerError, m, err)
+		return
+	}
+
+	u, err := user.RemoveRolesForOrganization(userId, organisationId)
+	if err != nil {
+		m := fmt.Sprintf("Failed to remove admin: %v", userId)
+		common.HandleErrorV2(w, http.StatusInternalServerError, m, err)
+		return
+	}
+
+	// Pending invitations are no longer valid
+	err = invitation.DeleteAllForUser(organisationId, userId)
+	if err != nil {
+		m := fmt.Sprintf("Failed to remove invitations for admin: %v", userId)
+		common.HandleErrorV2(w, http.StatusInternalServerError, m, err)
+		return
+	}
+
+	resp := deleteAdminResp{
+		Admin: newOrganisationAdmin(u, admin.RoleID),
+	}
+	common.ReturnHTTPResponse(resp, w)
+}
@@ -0,0 +1,164 @@
+package admin
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/asaskevich/govalidator"
+	"github.com/bb-consent/api/internal/common"
+	"github.com/bb-consent/api/internal/config"
+	"github.com/bb-consent/api/internal/email"
+	"github.com/bb-consent/api/internal/iam"
+	"github.com/bb-consent/api/internal/invitation"
+	"github.com/bb-consent/api/internal/org"
+	"github.com/bb-consent/api/internal/user"
+)
+
+type inviteAdminReq struct {
+	Email  string `json:"email" valid:"required,email"`
+	Name   string `json:"name"`
+	RoleId int    `json:"roleId" valid:"required"`
+}
+
+type inviteAdminResp struct {
+	Admin organisationAdmin `json:"admin"`
+}
+
+// registerOrganisationAdmin Registers the admin in IAM and db, the admin is
+// removed from IAM if it can't be added to db
+func registerOrganisationAdmin(email string, name string) (user.User, error) {
+	iamId, err := iam.RegisterOrganisationAdmin(email, name)
+	if err != nil {
+		if len(iamId) > 0 {
+			rollbackIamUser(iamId)
+		}
+		return user.User{}, err
+	}
+
+	var u user.User
+	u.IamID = iamId
+	u.Email = email
+	u.Name = name
+	u.Orgs = []user.Org{}
+	u.Roles = []user.Role{}
+
+	u, err = user.Add(u)
+	if err != nil {
+		rollbackIamUser(iamId)
+		return u, err
+	}
+	return u, nil
+}
+
+// rollbackIamUser Removes the admin registered in IAM, failure is logged
+// since the request has already failed
+func rollbackIamUser(iamId string) {
+	if err := iam.UnregisterOrganisationAdmin(iamId); err != nil {
+		log.Printf("Failed to remove admin: %v from IAM: %v", iamId, err)
+	}
+}
+
+// rollbackOrganisationAdmin Removes the admin registered for the invitation
+// from the organisation, db and IAM
+func rollbackOrganisationAdmin(organisationId string, u user.User, roleId int) {
+	if _, err := org.DeleteAdminUsers(organisationId, org.Admin{UserID: u.ID, RoleID: roleId}); err != nil {
+		log.Printf("Failed to remove admin: %v from organisation: %v", u.ID, err)
+	}
+	if err := user.Delete(u.ID); err != nil {
+		log.Printf("Failed to delete admin: %v: %v", u.ID, err)
+	}
+	rollbackIamUser(u.IamID)
+}
+
+// ConfigInviteAdmin Invites an admin to the organisation. Admins who don't
+// have an account are registered and added to the organisation, they
+// activate the account through the invitation. Admins having an account are
+// added to the organisation only once they accept the invitation.
+func ConfigInviteAdmin(w http.ResponseWriter, r *http.Request) {
+	// Headers
+	organisationId := r.Header.Get(config.OrganizationId)
+	organisationId = common.Sanitize(organisationId)
+
+	// Request body
+	var inviteReq inviteAdminReq
+	b, _ := io.ReadAll(r.Body)
+	defer r.Body.Close()
+	json.Unmarshal(b, &inviteReq)
+
+	// validating request payload
+	valid, err := govalidator.ValidateStruct(inviteReq)
+	if !valid {
+		m := "missing mandatory params for inviting admin"
+		common.HandleErrorV2(w, http.StatusBadRequest, m, err)
+		return
+	}
+	if !common.IsValidRoleID(inviteReq.RoleId) {
+		m := fmt.Sprintf("Invalid role: %v", inviteReq.RoleId)
+		common.HandleErrorV2(w, http.StatusBadRequest, m, nil)
+		return
+	}
+	adminEmail := strings.ToLower(common.Sanitize(inviteReq.Email))
+
+	o, err := org.Get(organisationId)
+	if err != nil {
+		m := fmt.Sprintf("Failed to fetch organisation: %v", organisationId)
+		common.HandleErrorV2(w, http.StatusInternalServerError, m, err)
+		return
+	}
+
+	existingUser := true
+	u, err := user.GetByEmail(adminEmail)
+	if err != nil {
+		existingUser = false
+	} else if _, err := getAdminForOrganisation(o, u.ID); err == nil {
+		m := fmt.Sprintf("User is already an admin of the organisation: %v", adminEmail)
+		common.HandleErrorV2(w, http.StatusBadRequest, m, nil)
+		return
+	}
+
+	if !existingUser {
+		u, err = registerOrganisationAdmin(adminEmail, inviteReq.Name)
+		if err != nil {
+			m := fmt.Sprintf("Failed to register admin: %v", adminEmail)
+			common.HandleErrorV2(w, http.StatusInternalServerError, m, err)
+			return
+		}
+
+		_, err = org.AddAdminUsers(organisationId, org.Admin{UserID: u.ID, RoleID: inviteReq.RoleId})
+		if err == nil {
+			u, err = user.SetRoleForOrganization(u.ID, user.Role{RoleID: inviteReq.RoleId, OrgID: organisationId})
+		}
+		if err != nil {
+			rollbackOrganisationAdmin(organisationId, u, inviteReq.RoleId)
+			m := fmt.Sprintf("Failed to add admin: %v", adminEmail)
+			common.HandleErrorV2(w, http.StatusInternalServerError, m, err)
+			return
+		}
+	}
+
+	_, activationToken, err := invitation.Create(organisationId, u.ID, u.Email, inviteReq.RoleId, existingUser)
+	if err != nil {
+		if !existingUser {
+			rollbackOrganisationAdmin(organisationId, u, inviteReq.RoleId)
+		}
+		m := fmt.Sprintf("Failed to create invitation for admin: %v", adminEmail)
+		common.HandleErrorV2(w, http.StatusInternalServerError, m, err)
+		return
+	}
+	activationLink := invitation.GetActivationLink(activationToken)
+
+	log.Printf("Sending invitation email to admin: %v", u.Email)
+	go email.SendAdminInvitationEmail(u.Email, o.Name, activationLink, existingUser, "Invitation to "+o.Name, email.SMTPConfig.AdminEmail)
+
+	resp := inviteAdminResp{
+		Admin: newOrganisationAdmin(u, inviteReq.RoleId),
+	}
+	response, _ := json.Marshal(resp)
+	w.Header().Set(config.ContentTypeHeader, config.ContentTypeJSON)
+	w.WriteHeader(http.StatusCreated)
+	w.Write(response)
+}
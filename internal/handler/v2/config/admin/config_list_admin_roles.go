@@ -0,0 +1,30 @@
+package admin
+
+import (
+	"net/http"
+
+	"github.com/bb-consent/api/internal/common"
+)
+
+type adminRole struct {
+	Id   int    `json:"id"`
+	Role string `json:"role"`
+}
+
+type listAdminRolesResp struct {
+	Roles []adminRole `json:"roles"`
+}
+
+// ConfigListAdminRoles
+func ConfigListAdminRoles(w http.ResponseWriter, r *http.Request) {
+
+	var roles []adminRole
+	for _, role := range common.GetRoles() {
+		roles = append(roles, adminRole{Id: role.ID, Role: role.Role})
+	}
+
+	resp := listAdminRolesResp{
+		Roles: roles,
+	}
+	common.ReturnHTTPResponse(resp, w)
+}
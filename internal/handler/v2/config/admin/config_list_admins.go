@@ -0,0 +1,63 @@
+package admin
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/bb-consent/api/internal/common"
+	"github.com/bb-consent/api/internal/config"
+	"github.com/bb-consent/api/internal/org"
+	"github.com/bb-consent/api/internal/user"
+)
+
+type organisationAdmin struct {
+	Id     string `json:"id"`
+	Name   string `json:"name"`
+	Email  string `json:"email"`
+	RoleId int    `json:"roleId"`
+	Role   string `json:"role"`
+}
+
+func newOrganisationAdmin(u user.User, roleId int) organisationAdmin {
+	return organisationAdmin{
+		Id:     u.ID,
+		Name:   u.Name,
+		Email:  u.Email,
+		RoleId: roleId,
+		Role:   common.GetRole(roleId).Role,
+	}
+}
+
+type listAdminsResp struct {
+	Admins []organisationAdmin `json:"admins"`
+}
+
+// ConfigListAdmins
+func ConfigListAdmins(w http.ResponseWriter, r *http.Request) {
+	// Headers
+	organisationId := r.Header.Get(config.OrganizationId)
+	organisationId = common.Sanitize(organisationId)
+
+	o, err := org.GetAdminUsers(organisationId)
+	if err != nil {
+		m := fmt.Sprintf("Failed to fetch admins of organisation: %v", organisationId)
+		common.HandleErrorV2(w, http.StatusInternalServerError, m, err)
+		return
+	}
+
+	admins := []organisationAdmin{}
+	for _, admin := range o.Admins {
+		u, err := user.Get(admin.UserID)
+		if err != nil {
+			m := fmt.Sprintf("Failed to fetch admin: %v", admin.UserID)
+			common.HandleErrorV2(w, http.StatusInternalServerError, m, err)
+			return
+		}
+		admins = append(admins, newOrganisationAdmin(u, admin.RoleID))
+	}
+
+	resp := listAdminsResp{
+		Admins: admins,
+	}
+	common.ReturnHTTPResponse(resp, w)
+}
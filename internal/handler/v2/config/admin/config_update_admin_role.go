@@ -0,0 +1,106 @@
+package admin
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/bb-consent/api/internal/common"
+	"github.com/bb-consent/api/internal/config"
+	"github.com/bb-consent/api/internal/org"
+	"github.com/bb-consent/api/internal/user"
+	"github.com/gorilla/mux"
+)
+
+type updateAdminRoleReq struct {
+	RoleId int `json:"roleId" valid:"required"`
+}
+
+type updateAdminRoleResp struct {
+	Admin user.User `json:"admin"`
+}
+
+// getAdminForOrganisation Returns the admin entry of the user in the organisation
+func getAdminForOrganisation(o org.Organization, userId string) (org.Admin, error) {
+	for _, admin := range o.Admins {
+		if admin.UserID == userId {
+			return admin, nil
+		}
+	}
+	return org.Admin{}, errors.New("user is not an admin of the organisation")
+}
+
+// countOrganisationAdmins Counts the admins of the organisation with admin role
+func countOrganisationAdmins(o org.Organization) int {
+	count := 0
+	for _, admin := range o.Admins {
+		if admin.RoleID == common.GetRoleID("Admin") {
+			count++
+		}
+	}
+	return count
+}
+
+// ConfigUpdateAdminRole
+func ConfigUpdateAdminRole(w http.ResponseWriter, r *http.Request) {
+	// Headers
+	organisationId := r.Header.Get(config.OrganizationId)
+	organisationId = common.Sanitize(organisationId)
+
+	userId := mux.Vars(r)[config.UserId]
+	userId = common.Sanitize(userId)
+
+	// Request body
+	var roleReq updateAdminRoleReq
+	b, _ := io.ReadAll(r.Body)
+	defer r.Body.Close()
+	json.Unmarshal(b, &roleReq)
+
+	if !common.IsValidRoleID(roleReq.RoleId) {
+		m := fmt.Sprintf("Invalid role: %v", roleReq.RoleId)
+		common.HandleErrorV2(w, http.StatusBadRequest, m, nil)
+		return
+	}
+
+	o, err := org.Get(organisationId)
+	if err != nil {
+		m := fmt.Sprintf("Failed to fetch organisation: %v", organisationId)
+		common.HandleErrorV2(w, http.StatusInternalServerError, m, err)
+		return
+	}
+
+	admin, err := getAdminForOrganisation(o, userId)
+	if err != nil {
+		m := fmt.Sprintf("Failed to fetch admin: %v", userId)
+		common.HandleErrorV2(w, http.StatusBadRequest, m, err)
+		return
+	}
+
+	// Organisation should be left with at least one admin
+	if admin.RoleID == common.GetRoleID("Admin") && roleReq.RoleId != admin.RoleID && countOrganisationAdmins(o) <= 1 {
+		m := "Role of the last admin of the organisation can't be changed"
+		common.HandleErrorV2(w, http.StatusBadRequest, m, nil)
+		return
+	}
+
+	_, err = org.UpdateAdminRole(organisationId, userId, roleReq.RoleId)
+	if err != nil {
+		m := fmt.Sprintf("Failed to update role of admin: %v", userId)
+		common.HandleErrorV2(w, http.StatusInternalServerError, m, err)
+		return
+	}
+
+	u, err := user.SetRoleForOrganization(userId, user.Role{RoleID: roleReq.RoleId, OrgID: organisationId})
+	if err != nil {
+		m := fmt.Sprintf("Failed to update role of admin: %v", userId)
+		common.HandleErrorV2(w, http.StatusInternalServerError, m, err)
+		return
+	}
+
+	resp := updateAdminRoleResp{
+		Admin: u,
+	}
+	common.ReturnHTTPResponse(resp, w)
+}
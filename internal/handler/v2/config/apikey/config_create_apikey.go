@@ -2,6 +2,7 @@ package apikey
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -11,8 +12,11 @@ import (
 	"github.com/bb-consent/api/internal/apikey"
 	"github.com/bb-consent/api/internal/common"
 	"github.com/bb-consent/api/internal/config"
-	"github.com/bb-consent/api/internal/org"
+	"github.com/bb-consent/api/internal/dataagreement"
+	"github.com/bb-consent/api/internal/token"
+	"github.com/bb-consent/api/internal/webhook"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"golang.org/x/exp/slices"
 )
 
 type addApiKeyReq struct {
@@ -23,6 +27,53 @@ type addApiKeyResp struct {
 	Apikey apikey.ApiKey `json:"apiKey" valid:"required"`
 }
 
+// validateDataAgreementIds Checks the data agreements exist in the organisation
+func validateDataAgreementIds(organisationId string, dataAgreementIds []string) error {
+	// Repository
+	darepo := dataagreement.DataAgreementRepository{}
+	darepo.Init(organisationId)
+
+	for _, dataAgreementId := range dataAgreementIds {
+		count, err := darepo.IsDataAgreementExist(dataAgreementId)
+		if err != nil {
+			return err
+		}
+		if count < 1 {
+			return fmt.Errorf("data agreement doesn't exist: %v", dataAgreementId)
+		}
+	}
+	return nil
+}
+
+// validateAgainstCallerApiKey Checks an api key doesn't get more access
+// than the api key of the caller. Scopes must be a subset of the scopes of
+// the caller and if the caller is restricted to data agreements, the api key
+// must be restricted to a subset of them.
+func validateAgainstCallerApiKey(r *http.Request, scopes []string, dataAgreementIds []string) error {
+	claims, ok := token.GetApiKeyClaims(r)
+	if !ok {
+		return nil
+	}
+
+	for _, scope := range scopes {
+		if !slices.Contains(claims.Scopes, scope) {
+			return fmt.Errorf("scope is not granted to the api key of the caller: %v", scope)
+		}
+	}
+
+	if len(claims.DataAgreementIds) > 0 {
+		if len(dataAgreementIds) == 0 {
+			return errors.New("api key of the caller is restricted to data agreements")
+		}
+		for _, dataAgreementId := range dataAgreementIds {
+			if !slices.Contains(claims.DataAgreementIds, dataAgreementId) {
+				return fmt.Errorf("data agreement is not granted to the api key of the caller: %v", dataAgreementId)
+			}
+		}
+	}
+	return nil
+}
+
 // ConfigCreateApiKey
 func ConfigCreateApiKey(w http.ResponseWriter, r *http.Request) {
 
@@ -30,13 +81,8 @@ func ConfigCreateApiKey(w http.ResponseWriter, r *http.Request) {
 	organisationId := r.Header.Get(config.OrganizationId)
 	organisationId = common.Sanitize(organisationId)
 
-	o, err := org.Get(organisationId)
-	if err != nil {
-		m := fmt.Sprintf("Failed to fetch organisation: %v", organisationId)
-		common.HandleErrorV2(w, http.StatusInternalServerError, m, err)
-		return
-	}
-	organisationAdminId := o.Admins[0].UserID
+	// Api key is attributed to the caller
+	organisationAdminId := token.GetUserID(r)
 
 	// Request body
 	var apiKeyReq addApiKeyReq
@@ -60,6 +106,22 @@ func ConfigCreateApiKey(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// validate data agreements the api key is restricted to
+	err = validateDataAgreementIds(organisationId, apiKeyReq.Apikey.DataAgreementIds)
+	if err != nil {
+		m := "Invalid data agreements provided for creating api key"
+		common.HandleErrorV2(w, http.StatusBadRequest, m, err)
+		return
+	}
+
+	// api key can't be granted more access than the api key of the caller
+	err = validateAgainstCallerApiKey(r, apiKeyReq.Apikey.Scopes, apiKeyReq.Apikey.DataAgreementIds)
+	if err != nil {
+		m := "Api key can't be granted more access than the api key of the caller"
+		common.HandleErrorV2(w, http.StatusForbidden, m, err)
+		return
+	}
+
 	// Repository
 	apiKeyRepo := apikey.ApiKeyRepository{}
 	apiKeyRepo.Init(organisationId)
@@ -73,7 +135,8 @@ func ConfigCreateApiKey(w http.ResponseWriter, r *http.Request) {
 		expiryAt = time.Now().Unix() + int64(apiKeyReq.Apikey.ExpiryInDays)*60*60*24
 	}
 
-	key, err := apikey.Create(apiKeyReq.Apikey.Scopes, expiryAt, organisationId, organisationAdminId)
+	apiKeyId := primitive.NewObjectID().Hex()
+	key, err := apikey.Generate()
 	if err != nil {
 		m := "Failed to create apiKey"
 		common.HandleError(w, http.StatusInternalServerError, m, err)
@@ -85,12 +148,15 @@ func ConfigCreateApiKey(w http.ResponseWriter, r *http.Request) {
 	expiryTimestamp := expiryTime.UTC().Format("2006-01-02T15:04:05Z")
 
 	var newApiKey apikey.ApiKey
-	newApiKey.Id = primitive.NewObjectID().Hex()
+	newApiKey.Id = apiKeyId
 	newApiKey.Name = apiKeyReq.Apikey.Name
 	newApiKey.Scopes = apiKeyReq.Apikey.Scopes
+	newApiKey.DataAgreementIds = apiKeyReq.Apikey.DataAgreementIds
 	newApiKey.Apikey = key
+	newApiKey.KeyHash = apikey.Hash(key)
 	newApiKey.ExpiryInDays = apiKeyReq.Apikey.ExpiryInDays
 	newApiKey.OrganisationId = organisationId
+	newApiKey.OrganisationAdminId = organisationAdminId
 	newApiKey.IsDeleted = false
 	newApiKey.ExpiryTimestamp = expiryTimestamp
 
@@ -101,6 +167,9 @@ func ConfigCreateApiKey(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Trigger webhooks for api key created event
+	go webhook.TriggerApiKeyWebhookEvent(apiKey, token.GetUserID(r), webhook.EventTypes[webhook.EventTypeApiKeyCreated])
+
 	resp := addApiKeyResp{
 		Apikey: apiKey,
 	}
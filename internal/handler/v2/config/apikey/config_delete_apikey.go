@@ -7,6 +7,8 @@ import (
 	"github.com/bb-consent/api/internal/apikey"
 	"github.com/bb-consent/api/internal/common"
 	"github.com/bb-consent/api/internal/config"
+	"github.com/bb-consent/api/internal/token"
+	"github.com/bb-consent/api/internal/webhook"
 	"github.com/gorilla/mux"
 )
 
@@ -41,6 +43,10 @@ func ConfigDeleteApiKey(w http.ResponseWriter, r *http.Request) {
 		common.HandleError(w, http.StatusInternalServerError, m, err)
 		return
 	}
+
+	// Trigger webhooks for api key deleted event
+	go webhook.TriggerApiKeyWebhookEvent(apiKey, token.GetUserID(r), webhook.EventTypes[webhook.EventTypeApiKeyDeleted])
+
 	resp := deleteApiKeyResp{
 		Apikey: apiKey,
 	}
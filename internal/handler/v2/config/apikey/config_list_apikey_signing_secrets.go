@@ -0,0 +1,33 @@
+package apikey
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/bb-consent/api/internal/apikey"
+	"github.com/bb-consent/api/internal/common"
+	"github.com/bb-consent/api/internal/config"
+)
+
+type listApiKeySigningSecretsResp struct {
+	SigningSecrets []apikey.SigningSecret `json:"signingSecrets"`
+}
+
+// ConfigListApiKeySigningSecrets
+func ConfigListApiKeySigningSecrets(w http.ResponseWriter, r *http.Request) {
+	// Headers
+	organisationId := r.Header.Get(config.OrganizationId)
+	organisationId = common.Sanitize(organisationId)
+
+	signingSecrets, err := apikey.ListSigningSecrets(organisationId)
+	if err != nil {
+		m := fmt.Sprintf("Failed to list api key signing secrets for organisation: %v", organisationId)
+		common.HandleErrorV2(w, http.StatusInternalServerError, m, err)
+		return
+	}
+
+	resp := listApiKeySigningSecretsResp{
+		SigningSecrets: signingSecrets,
+	}
+	common.ReturnHTTPResponse(resp, w)
+}
@@ -0,0 +1,43 @@
+package apikey
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/bb-consent/api/internal/apikey"
+	"github.com/bb-consent/api/internal/common"
+	"github.com/bb-consent/api/internal/config"
+	"github.com/gorilla/mux"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type apiKeySigningSecretResp struct {
+	SigningSecret apikey.SigningSecret `json:"signingSecret"`
+}
+
+// ConfigRetireApiKeySigningSecret
+func ConfigRetireApiKeySigningSecret(w http.ResponseWriter, r *http.Request) {
+	// Headers
+	organisationId := r.Header.Get(config.OrganizationId)
+	organisationId = common.Sanitize(organisationId)
+
+	signingSecretId := mux.Vars(r)[config.SigningSecretId]
+	signingSecretId = common.Sanitize(signingSecretId)
+
+	signingSecret, err := apikey.RetireSigningSecret(organisationId, signingSecretId)
+	if err != nil {
+		m := fmt.Sprintf("Failed to retire api key signing secret: %v", signingSecretId)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			common.HandleErrorV2(w, http.StatusBadRequest, m, err)
+			return
+		}
+		common.HandleErrorV2(w, http.StatusInternalServerError, m, err)
+		return
+	}
+
+	resp := apiKeySigningSecretResp{
+		SigningSecret: signingSecret,
+	}
+	common.ReturnHTTPResponse(resp, w)
+}
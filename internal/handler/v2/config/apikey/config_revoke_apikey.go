@@ -0,0 +1,59 @@
+package apikey
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/bb-consent/api/internal/apikey"
+	"github.com/bb-consent/api/internal/common"
+	"github.com/bb-consent/api/internal/config"
+	"github.com/bb-consent/api/internal/token"
+	"github.com/bb-consent/api/internal/webhook"
+	"github.com/gorilla/mux"
+)
+
+type revokeApiKeyResp struct {
+	Apikey apikey.ApiKey `json:"apiKey" valid:"required"`
+}
+
+// ConfigRevokeApiKey
+func ConfigRevokeApiKey(w http.ResponseWriter, r *http.Request) {
+	// Headers
+	organisationId := r.Header.Get(config.OrganizationId)
+	organisationId = common.Sanitize(organisationId)
+
+	apiKeyId := mux.Vars(r)[config.ApiKeyId]
+	apiKeyId = common.Sanitize(apiKeyId)
+
+	// Repository
+	apiKeyRepo := apikey.ApiKeyRepository{}
+	apiKeyRepo.Init(organisationId)
+	apiKey, err := apiKeyRepo.Get(apiKeyId)
+	if err != nil {
+		m := fmt.Sprintf("Failed to fetch api key: %v", apiKeyId)
+		common.HandleErrorV2(w, http.StatusBadRequest, m, err)
+		return
+	}
+
+	if !apiKey.Revoked {
+		apiKey.Revoked = true
+		apiKey.RevokedTimestamp = time.Now().UTC().Format("2006-01-02T15:04:05Z")
+
+		// Revokes api key
+		apiKey, err = apiKeyRepo.Update(apiKey)
+		if err != nil {
+			m := fmt.Sprintf("Failed to revoke api key: %v", apiKeyId)
+			common.HandleErrorV2(w, http.StatusInternalServerError, m, err)
+			return
+		}
+
+		// Trigger webhooks for api key revoked event
+		go webhook.TriggerApiKeyWebhookEvent(apiKey, token.GetUserID(r), webhook.EventTypes[webhook.EventTypeApiKeyRevoked])
+	}
+
+	resp := revokeApiKeyResp{
+		Apikey: apiKey,
+	}
+	common.ReturnHTTPResponse(resp, w)
+}
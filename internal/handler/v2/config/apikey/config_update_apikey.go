@@ -12,7 +12,8 @@ import (
 	"github.com/bb-consent/api/internal/apikey"
 	"github.com/bb-consent/api/internal/common"
 	"github.com/bb-consent/api/internal/config"
-	"github.com/bb-consent/api/internal/org"
+	"github.com/bb-consent/api/internal/token"
+	"github.com/bb-consent/api/internal/webhook"
 	"github.com/gorilla/mux"
 )
 
@@ -33,13 +34,8 @@ func ConfigUpdateApiKey(w http.ResponseWriter, r *http.Request) {
 	apiKeyId := mux.Vars(r)[config.ApiKeyId]
 	apiKeyId = common.Sanitize(apiKeyId)
 
-	o, err := org.Get(organisationId)
-	if err != nil {
-		m := fmt.Sprintf("Failed to fetch organisation: %v", organisationId)
-		common.HandleErrorV2(w, http.StatusInternalServerError, m, err)
-		return
-	}
-	organisationAdminId := o.Admins[0].UserID
+	// Api key is attributed to the caller
+	organisationAdminId := token.GetUserID(r)
 
 	// Request body
 	var apiKeyReq updateApiKeyReq
@@ -63,6 +59,22 @@ func ConfigUpdateApiKey(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// validate data agreements the api key is restricted to
+	err = validateDataAgreementIds(organisationId, apiKeyReq.Apikey.DataAgreementIds)
+	if err != nil {
+		m := "Invalid data agreements provided for updating api key"
+		common.HandleErrorV2(w, http.StatusBadRequest, m, err)
+		return
+	}
+
+	// api key can't be granted more access than the api key of the caller
+	err = validateAgainstCallerApiKey(r, apiKeyReq.Apikey.Scopes, apiKeyReq.Apikey.DataAgreementIds)
+	if err != nil {
+		m := "Api key can't be granted more access than the api key of the caller"
+		common.HandleErrorV2(w, http.StatusForbidden, m, err)
+		return
+	}
+
 	// Repository
 	apiKeyRepo := apikey.ApiKeyRepository{}
 	apiKeyRepo.Init(organisationId)
@@ -72,6 +84,11 @@ func ConfigUpdateApiKey(w http.ResponseWriter, r *http.Request) {
 		common.HandleError(w, http.StatusInternalServerError, m, err)
 		return
 	}
+	if toBeUpdatedApiKey.Revoked {
+		m := fmt.Sprintf("Revoked api key can't be updated: %v", apiKeyId)
+		common.HandleErrorV2(w, http.StatusBadRequest, m, err)
+		return
+	}
 	expiryAt := int64(apiKeyReq.Apikey.ExpiryInDays * 24 * 60 * 60)
 	if expiryAt <= 0 {
 		//Default apikey expiry 1 month
@@ -81,7 +98,7 @@ func ConfigUpdateApiKey(w http.ResponseWriter, r *http.Request) {
 		expiryAt = time.Now().Unix() + int64(apiKeyReq.Apikey.ExpiryInDays)*60*60*24
 	}
 
-	currentApiKey, err := apikey.Create(apiKeyReq.Apikey.Scopes, expiryAt, organisationId, organisationAdminId)
+	currentApiKey, err := apikey.Generate()
 	if err != nil {
 		m := "Failed to create apiKey"
 		common.HandleError(w, http.StatusInternalServerError, m, err)
@@ -97,8 +114,11 @@ func ConfigUpdateApiKey(w http.ResponseWriter, r *http.Request) {
 	}
 
 	toBeUpdatedApiKey.Apikey = currentApiKey
+	toBeUpdatedApiKey.KeyHash = apikey.Hash(currentApiKey)
+	toBeUpdatedApiKey.OrganisationAdminId = organisationAdminId
 	toBeUpdatedApiKey.ExpiryInDays = apiKeyReq.Apikey.ExpiryInDays
 	toBeUpdatedApiKey.Scopes = apiKeyReq.Apikey.Scopes
+	toBeUpdatedApiKey.DataAgreementIds = apiKeyReq.Apikey.DataAgreementIds
 	toBeUpdatedApiKey.ExpiryTimestamp = expiryTimestamp
 
 	// Updates apikey
@@ -109,6 +129,9 @@ func ConfigUpdateApiKey(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Trigger webhooks for api key updated event
+	go webhook.TriggerApiKeyWebhookEvent(savedApiKey, token.GetUserID(r), webhook.EventTypes[webhook.EventTypeApiKeyUpdated])
+
 	resp := updateApiKeyResp{
 		Apikey: savedApiKey,
 	}
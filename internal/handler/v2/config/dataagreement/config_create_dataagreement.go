@@ -15,6 +15,7 @@ import (
 	"github.com/bb-consent/api/internal/policy"
 	"github.com/bb-consent/api/internal/revision"
 	"github.com/bb-consent/api/internal/token"
+	"github.com/bb-consent/api/internal/webhook"
 	"github.com/go-playground/validator/v10"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
@@ -384,6 +385,12 @@ func ConfigCreateDataAgreement(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Trigger webhooks for data agreement created and published events
+	go webhook.TriggerDataAgreementWebhookEvent(savedDataAgreement, newRevision, orgAdminId, webhook.EventTypes[webhook.EventTypeDataAgreementCreated])
+	if savedDataAgreement.Active {
+		go webhook.TriggerDataAgreementWebhookEvent(savedDataAgreement, newRevision, orgAdminId, webhook.EventTypes[webhook.EventTypeDataAgreementPublished])
+	}
+
 	// Constructing the response
 	var resp addDataAgreementResp
 	resp.DataAgreement = savedDataAgreement
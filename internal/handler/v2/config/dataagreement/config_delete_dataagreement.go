@@ -11,6 +11,7 @@ import (
 	daRecord "github.com/bb-consent/api/internal/dataagreement_record"
 	"github.com/bb-consent/api/internal/revision"
 	"github.com/bb-consent/api/internal/token"
+	"github.com/bb-consent/api/internal/webhook"
 	"github.com/gorilla/mux"
 )
 
@@ -77,6 +78,9 @@ func ConfigDeleteDataAgreement(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Trigger webhooks for data agreement deleted event
+	go webhook.TriggerDataAgreementWebhookEvent(toBeDeletedDA, rev, orgAdminId, webhook.EventTypes[webhook.EventTypeDataAgreementDeleted])
+
 	var revisionForHTTPResponse revision.RevisionForHTTPResponse
 	revisionForHTTPResponse.Init(rev)
 
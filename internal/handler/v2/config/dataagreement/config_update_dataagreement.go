@@ -10,10 +10,12 @@ import (
 
 	"github.com/bb-consent/api/internal/common"
 	"github.com/bb-consent/api/internal/config"
+	consentrenewal "github.com/bb-consent/api/internal/consent_renewal"
 	"github.com/bb-consent/api/internal/dataagreement"
 	"github.com/bb-consent/api/internal/org"
 	"github.com/bb-consent/api/internal/revision"
 	"github.com/bb-consent/api/internal/token"
+	"github.com/bb-consent/api/internal/webhook"
 	"github.com/go-playground/validator/v10"
 	"github.com/gorilla/mux"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -288,6 +290,21 @@ func ConfigUpdateDataAgreement(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Trigger webhooks for data agreement updated event, a new version is
+	// published if a draft is made active or the version of an active data
+	// agreement is bumped
+	go webhook.TriggerDataAgreementWebhookEvent(savedDataAgreement, newRevision, orgAdminId, webhook.EventTypes[webhook.EventTypeDataAgreementUpdated])
+	isPublished := !currentDataAgreement.Active || savedDataAgreement.Version != currentVersion
+	if savedDataAgreement.Active && isPublished {
+		go webhook.TriggerDataAgreementWebhookEvent(savedDataAgreement, newRevision, orgAdminId, webhook.EventTypes[webhook.EventTypeDataAgreementPublished])
+	}
+
+	// Consent records of the previous versions are carried forward or marked
+	// for renewal when a new version is published
+	if currentDataAgreement.Active && savedDataAgreement.Active {
+		go consentrenewal.ProcessNewVersion(savedDataAgreement, newRevision)
+	}
+
 	// Constructing the response
 	var resp updateDataAgreementResp
 	resp.DataAgreement = savedDataAgreement
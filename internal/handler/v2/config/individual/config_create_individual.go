@@ -13,6 +13,7 @@ import (
 	"github.com/bb-consent/api/internal/config"
 	"github.com/bb-consent/api/internal/iam"
 	"github.com/bb-consent/api/internal/individual"
+	"github.com/bb-consent/api/internal/webhook"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
@@ -94,6 +95,9 @@ func ConfigCreateIndividual(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Trigger webhooks for individual created event
+	go webhook.TriggerIndividualWebhookEvent(savedIndividual, webhook.EventTypes[webhook.EventTypeIndividualCreated])
+
 	resp := addIndividualResp{
 		Individual: savedIndividual,
 	}
@@ -0,0 +1,140 @@
+package individual
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/asaskevich/govalidator"
+	"github.com/bb-consent/api/internal/common"
+	"github.com/bb-consent/api/internal/config"
+	"github.com/bb-consent/api/internal/guardian"
+	"github.com/bb-consent/api/internal/individual"
+	"github.com/gorilla/mux"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"golang.org/x/exp/slices"
+)
+
+// parseGuardianTimestamp Converts RFC3339 timestamp to UTC timestamp stored in db
+func parseGuardianTimestamp(timestamp string) (string, error) {
+	t, err := time.Parse(time.RFC3339, timestamp)
+	if err != nil {
+		return "", err
+	}
+	return t.UTC().Format("2006-01-02T15:04:05Z"), nil
+}
+
+// validateGuardian Validates the guardian relationship, validity timestamps
+// are converted to UTC
+func validateGuardian(organisationId string, individualId string, g guardian.Guardian) (guardian.Guardian, error) {
+	valid, err := govalidator.ValidateStruct(g)
+	if !valid {
+		return g, err
+	}
+
+	if !slices.Contains(guardian.Relationships, g.Relationship) {
+		return g, fmt.Errorf("relationship should be one of %v", strings.Join(guardian.Relationships, ", "))
+	}
+	if g.GuardianIndividualId == individualId {
+		return g, errors.New("guardian should be different from the individual")
+	}
+
+	// Repository
+	individualRepo := individual.IndividualRepository{}
+	individualRepo.Init(organisationId)
+
+	_, err = individualRepo.Get(g.GuardianIndividualId)
+	if err != nil {
+		return g, fmt.Errorf("failed to fetch guardian individual: %v", g.GuardianIndividualId)
+	}
+
+	if len(g.ValidFromTimestamp) == 0 {
+		g.ValidFromTimestamp = time.Now().UTC().Format("2006-01-02T15:04:05Z")
+	} else {
+		g.ValidFromTimestamp, err = parseGuardianTimestamp(g.ValidFromTimestamp)
+		if err != nil {
+			return g, errors.New("validFrom should be a RFC3339 timestamp")
+		}
+	}
+	if len(g.ValidUntilTimestamp) > 0 {
+		g.ValidUntilTimestamp, err = parseGuardianTimestamp(g.ValidUntilTimestamp)
+		if err != nil {
+			return g, errors.New("validUntil should be a RFC3339 timestamp")
+		}
+		if g.ValidUntilTimestamp <= g.ValidFromTimestamp {
+			return g, errors.New("validUntil should be after validFrom")
+		}
+	}
+
+	return g, nil
+}
+
+type createIndividualGuardianReq struct {
+	Guardian guardian.Guardian `json:"guardian"`
+}
+
+type createIndividualGuardianResp struct {
+	Guardian guardian.Guardian `json:"guardian"`
+}
+
+// ConfigCreateIndividualGuardian Adds a guardian who can consent on behalf of the individual
+func ConfigCreateIndividualGuardian(w http.ResponseWriter, r *http.Request) {
+	// Headers
+	organisationId := r.Header.Get(config.OrganizationId)
+	organisationId = common.Sanitize(organisationId)
+
+	individualId := mux.Vars(r)[config.IndividualId]
+	individualId = common.Sanitize(individualId)
+
+	// Request body
+	var guardianReq createIndividualGuardianReq
+	b, _ := io.ReadAll(r.Body)
+	defer r.Body.Close()
+	json.Unmarshal(b, &guardianReq)
+
+	// Repository
+	individualRepo := individual.IndividualRepository{}
+	individualRepo.Init(organisationId)
+
+	_, err := individualRepo.Get(individualId)
+	if err != nil {
+		m := fmt.Sprintf("Failed to fetch individual: %v", individualId)
+		common.HandleErrorV2(w, http.StatusBadRequest, m, err)
+		return
+	}
+
+	newGuardian, err := validateGuardian(organisationId, individualId, guardianReq.Guardian)
+	if err != nil {
+		common.HandleErrorV2(w, http.StatusBadRequest, err.Error(), err)
+		return
+	}
+	newGuardian.Id = primitive.NewObjectID().Hex()
+	newGuardian.IndividualId = individualId
+	newGuardian.OrganisationId = organisationId
+	newGuardian.IsDeleted = false
+	newGuardian.Timestamp = time.Now().UTC().Format("2006-01-02T15:04:05Z")
+
+	// Repository
+	guardianRepo := guardian.GuardianRepository{}
+	guardianRepo.Init(organisationId)
+
+	savedGuardian, err := guardianRepo.Add(newGuardian)
+	if err != nil {
+		m := fmt.Sprintf("Failed to add guardian for individual: %v", individualId)
+		common.HandleErrorV2(w, http.StatusInternalServerError, m, err)
+		return
+	}
+
+	resp := createIndividualGuardianResp{
+		Guardian: savedGuardian,
+	}
+
+	response, _ := json.Marshal(resp)
+	w.Header().Set(config.ContentTypeHeader, config.ContentTypeJSON)
+	w.WriteHeader(http.StatusOK)
+	w.Write(response)
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/bb-consent/api/internal/config"
 	"github.com/bb-consent/api/internal/iam"
 	"github.com/bb-consent/api/internal/individual"
+	"github.com/bb-consent/api/internal/webhook"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 )
@@ -126,10 +127,12 @@ func createIndividuals(individuals []individual.Individual, organisationId strin
 				individual.IamId = iamId
 				individual.Id = primitive.NewObjectID().Hex()
 				// Save individual to db
-				_, err = individualRepo.Add(individual)
+				savedIndividual, err := individualRepo.Add(individual)
 				if err != nil {
 					log.Printf("Unable to save individual in db: %v", individual.Email)
+					continue
 				}
+				go webhook.TriggerIndividualWebhookEvent(savedIndividual, webhook.EventTypes[webhook.EventTypeIndividualCreated])
 			} else {
 				log.Printf("Unable to fetch individual in db: %v", individual.Name)
 			}
@@ -144,10 +147,12 @@ func createIndividuals(individuals []individual.Individual, organisationId strin
 				u.Phone = individual.Phone
 			}
 			// Update individual to db
-			_, err = individualRepo.Update(u)
+			savedIndividual, err := individualRepo.Update(u)
 			if err != nil {
 				log.Printf("Unable to update individual in db: %v", u.Id)
+				continue
 			}
+			go webhook.TriggerIndividualWebhookEvent(savedIndividual, webhook.EventTypes[webhook.EventTypeIndividualUpdated])
 		}
 
 	}
@@ -0,0 +1,58 @@
+package individual
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/bb-consent/api/internal/common"
+	"github.com/bb-consent/api/internal/config"
+	"github.com/bb-consent/api/internal/guardian"
+	"github.com/gorilla/mux"
+)
+
+type deleteIndividualGuardianResp struct {
+	Guardian guardian.Guardian `json:"guardian"`
+}
+
+// ConfigDeleteIndividualGuardian Removes the guardian, revisions authorized
+// by the guardian earlier are retained
+func ConfigDeleteIndividualGuardian(w http.ResponseWriter, r *http.Request) {
+	// Headers
+	organisationId := r.Header.Get(config.OrganizationId)
+	organisationId = common.Sanitize(organisationId)
+
+	individualId := mux.Vars(r)[config.IndividualId]
+	individualId = common.Sanitize(individualId)
+
+	guardianId := mux.Vars(r)[config.GuardianId]
+	guardianId = common.Sanitize(guardianId)
+
+	// Repository
+	guardianRepo := guardian.GuardianRepository{}
+	guardianRepo.Init(organisationId)
+
+	toBeDeletedGuardian, err := guardianRepo.Get(individualId, guardianId)
+	if err != nil {
+		m := fmt.Sprintf("Failed to fetch guardian: %v of individual: %v", guardianId, individualId)
+		common.HandleErrorV2(w, http.StatusNotFound, m, err)
+		return
+	}
+
+	toBeDeletedGuardian.IsDeleted = true
+	deletedGuardian, err := guardianRepo.Update(toBeDeletedGuardian)
+	if err != nil {
+		m := fmt.Sprintf("Failed to delete guardian: %v of individual: %v", guardianId, individualId)
+		common.HandleErrorV2(w, http.StatusInternalServerError, m, err)
+		return
+	}
+
+	resp := deleteIndividualGuardianResp{
+		Guardian: deletedGuardian,
+	}
+
+	response, _ := json.Marshal(resp)
+	w.Header().Set(config.ContentTypeHeader, config.ContentTypeJSON)
+	w.WriteHeader(http.StatusOK)
+	w.Write(response)
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/bb-consent/api/internal/config"
 	"github.com/bb-consent/api/internal/iam"
 	"github.com/bb-consent/api/internal/individual"
+	"github.com/bb-consent/api/internal/webhook"
 	"github.com/gorilla/mux"
 )
 
@@ -52,6 +53,9 @@ func ConfigDeleteIndividual(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Trigger webhooks for individual deleted event
+	go webhook.TriggerIndividualWebhookEvent(savedIndividual, webhook.EventTypes[webhook.EventTypeIndividualDeleted])
+
 	resp := deleteIndividualResp{
 		Individual: savedIndividual,
 	}
@@ -0,0 +1,60 @@
+package individual
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/bb-consent/api/internal/common"
+	"github.com/bb-consent/api/internal/config"
+	"github.com/bb-consent/api/internal/erasure"
+	"github.com/bb-consent/api/internal/individual"
+	"github.com/bb-consent/api/internal/token"
+	"github.com/bb-consent/api/internal/webhook"
+	"github.com/gorilla/mux"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type eraseIndividualResp struct {
+	Receipt       erasure.Receipt `json:"receipt"`
+	SignedReceipt string          `json:"signedReceipt"`
+}
+
+// ConfigEraseIndividual Erases the individual and the consent records of
+// forgettable data agreements, returns a signed erasure receipt
+func ConfigEraseIndividual(w http.ResponseWriter, r *http.Request) {
+	// Headers
+	organisationId := common.Sanitize(r.Header.Get(config.OrganizationId))
+	individualId := common.Sanitize(mux.Vars(r)[config.IndividualId])
+
+	requestedBy := token.GetUserID(r)
+	if len(requestedBy) == 0 {
+		requestedBy = organisationId
+	}
+
+	receipt, err := erasure.EraseIndividual(organisationId, individualId, requestedBy)
+	if err == mongo.ErrNoDocuments {
+		m := fmt.Sprintf("Failed to fetch individual: %v", individualId)
+		common.HandleErrorV2(w, http.StatusNotFound, m, err)
+		return
+	}
+	if err != nil {
+		m := fmt.Sprintf("Failed to erase individual: %v", individualId)
+		common.HandleErrorV2(w, http.StatusInternalServerError, m, err)
+		return
+	}
+
+	// Only the IDs of the erased individual are published
+	erasedIndividual := individual.Individual{Id: individualId, OrganisationId: organisationId}
+	go webhook.TriggerIndividualWebhookEvent(erasedIndividual, webhook.EventTypes[webhook.EventTypeIndividualDeleted])
+
+	resp := eraseIndividualResp{
+		Receipt:       receipt,
+		SignedReceipt: receipt.SignedReceipt,
+	}
+
+	response, _ := json.Marshal(resp)
+	w.Header().Set(config.ContentTypeHeader, config.ContentTypeJSON)
+	w.WriteHeader(http.StatusOK)
+	w.Write(response)
+}
@@ -0,0 +1,41 @@
+package individual
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/bb-consent/api/internal/common"
+	"github.com/bb-consent/api/internal/config"
+	"github.com/bb-consent/api/internal/guardian"
+	"github.com/gorilla/mux"
+)
+
+type listIndividualGuardiansResp struct {
+	Guardians []guardian.Guardian `json:"guardians"`
+}
+
+// ConfigListIndividualGuardians Lists the guardians of the individual
+func ConfigListIndividualGuardians(w http.ResponseWriter, r *http.Request) {
+	// Headers
+	organisationId := r.Header.Get(config.OrganizationId)
+	organisationId = common.Sanitize(organisationId)
+
+	individualId := mux.Vars(r)[config.IndividualId]
+	individualId = common.Sanitize(individualId)
+
+	// Repository
+	guardianRepo := guardian.GuardianRepository{}
+	guardianRepo.Init(organisationId)
+
+	guardians, err := guardianRepo.ListByIndividualId(individualId)
+	if err != nil {
+		m := fmt.Sprintf("Failed to fetch guardians of individual: %v", individualId)
+		common.HandleErrorV2(w, http.StatusInternalServerError, m, err)
+		return
+	}
+
+	resp := listIndividualGuardiansResp{
+		Guardians: guardians,
+	}
+	common.ReturnHTTPResponse(resp, w)
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/bb-consent/api/internal/config"
 	"github.com/bb-consent/api/internal/iam"
 	"github.com/bb-consent/api/internal/individual"
+	"github.com/bb-consent/api/internal/webhook"
 	"github.com/gorilla/mux"
 )
 
@@ -120,6 +121,9 @@ func ConfigUpdateIndividual(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Trigger webhooks for individual updated event
+	go webhook.TriggerIndividualWebhookEvent(savedIndividual, webhook.EventTypes[webhook.EventTypeIndividualUpdated])
+
 	resp := updateIndividualResp{
 		Individual: savedIndividual,
 	}
@@ -0,0 +1,79 @@
+package individual
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/bb-consent/api/internal/common"
+	"github.com/bb-consent/api/internal/config"
+	"github.com/bb-consent/api/internal/guardian"
+	"github.com/gorilla/mux"
+)
+
+type updateIndividualGuardianReq struct {
+	Guardian guardian.Guardian `json:"guardian"`
+}
+
+type updateIndividualGuardianResp struct {
+	Guardian guardian.Guardian `json:"guardian"`
+}
+
+// ConfigUpdateIndividualGuardian Updates the relationship, evidence and validity of the guardian
+func ConfigUpdateIndividualGuardian(w http.ResponseWriter, r *http.Request) {
+	// Headers
+	organisationId := r.Header.Get(config.OrganizationId)
+	organisationId = common.Sanitize(organisationId)
+
+	individualId := mux.Vars(r)[config.IndividualId]
+	individualId = common.Sanitize(individualId)
+
+	guardianId := mux.Vars(r)[config.GuardianId]
+	guardianId = common.Sanitize(guardianId)
+
+	// Request body
+	var guardianReq updateIndividualGuardianReq
+	b, _ := io.ReadAll(r.Body)
+	defer r.Body.Close()
+	json.Unmarshal(b, &guardianReq)
+
+	// Repository
+	guardianRepo := guardian.GuardianRepository{}
+	guardianRepo.Init(organisationId)
+
+	toBeUpdatedGuardian, err := guardianRepo.Get(individualId, guardianId)
+	if err != nil {
+		m := fmt.Sprintf("Failed to fetch guardian: %v of individual: %v", guardianId, individualId)
+		common.HandleErrorV2(w, http.StatusNotFound, m, err)
+		return
+	}
+
+	// Guardian individual can't be changed
+	guardianReq.Guardian.GuardianIndividualId = toBeUpdatedGuardian.GuardianIndividualId
+	updatedGuardian, err := validateGuardian(organisationId, individualId, guardianReq.Guardian)
+	if err != nil {
+		common.HandleErrorV2(w, http.StatusBadRequest, err.Error(), err)
+		return
+	}
+	toBeUpdatedGuardian.Relationship = updatedGuardian.Relationship
+	toBeUpdatedGuardian.Evidence = updatedGuardian.Evidence
+	toBeUpdatedGuardian.ValidFromTimestamp = updatedGuardian.ValidFromTimestamp
+	toBeUpdatedGuardian.ValidUntilTimestamp = updatedGuardian.ValidUntilTimestamp
+
+	savedGuardian, err := guardianRepo.Update(toBeUpdatedGuardian)
+	if err != nil {
+		m := fmt.Sprintf("Failed to update guardian: %v of individual: %v", guardianId, individualId)
+		common.HandleErrorV2(w, http.StatusInternalServerError, m, err)
+		return
+	}
+
+	resp := updateIndividualGuardianResp{
+		Guardian: savedGuardian,
+	}
+
+	response, _ := json.Marshal(resp)
+	w.Header().Set(config.ContentTypeHeader, config.ContentTypeJSON)
+	w.WriteHeader(http.StatusOK)
+	w.Write(response)
+}
@@ -15,6 +15,7 @@ import (
 	"github.com/bb-consent/api/internal/policy"
 	"github.com/bb-consent/api/internal/revision"
 	"github.com/bb-consent/api/internal/token"
+	"github.com/bb-consent/api/internal/webhook"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
@@ -144,6 +145,9 @@ func ConfigCreatePolicy(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Trigger webhooks for policy created event
+	go webhook.TriggerPolicyWebhookEvent(savedPolicy, savedRevision, orgAdminId, webhook.EventTypes[webhook.EventTypePolicyCreated])
+
 	count, err := prepo.GetPolicyCountByOrganisation()
 	if err != nil {
 		m := "Failed to count policies"
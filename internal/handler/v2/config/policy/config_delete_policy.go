@@ -9,11 +9,16 @@ import (
 	"github.com/bb-consent/api/internal/config"
 	"github.com/bb-consent/api/internal/policy"
 	"github.com/bb-consent/api/internal/revision"
+	"github.com/bb-consent/api/internal/token"
+	"github.com/bb-consent/api/internal/webhook"
 	"github.com/gorilla/mux"
 )
 
 // ConfigDeletePolicy
 func ConfigDeletePolicy(w http.ResponseWriter, r *http.Request) {
+	// Current user
+	orgAdminId := token.GetUserID(r)
+
 	organisationId := r.Header.Get(config.OrganizationId)
 	organisationId = common.Sanitize(organisationId)
 	policyId := mux.Vars(r)[config.PolicyId]
@@ -46,6 +51,9 @@ func ConfigDeletePolicy(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Trigger webhooks for policy deleted event
+	go webhook.TriggerPolicyWebhookEvent(currentPolicy, currentRevision, orgAdminId, webhook.EventTypes[webhook.EventTypePolicyDeleted])
+
 	var revisionForHTTPResponse revision.RevisionForHTTPResponse
 	revisionForHTTPResponse.Init(currentRevision)
 
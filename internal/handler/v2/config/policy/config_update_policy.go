@@ -14,6 +14,7 @@ import (
 	"github.com/bb-consent/api/internal/policy"
 	"github.com/bb-consent/api/internal/revision"
 	"github.com/bb-consent/api/internal/token"
+	"github.com/bb-consent/api/internal/webhook"
 	"github.com/gorilla/mux"
 )
 
@@ -126,6 +127,9 @@ func ConfigUpdatePolicy(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Trigger webhooks for policy updated event
+	go webhook.TriggerPolicyWebhookEvent(savedPolicy, newRevision, orgAdminId, webhook.EventTypes[webhook.EventTypePolicyUpdated])
+
 	policyRepo.Init(organisationId)
 	first_policy, err := policyRepo.GetFirstPolicy()
 	if err != nil {
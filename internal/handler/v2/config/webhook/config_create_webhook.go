@@ -12,8 +12,10 @@ import (
 	"github.com/asaskevich/govalidator"
 	"github.com/bb-consent/api/internal/common"
 	"github.com/bb-consent/api/internal/config"
+	"github.com/bb-consent/api/internal/dataagreement"
 	wh "github.com/bb-consent/api/internal/webhook"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"golang.org/x/exp/slices"
 )
 
 // uniqueSlice Filter out all the duplicate strings and returns the unique slice
@@ -29,16 +31,70 @@ func uniqueSlice(inputSlice []string) []string {
 	return list
 }
 
-func updateWebhookFromAddWebhookRequestBody(requestBody addWebhookReq, newWebhook wh.Webhook) wh.Webhook {
+// validateWebhookFilter Validates the data agreements, lawful basis and method of use the webhook is filtered on
+func validateWebhookFilter(filter wh.WebhookFilter, organisationId string) error {
+	// Repository
+	darepo := dataagreement.DataAgreementRepository{}
+	darepo.Init(organisationId)
+
+	for _, dataAgreementId := range filter.DataAgreementIds {
+		count, err := darepo.IsDataAgreementExist(dataAgreementId)
+		if err != nil {
+			return err
+		}
+		if count < 1 {
+			return fmt.Errorf("data agreement doesn't exist: %v", dataAgreementId)
+		}
+	}
+
+	lawfulBases := []string{config.LawfulBasisConsent, config.LawfulBasisContract, config.LawfulBasisLegalObligation, config.LawfulBasisVitalInterest, config.LawfulBasisPublicTask, config.LawfulBasisLegitimateInterest}
+	for _, lawfulBasis := range filter.LawfulBasis {
+		if !slices.Contains(lawfulBases, lawfulBasis) {
+			return fmt.Errorf("please provide a valid lawful basis: %v", lawfulBasis)
+		}
+	}
+
+	methodsOfUse := []string{config.Null, config.DataSource, config.DataUsingService}
+	for _, methodOfUse := range filter.MethodOfUse {
+		if !slices.Contains(methodsOfUse, methodOfUse) {
+			return fmt.Errorf("please provide a valid method of use: %v", methodOfUse)
+		}
+	}
+
+	return nil
+}
+
+func updateWebhookFromAddWebhookRequestBody(requestBody addWebhookReq, newWebhook wh.Webhook) (wh.Webhook, error) {
 	newWebhook.PayloadURL = requestBody.Webhook.PayloadURL
 	newWebhook.ContentType = requestBody.Webhook.ContentType
 	newWebhook.SubscribedEvents = requestBody.Webhook.SubscribedEvents
 	newWebhook.Disabled = requestBody.Webhook.Disabled
 	newWebhook.SecretKey = requestBody.Webhook.SecretKey
 	newWebhook.SkipSSLVerification = requestBody.Webhook.SkipSSLVerification
+	newWebhook.SignatureScheme = requestBody.Webhook.SignatureScheme
+	if len(newWebhook.SignatureScheme) == 0 {
+		newWebhook.SignatureScheme = wh.SignatureSchemeIGrant
+	}
+	newWebhook.Filter = wh.WebhookFilter{
+		DataAgreementIds: uniqueSlice(requestBody.Webhook.Filter.DataAgreementIds),
+		LawfulBasis:      uniqueSlice(requestBody.Webhook.Filter.LawfulBasis),
+		MethodOfUse:      uniqueSlice(requestBody.Webhook.Filter.MethodOfUse),
+	}
 	newWebhook.TimeStamp = time.Now().UTC().Format("2006-01-02T15:04:05Z")
 
-	return newWebhook
+	newWebhook.CACertificates = requestBody.Webhook.CACertificates
+	newWebhook.Headers = requestBody.Webhook.Headers
+	newWebhook.ClientCertificate = requestBody.Webhook.ClientCertificate
+	if len(requestBody.Webhook.ClientKey) > 0 {
+		// Client key is stored encrypted
+		encryptedClientKey, err := wh.EncryptSecret(requestBody.Webhook.ClientKey)
+		if err != nil {
+			return newWebhook, err
+		}
+		newWebhook.ClientKey = encryptedClientKey
+	}
+
+	return newWebhook, nil
 }
 
 func validateAddwebhookRequestBody(webhookReq addWebhookReq, organisationId string, webhhokRepo wh.WebhookRepository) error {
@@ -53,6 +109,33 @@ func validateAddwebhookRequestBody(webhookReq addWebhookReq, organisationId stri
 		return errors.New("please prefix the endpoint URL with https:// or http://;")
 	}
 
+	// Check if the outbound network policy allows the webhook endpoint
+	err = wh.ValidateWebhookURL(webhookReq.Webhook.PayloadURL)
+	if err != nil {
+		return err
+	}
+
+	err = wh.ValidateSkipSSLVerification(webhookReq.Webhook.SkipSSLVerification)
+	if err != nil {
+		return err
+	}
+
+	// Check if the mutual TLS configuration and custom headers are valid
+	err = wh.ValidateClientCertificate(webhookReq.Webhook.ClientCertificate, webhookReq.Webhook.ClientKey)
+	if err != nil {
+		return err
+	}
+
+	err = wh.ValidateCACertificates(webhookReq.Webhook.CACertificates)
+	if err != nil {
+		return err
+	}
+
+	err = wh.ValidateHeaders(webhookReq.Webhook.Headers)
+	if err != nil {
+		return err
+	}
+
 	// Check if webhook with provided payload URL already exists
 	count, err := webhhokRepo.GetWebhookCountByPayloadURL(webhookReq.Webhook.PayloadURL)
 	if err != nil {
@@ -102,7 +185,13 @@ func validateAddwebhookRequestBody(webhookReq addWebhookReq, organisationId stri
 	if !isValidContentType {
 		return errors.New("please provide a valid content type")
 	}
-	return nil
+
+	// Check if the signature scheme provided is valid
+	if len(webhookReq.Webhook.SignatureScheme) > 0 && !slices.Contains(wh.SignatureSchemes, webhookReq.Webhook.SignatureScheme) {
+		return errors.New("please provide a valid signature scheme")
+	}
+
+	return validateWebhookFilter(webhookReq.Webhook.Filter, organisationId)
 }
 
 type addWebhookReq struct {
@@ -138,7 +227,12 @@ func ConfigCreateWebhook(w http.ResponseWriter, r *http.Request) {
 
 	var newWebhook wh.Webhook
 	newWebhook.ID = primitive.NewObjectID().Hex()
-	newWebhook = updateWebhookFromAddWebhookRequestBody(webhookReq, newWebhook)
+	newWebhook, err = updateWebhookFromAddWebhookRequestBody(webhookReq, newWebhook)
+	if err != nil {
+		m := fmt.Sprintf("Failed to create webhook for organisation:%v", organisationId)
+		common.HandleError(w, http.StatusInternalServerError, m, err)
+		return
+	}
 	newWebhook.OrganisationId = organisationId
 	newWebhook.IsDeleted = false
 
@@ -150,6 +244,9 @@ func ConfigCreateWebhook(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Client key and custom header values aren't returned
+	webhook.ClientKey = ""
+	webhook.Headers = wh.RedactHeaders(webhook.Headers)
 	resp := addWebhookResp{
 		Webhook: webhook,
 	}
@@ -47,6 +47,9 @@ func ConfigDeleteWebhook(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Client key and custom header values aren't returned
+	savedWebhook.ClientKey = ""
+	savedWebhook.Headers = wh.RedactHeaders(savedWebhook.Headers)
 	resp := deleteWebhookResp{
 		Webhook: savedWebhook,
 	}
@@ -1,6 +1,7 @@
 package webhook
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"log"
@@ -15,21 +16,16 @@ import (
 
 // recentWebhookDelivery Defines the structure for recent webhook delivery
 type recentWebhookDelivery struct {
-	Id                 string `json:"id" bson:"_id,omitempty"` // Webhook delivery ID
-	WebhookId          string `json:"webhookId"`               // Webhook ID
-	ResponseStatusCode int    `json:"responseStatusCode"`      // HTTP response status code
-	ResponseStatusStr  string `json:"responseStatusStr"`       // HTTP response status string
-	TimeStamp          string `json:"timestamp"`               // UTC timestamp when webhook execution started
-	Status             string `json:"status"`                  // Status of webhook delivery for e.g. failed or completed
-	StatusDescription  string `json:"statusDescription"`       // Describe the status for e.g. Reason for failure
-}
-
-func webhookDeliveriesToInterfaceSlice(webhookdeliveries []recentWebhookDelivery) []interface{} {
-	interfaceSlice := make([]interface{}, len(webhookdeliveries))
-	for i, r := range webhookdeliveries {
-		interfaceSlice[i] = r
-	}
-	return interfaceSlice
+	Id                   string `json:"id" bson:"_id,omitempty"` // Webhook delivery ID
+	WebhookId            string `json:"webhookId"`               // Webhook ID
+	EventType            string `json:"eventType"`               // Webhook event type for e.g. consent.allowed
+	ResponseStatusCode   int    `json:"responseStatusCode"`      // HTTP response status code
+	ResponseStatusStr    string `json:"responseStatusStr"`       // HTTP response status string
+	TimeStamp            string `json:"timestamp"`               // UTC timestamp when webhook execution started
+	Status               string `json:"status"`                  // Status of webhook delivery for e.g. failed or succeeded
+	StatusDescription    string `json:"statusDescription"`       // Describe the status for e.g. Reason for failure
+	Attempts             int    `json:"attempts"`                // Number of delivery attempts made
+	NextAttemptTimeStamp string `json:"nextAttemptTimestamp"`    // UTC timestamp after which the delivery is attempted again
 }
 
 type listWebhookDeliveriesResp struct {
@@ -62,8 +58,30 @@ func ConfigListRecentWebhookDeliveries(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Get all recent webhook deliveries
-	recentWebhookDeliveries, err := wh.GetAllDeliveryByWebhookId(webhook.ID)
+	// Filters
+	filter := wh.WebhookDeliveryFilter{
+		Status:             r.URL.Query().Get("status"),
+		EventType:          r.URL.Query().Get("eventType"),
+		ResponseStatusCode: r.URL.Query().Get("responseStatusCode"),
+		FromTimeStamp:      r.URL.Query().Get("fromTimestamp"),
+		ToTimeStamp:        r.URL.Query().Get("toTimestamp"),
+	}
+	err = filter.Validate()
+	if err != nil {
+		common.HandleErrorV2(w, http.StatusBadRequest, err.Error(), err)
+		return
+	}
+
+	// Get recent webhook deliveries matching the filters
+	var recentWebhookDeliveries []wh.WebhookDelivery
+	query := paginate.PaginateDBObjectsQueryUsingPipeline{
+		Pipeline:   wh.SearchDeliveriesPipeline(webhook.ID, filter),
+		Collection: wh.WebhookDeliveryCollection(),
+		Context:    context.Background(),
+		Limit:      limit,
+		Offset:     offset,
+	}
+	result, err := paginate.PaginateDBObjectsUsingPipeline(query, &recentWebhookDeliveries)
 	if err != nil {
 		m := fmt.Sprintf("Failed to fetch recent payload deliveries for webhook:%v for organisation: %v", webhookId, organisationId)
 		common.HandleError(w, http.StatusInternalServerError, m, err)
@@ -71,32 +89,28 @@ func ConfigListRecentWebhookDeliveries(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Constructing the response
-	var webhookDeliveries []recentWebhookDelivery
+	webhookDeliveries := []recentWebhookDelivery{}
 
 	for _, wd := range recentWebhookDeliveries {
 
 		tempRecentWebhookDelivery := recentWebhookDelivery{
-			Id:                 wd.ID,
-			WebhookId:          wd.WebhookID,
-			ResponseStatusCode: wd.ResponseStatusCode,
-			ResponseStatusStr:  wd.ResponseStatusStr,
-			TimeStamp:          wd.ExecutionStartTimeStamp,
-			Status:             wd.Status,
-			StatusDescription:  wd.StatusDescription,
+			Id:                   wd.ID,
+			WebhookId:            wd.WebhookID,
+			EventType:            wd.WebhookEventType,
+			ResponseStatusCode:   wd.ResponseStatusCode,
+			ResponseStatusStr:    wd.ResponseStatusStr,
+			TimeStamp:            wd.ExecutionStartTimeStamp,
+			Status:               wd.Status,
+			StatusDescription:    wd.StatusDescription,
+			Attempts:             wd.Attempts,
+			NextAttemptTimeStamp: wd.NextAttemptTimeStamp,
 		}
 
 		webhookDeliveries = append(webhookDeliveries, tempRecentWebhookDelivery)
 	}
 
-	query := paginate.PaginateObjectsQuery{
-		Limit:  limit,
-		Offset: offset,
-	}
-
-	interfaceSlice := webhookDeliveriesToInterfaceSlice(webhookDeliveries)
-	result := paginate.PaginateObjects(query, interfaceSlice)
 	var resp = listWebhookDeliveriesResp{
-		WebhookDeliveries: result.Items,
+		WebhookDeliveries: webhookDeliveries,
 		Pagination:        result.Pagination,
 	}
 
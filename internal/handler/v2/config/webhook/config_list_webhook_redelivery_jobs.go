@@ -0,0 +1,66 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/bb-consent/api/internal/common"
+	"github.com/bb-consent/api/internal/config"
+	"github.com/bb-consent/api/internal/paginate"
+	wh "github.com/bb-consent/api/internal/webhook"
+	"github.com/gorilla/mux"
+)
+
+type listWebhookRedeliveryJobsResp struct {
+	RedeliveryJobs interface{}         `json:"redeliveryJobs"`
+	Pagination     paginate.Pagination `json:"pagination"`
+}
+
+// ConfigListWebhookRedeliveryJobs Lists redelivery jobs of a webhook
+func ConfigListWebhookRedeliveryJobs(w http.ResponseWriter, r *http.Request) {
+	// Headers
+	organisationId := r.Header.Get(config.OrganizationId)
+	organisationId = common.Sanitize(organisationId)
+
+	webhookId := mux.Vars(r)[config.WebhookId]
+	webhookId = common.Sanitize(webhookId)
+
+	// Query params
+	offset, limit := paginate.ParsePaginationQueryParams(r)
+
+	// Repository
+	jobRepo := wh.WebhookRedeliveryJobRepository{}
+	jobRepo.Init(organisationId)
+
+	if err := jobRepo.FailInterrupted(); err != nil {
+		log.Printf("Failed to mark interrupted redelivery jobs as failed for organisation: %v err: %v", organisationId, err)
+	}
+
+	var redeliveryJobs []wh.WebhookRedeliveryJob
+	query := paginate.PaginateDBObjectsQueryUsingPipeline{
+		Pipeline:   jobRepo.ListPipeline(webhookId),
+		Collection: wh.WebhookRedeliveryJobCollection(),
+		Context:    context.Background(),
+		Limit:      limit,
+		Offset:     offset,
+	}
+	result, err := paginate.PaginateDBObjectsUsingPipeline(query, &redeliveryJobs)
+	if err != nil {
+		m := fmt.Sprintf("Failed to list redelivery jobs for webhook:%v for organisation: %v", webhookId, organisationId)
+		common.HandleErrorV2(w, http.StatusInternalServerError, m, err)
+		return
+	}
+
+	resp := listWebhookRedeliveryJobsResp{
+		RedeliveryJobs: result.Items,
+		Pagination:     result.Pagination,
+	}
+
+	response, _ := json.Marshal(resp)
+	w.Header().Set(config.ContentTypeHeader, config.ContentTypeJSON)
+	w.WriteHeader(http.StatusOK)
+	w.Write(response)
+}
@@ -14,16 +14,22 @@ import (
 
 // WebhookWithLastDeliveryStatus Defines webhook structure along with last delivery status
 type WebhookWithLastDeliveryStatus struct {
-	ID                    string   `json:"id" bson:"_id,omitempty"` // Webhook ID
-	OrganisationId        string   `json:"orgId" bson:"orgid"`
-	PayloadURL            string   `json:"payloadUrl"`            // Webhook payload URL
-	ContentType           string   `json:"contentType"`           // Webhook payload content type for e.g application/json
-	SubscribedEvents      []string `json:"subscribedEvents"`      // Events subscribed for e.g. user.data.delete
-	Disabled              bool     `json:"disabled"`              // Disabled or not
-	SecretKey             string   `json:"secretKey"`             // For calculating SHA256 HMAC to verify data integrity and authenticity
-	SkipSSLVerification   bool     `json:"skipSslVerification"`   // Skip SSL certificate verification or not (expiry is checked)
-	TimeStamp             string   `json:"timestamp"`             // UTC timestamp
-	IsLastDeliverySuccess bool     `json:"isLastDeliverySuccess"` // Indicates whether last payload delivery to webhook was success or not
+	ID                    string             `json:"id" bson:"_id,omitempty"` // Webhook ID
+	OrganisationId        string             `json:"orgId" bson:"orgid"`
+	PayloadURL            string             `json:"payloadUrl"`            // Webhook payload URL
+	ContentType           string             `json:"contentType"`           // Webhook payload content type for e.g application/json
+	SubscribedEvents      []string           `json:"subscribedEvents"`      // Events subscribed for e.g. user.data.delete
+	Disabled              bool               `json:"disabled"`              // Disabled or not
+	SecretKey             string             `json:"secretKey"`             // For calculating SHA256 HMAC to verify data integrity and authenticity
+	SkipSSLVerification   bool               `json:"skipSslVerification"`   // Skip SSL certificate verification or not (expiry is checked)
+	TimeStamp             string             `json:"timestamp"`             // UTC timestamp
+	IsLastDeliverySuccess bool               `json:"isLastDeliverySuccess"` // Indicates whether last payload delivery to webhook was success or not
+	Filter                wh.WebhookFilter   `json:"filter"`                // Restricts the events delivered to the webhook
+	PreviousSecrets       []wh.WebhookSecret `json:"previousSecrets"`       // Previous secrets which are used for signing until they expire
+	SignatureScheme       string             `json:"signatureScheme"`       // Scheme for signing payloads for e.g. igrant or standard-webhooks
+	ClientCertificate     string             `json:"clientCertificate"`     // PEM encoded client certificate for mutual TLS
+	CACertificates        string             `json:"caCertificates"`        // PEM encoded CA certificates for verifying the endpoint
+	Headers               map[string]string  `json:"headers"`               // Custom headers posted to webhook endpoint
 }
 
 func webhooksToInterfaceSlice(webhooks []WebhookWithLastDeliveryStatus) []interface{} {
@@ -62,6 +68,9 @@ func ConfigListWebhooks(w http.ResponseWriter, r *http.Request) {
 	updatedWebhooks = make([]WebhookWithLastDeliveryStatus, 0)
 
 	for _, webhook := range webhooks {
+		if !canReadWebhookSecrets(r) {
+			webhook = wh.RedactSecrets(webhook)
+		}
 
 		// Fetching the last delivery to the webhook and retrieving the delivery status
 		isLastDeliverySuccess := false
@@ -70,8 +79,9 @@ func ConfigListWebhooks(w http.ResponseWriter, r *http.Request) {
 			// There is no payload delivery yet !
 			isLastDeliverySuccess = true
 		} else {
-			// if the last payload delivery is completed and response status code is within 2XX range
-			if lastDelivery.Status == wh.DeliveryStatus[wh.DeliveryStatusCompleted] {
+			// if the last payload delivery is succeeded (or completed for deliveries recorded
+			// before retries were introduced) and response status code is within 2XX range
+			if lastDelivery.Status == wh.DeliveryStatus[wh.DeliveryStatusSucceeded] || lastDelivery.Status == wh.DeliveryStatus[wh.DeliveryStatusCompleted] {
 				if (lastDelivery.ResponseStatusCode >= 200 && lastDelivery.ResponseStatusCode <= 208) || lastDelivery.ResponseStatusCode == 226 {
 					isLastDeliverySuccess = true
 				}
@@ -90,6 +100,12 @@ func ConfigListWebhooks(w http.ResponseWriter, r *http.Request) {
 			SkipSSLVerification:   webhook.SkipSSLVerification,
 			TimeStamp:             webhook.TimeStamp,
 			IsLastDeliverySuccess: isLastDeliverySuccess,
+			Filter:                webhook.Filter,
+			PreviousSecrets:       webhook.PreviousSecrets,
+			SignatureScheme:       webhook.SignatureScheme,
+			ClientCertificate:     webhook.ClientCertificate,
+			CACertificates:        webhook.CACertificates,
+			Headers:               wh.RedactHeaders(webhook.Headers),
 		}
 
 		updatedWebhooks = append(updatedWebhooks, updatedWebhook)
@@ -0,0 +1,154 @@
+package webhook
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/bb-consent/api/internal/common"
+	"github.com/bb-consent/api/internal/config"
+	"github.com/bb-consent/api/internal/paginate"
+	wh "github.com/bb-consent/api/internal/webhook"
+)
+
+// Webhook health status
+const (
+	webhookHealthHealthy  = "healthy"
+	webhookHealthDegraded = "degraded"
+	webhookHealthDisabled = "disabled"
+)
+
+// webhookHealth Defines the health summary of a webhook
+type webhookHealth struct {
+	Id                   string  `json:"id"`                   // Webhook ID
+	PayloadURL           string  `json:"payloadUrl"`           // Webhook payload URL
+	Status               string  `json:"status"`               // Health of the webhook for e.g. healthy, degraded or disabled
+	Disabled             bool    `json:"disabled"`             // Disabled or not
+	DisabledReason       string  `json:"disabledReason"`       // Reason for the webhook to be disabled automatically
+	ConsecutiveFailures  int     `json:"consecutiveFailures"`  // Failed delivery attempts in a row
+	LastSuccessTimeStamp string  `json:"lastSuccessTimestamp"` // UTC timestamp of last successful delivery attempt
+	LastFailureTimeStamp string  `json:"lastFailureTimestamp"` // UTC timestamp of last failed delivery attempt
+	TotalDeliveries      int64   `json:"totalDeliveries"`      // Number of deliveries to the webhook
+	SucceededDeliveries  int64   `json:"succeededDeliveries"`  // Number of deliveries which succeeded
+	FailedDeliveries     int64   `json:"failedDeliveries"`     // Number of deliveries which failed
+	PendingDeliveries    int64   `json:"pendingDeliveries"`    // Number of deliveries which are pending or being retried
+	SuccessRate          float64 `json:"successRate"`          // Percentage of completed deliveries which succeeded
+}
+
+func webhooksHealthToInterfaceSlice(webhooksHealth []webhookHealth) []interface{} {
+	interfaceSlice := make([]interface{}, len(webhooksHealth))
+	for i, r := range webhooksHealth {
+		interfaceSlice[i] = r
+	}
+	return interfaceSlice
+}
+
+type listWebhooksHealthResp struct {
+	Webhooks   interface{}         `json:"webhooks"`
+	Pagination paginate.Pagination `json:"pagination"`
+}
+
+// computeWebhookHealth Computes the health summary of a webhook from its delivery history
+func computeWebhookHealth(webhook wh.Webhook) (webhookHealth, error) {
+	health := webhookHealth{
+		Id:                   webhook.ID,
+		PayloadURL:           webhook.PayloadURL,
+		Status:               webhookHealthHealthy,
+		Disabled:             webhook.Disabled,
+		DisabledReason:       webhook.DisabledReason,
+		ConsecutiveFailures:  webhook.ConsecutiveFailures,
+		LastSuccessTimeStamp: webhook.LastSuccessTimeStamp,
+		LastFailureTimeStamp: webhook.LastFailureTimeStamp,
+	}
+
+	statusCounts, err := wh.GetDeliveryStatusCountsByWebhookId(webhook.ID)
+	if err != nil {
+		return health, err
+	}
+
+	for _, statusCount := range statusCounts {
+		health.TotalDeliveries += statusCount.Count
+
+		switch statusCount.Status {
+		case wh.DeliveryStatus[wh.DeliveryStatusSucceeded]:
+			health.SucceededDeliveries += statusCount.Count
+		case wh.DeliveryStatus[wh.DeliveryStatusPending], wh.DeliveryStatus[wh.DeliveryStatusRetrying]:
+			health.PendingDeliveries += statusCount.Count
+		case wh.DeliveryStatus[wh.DeliveryStatusCompleted]:
+			// Deliveries recorded before retries were introduced are
+			// completed regardless of the response status code
+			if statusCount.IsSuccess {
+				health.SucceededDeliveries += statusCount.Count
+			} else {
+				health.FailedDeliveries += statusCount.Count
+			}
+		default:
+			health.FailedDeliveries += statusCount.Count
+		}
+	}
+
+	completedDeliveries := health.SucceededDeliveries + health.FailedDeliveries
+	if completedDeliveries > 0 {
+		health.SuccessRate = float64(health.SucceededDeliveries) * 100 / float64(completedDeliveries)
+	}
+
+	if webhook.Disabled {
+		health.Status = webhookHealthDisabled
+	} else if webhook.ConsecutiveFailures > 0 {
+		health.Status = webhookHealthDegraded
+	}
+
+	return health, nil
+}
+
+// ConfigListWebhooksHealth Lists the health summary of webhooks for an organisation
+func ConfigListWebhooksHealth(w http.ResponseWriter, r *http.Request) {
+	// Headers
+	organisationId := r.Header.Get(config.OrganizationId)
+	organisationId = common.Sanitize(organisationId)
+
+	// Repository
+	webhookRepo := wh.WebhookRepository{}
+	webhookRepo.Init(organisationId)
+
+	// Fetching all the webhooks for an organisation
+	webhooks, err := webhookRepo.GetAllWebhooksByOrgID()
+	if err != nil {
+		m := fmt.Sprintf("Failed to fetch webhooks for organization: %v", organisationId)
+		common.HandleErrorV2(w, http.StatusInternalServerError, m, err)
+		return
+	}
+
+	webhooksHealth := make([]webhookHealth, 0)
+	for _, webhook := range webhooks {
+		health, err := computeWebhookHealth(webhook)
+		if err != nil {
+			m := fmt.Sprintf("Failed to compute health for webhook:%v for organisation: %v", webhook.ID, organisationId)
+			common.HandleErrorV2(w, http.StatusInternalServerError, m, err)
+			return
+		}
+		webhooksHealth = append(webhooksHealth, health)
+	}
+
+	// Query params
+	offset, limit := paginate.ParsePaginationQueryParams(r)
+	log.Printf("Offset: %v and limit: %v\n", offset, limit)
+
+	query := paginate.PaginateObjectsQuery{
+		Limit:  limit,
+		Offset: offset,
+	}
+
+	interfaceSlice := webhooksHealthToInterfaceSlice(webhooksHealth)
+	result := paginate.PaginateObjects(query, interfaceSlice)
+	var resp = listWebhooksHealthResp{
+		Webhooks:   result.Items,
+		Pagination: result.Pagination,
+	}
+
+	response, _ := json.Marshal(resp)
+	w.Header().Set(config.ContentTypeHeader, config.ContentTypeJSON)
+	w.WriteHeader(http.StatusOK)
+	w.Write(response)
+}
@@ -1,6 +1,7 @@
 package webhook
 
 import (
+	"crypto/tls"
 	"encoding/json"
 	"fmt"
 	"log"
@@ -14,12 +15,66 @@ import (
 
 // PingWebhookResp Defines the response structure for webhook status check using ping
 type PingWebhookResp struct {
-	ResponseStatusCode      int    `json:"responseStatusCode"`      // HTTP response status code
-	ResponseStatusStr       string `json:"responseStatusStr"`       // HTTP response status string
-	ExecutionStartTimeStamp string `json:"executionStartTimestamp"` // UTC timestamp when webhook execution started
-	ExecutionEndTimeStamp   string `json:"executionEndTimestamp"`   // UTC timestamp when webhook execution ended
-	Status                  string `json:"status"`                  // Status of webhook delivery for e.g. failed or completed
-	StatusDescription       string `json:"statusDescription"`       // Describe the status for e.g. Reason for failure
+	ResponseStatusCode      int                    `json:"responseStatusCode"`      // HTTP response status code
+	ResponseStatusStr       string                 `json:"responseStatusStr"`       // HTTP response status string
+	ExecutionStartTimeStamp string                 `json:"executionStartTimestamp"` // UTC timestamp when webhook execution started
+	ExecutionEndTimeStamp   string                 `json:"executionEndTimestamp"`   // UTC timestamp when webhook execution ended
+	Status                  string                 `json:"status"`                  // Status of webhook delivery for e.g. failed or completed
+	StatusDescription       string                 `json:"statusDescription"`       // Describe the status for e.g. Reason for failure
+	TLS                     *PingWebhookTLSDetails `json:"tls,omitempty"`           // TLS handshake details, if the webhook endpoint uses https
+}
+
+// PingWebhookCertificate Defines the certificate details presented by the webhook endpoint
+type PingWebhookCertificate struct {
+	Subject   string   `json:"subject"`
+	Issuer    string   `json:"issuer"`
+	DNSNames  []string `json:"dnsNames"`
+	NotBefore string   `json:"notBefore"`
+	NotAfter  string   `json:"notAfter"`
+}
+
+// PingWebhookTLSDetails Defines the TLS handshake details for webhook status check using ping
+type PingWebhookTLSDetails struct {
+	Version               string                   `json:"version"`               // TLS version for e.g. TLS 1.3
+	CipherSuite           string                   `json:"cipherSuite"`           // Negotiated cipher suite
+	ServerName            string                   `json:"serverName"`            // Server name sent for SNI
+	NegotiatedProtocol    string                   `json:"negotiatedProtocol"`    // Application protocol negotiated using ALPN
+	ClientCertificateSent bool                     `json:"clientCertificateSent"` // Client certificate is configured for mutual TLS
+	PeerCertificates      []PingWebhookCertificate `json:"peerCertificates"`      // Certificate chain presented by the webhook endpoint
+}
+
+// tlsVersions Readable names of TLS versions
+var tlsVersions = map[uint16]string{
+	tls.VersionTLS10: "TLS 1.0",
+	tls.VersionTLS11: "TLS 1.1",
+	tls.VersionTLS12: "TLS 1.2",
+	tls.VersionTLS13: "TLS 1.3",
+}
+
+// getPingWebhookTLSDetails Constructs TLS handshake details from the connection state
+func getPingWebhookTLSDetails(state *tls.ConnectionState, webhook wh.Webhook) *PingWebhookTLSDetails {
+	if state == nil {
+		return nil
+	}
+
+	tlsDetails := PingWebhookTLSDetails{
+		Version:               tlsVersions[state.Version],
+		CipherSuite:           tls.CipherSuiteName(state.CipherSuite),
+		ServerName:            state.ServerName,
+		NegotiatedProtocol:    state.NegotiatedProtocol,
+		ClientCertificateSent: len(webhook.ClientCertificate) > 0 && len(webhook.ClientKey) > 0,
+		PeerCertificates:      []PingWebhookCertificate{},
+	}
+	for _, certificate := range state.PeerCertificates {
+		tlsDetails.PeerCertificates = append(tlsDetails.PeerCertificates, PingWebhookCertificate{
+			Subject:   certificate.Subject.String(),
+			Issuer:    certificate.Issuer.String(),
+			DNSNames:  certificate.DNSNames,
+			NotBefore: certificate.NotBefore.UTC().Format("2006-01-02T15:04:05Z"),
+			NotAfter:  certificate.NotAfter.UTC().Format("2006-01-02T15:04:05Z"),
+		})
+	}
+	return &tlsDetails
 }
 
 func ConfigPingWebhook(w http.ResponseWriter, r *http.Request) {
@@ -67,13 +122,20 @@ func ConfigPingWebhook(w http.ResponseWriter, r *http.Request) {
 
 	defer resp.Body.Close()
 
+	// Ping is successful only if the webhook endpoint responded with 2XX
+	status := wh.DeliveryStatus[wh.DeliveryStatusSucceeded]
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		status = wh.DeliveryStatus[wh.DeliveryStatusFailed]
+	}
+
 	// Constructing webhook ping response
 	pingWebhookResp := PingWebhookResp{
 		ResponseStatusCode:      resp.StatusCode,
 		ResponseStatusStr:       resp.Status,
 		ExecutionStartTimeStamp: executionStartTimeStamp,
 		ExecutionEndTimeStamp:   executionEndTimeStamp,
-		Status:                  wh.DeliveryStatus[wh.DeliveryStatusCompleted],
+		Status:                  status,
+		TLS:                     getPingWebhookTLSDetails(resp.TLS, webhook),
 	}
 
 	response, _ := json.Marshal(pingWebhookResp)
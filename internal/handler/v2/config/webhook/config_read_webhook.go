@@ -7,6 +7,8 @@ import (
 
 	"github.com/bb-consent/api/internal/common"
 	"github.com/bb-consent/api/internal/config"
+	"github.com/bb-consent/api/internal/rbac"
+	"github.com/bb-consent/api/internal/token"
 	wh "github.com/bb-consent/api/internal/webhook"
 	"github.com/gorilla/mux"
 )
@@ -15,6 +17,14 @@ type readWebhookResp struct {
 	Webhook wh.Webhook `json:"webhook"`
 }
 
+// canReadWebhookSecrets Checks if the caller manages webhooks and is
+// allowed to read their signing secrets, read only roles can't forge
+// webhook signatures this way
+func canReadWebhookSecrets(r *http.Request) bool {
+	role := token.GetUserRole(r)
+	return role == rbac.ROLE_ADMIN || role == rbac.ROLE_DEVELOPER
+}
+
 // ConfigReadWebhook
 func ConfigReadWebhook(w http.ResponseWriter, r *http.Request) {
 	// Headers
@@ -35,6 +45,12 @@ func ConfigReadWebhook(w http.ResponseWriter, r *http.Request) {
 		common.HandleError(w, http.StatusNotFound, m, err)
 		return
 	}
+	// Client key and custom header values aren't returned
+	webhook.ClientKey = ""
+	webhook.Headers = wh.RedactHeaders(webhook.Headers)
+	if !canReadWebhookSecrets(r) {
+		webhook = wh.RedactSecrets(webhook)
+	}
 	resp := readWebhookResp{
 		Webhook: webhook,
 	}
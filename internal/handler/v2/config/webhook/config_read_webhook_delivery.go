@@ -12,13 +12,15 @@ import (
 )
 
 type readWebhookDeliveryResp struct {
-	Id                 string `json:"id" bson:"_id,omitempty"` // Webhook delivery ID
-	WebhookId          string `json:"webhookId"`               // Webhook ID
-	ResponseStatusCode int    `json:"responseStatusCode"`      // HTTP response status code
-	ResponseStatusStr  string `json:"responseStatusStr"`       // HTTP response status string
-	TimeStamp          string `json:"timestamp"`               // UTC timestamp when webhook execution started
-	Status             string `json:"status"`                  // Status of webhook delivery for e.g. failed or completed
-	StatusDescription  string `json:"statusDescription"`       // Describe the status for e.g. Reason for failure
+	Id                   string `json:"id" bson:"_id,omitempty"` // Webhook delivery ID
+	WebhookId            string `json:"webhookId"`               // Webhook ID
+	ResponseStatusCode   int    `json:"responseStatusCode"`      // HTTP response status code
+	ResponseStatusStr    string `json:"responseStatusStr"`       // HTTP response status string
+	TimeStamp            string `json:"timestamp"`               // UTC timestamp when webhook execution started
+	Status               string `json:"status"`                  // Status of webhook delivery for e.g. failed or succeeded
+	StatusDescription    string `json:"statusDescription"`       // Describe the status for e.g. Reason for failure
+	Attempts             int    `json:"attempts"`                // Number of delivery attempts made
+	NextAttemptTimeStamp string `json:"nextAttemptTimestamp"`    // UTC timestamp after which the delivery is attempted again
 }
 
 // GetRecentWebhookDeliveryById Gets the payload delivery details for a webhook by ID
@@ -54,13 +56,15 @@ func ConfigReadRecentWebhookDelivery(w http.ResponseWriter, r *http.Request) {
 	}
 
 	resp := readWebhookDeliveryResp{
-		Id:                 webhookDelivery.ID,
-		WebhookId:          webhookDelivery.WebhookID,
-		ResponseStatusCode: webhookDelivery.ResponseStatusCode,
-		ResponseStatusStr:  webhookDelivery.ResponseStatusStr,
-		TimeStamp:          webhookDelivery.ExecutionStartTimeStamp,
-		Status:             webhookDelivery.Status,
-		StatusDescription:  webhookDelivery.StatusDescription,
+		Id:                   webhookDelivery.ID,
+		WebhookId:            webhookDelivery.WebhookID,
+		ResponseStatusCode:   webhookDelivery.ResponseStatusCode,
+		ResponseStatusStr:    webhookDelivery.ResponseStatusStr,
+		TimeStamp:            webhookDelivery.ExecutionStartTimeStamp,
+		Status:               webhookDelivery.Status,
+		StatusDescription:    webhookDelivery.StatusDescription,
+		Attempts:             webhookDelivery.Attempts,
+		NextAttemptTimeStamp: webhookDelivery.NextAttemptTimeStamp,
 	}
 
 	response, _ := json.Marshal(resp)
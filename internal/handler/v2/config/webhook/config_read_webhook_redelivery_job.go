@@ -0,0 +1,54 @@
+package webhook
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/bb-consent/api/internal/common"
+	"github.com/bb-consent/api/internal/config"
+	wh "github.com/bb-consent/api/internal/webhook"
+	"github.com/gorilla/mux"
+)
+
+type readWebhookRedeliveryJobResp struct {
+	RedeliveryJob wh.WebhookRedeliveryJob `json:"redeliveryJob"`
+}
+
+// ConfigReadWebhookRedeliveryJob Reads the progress of a redelivery job
+func ConfigReadWebhookRedeliveryJob(w http.ResponseWriter, r *http.Request) {
+	// Headers
+	organisationId := r.Header.Get(config.OrganizationId)
+	organisationId = common.Sanitize(organisationId)
+
+	webhookId := mux.Vars(r)[config.WebhookId]
+	webhookId = common.Sanitize(webhookId)
+
+	redeliveryJobId := mux.Vars(r)[config.RedeliveryJobId]
+	redeliveryJobId = common.Sanitize(redeliveryJobId)
+
+	// Repository
+	jobRepo := wh.WebhookRedeliveryJobRepository{}
+	jobRepo.Init(organisationId)
+
+	if err := jobRepo.FailInterrupted(); err != nil {
+		log.Printf("Failed to mark interrupted redelivery jobs as failed for organisation: %v err: %v", organisationId, err)
+	}
+
+	redeliveryJob, err := jobRepo.Get(webhookId, redeliveryJobId)
+	if err != nil {
+		m := fmt.Sprintf("Failed to get redelivery job:%v for webhook:%v for organisation: %v", redeliveryJobId, webhookId, organisationId)
+		common.HandleErrorV2(w, http.StatusNotFound, m, err)
+		return
+	}
+
+	resp := readWebhookRedeliveryJobResp{
+		RedeliveryJob: redeliveryJob,
+	}
+
+	response, _ := json.Marshal(resp)
+	w.Header().Set(config.ContentTypeHeader, config.ContentTypeJSON)
+	w.WriteHeader(http.StatusOK)
+	w.Write(response)
+}
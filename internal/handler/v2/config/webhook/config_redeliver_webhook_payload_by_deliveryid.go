@@ -1,14 +1,12 @@
 package webhook
 
 import (
-	"encoding/json"
 	"fmt"
 	"net/http"
 
 	"github.com/bb-consent/api/internal/actionlog"
 	"github.com/bb-consent/api/internal/common"
 	"github.com/bb-consent/api/internal/config"
-	"github.com/bb-consent/api/internal/individual"
 	wh "github.com/bb-consent/api/internal/webhook"
 	"github.com/bb-consent/api/internal/webhook_dispatcher"
 	"github.com/gorilla/mux"
@@ -46,31 +44,19 @@ func ConfigRedeliverWebhookPayloadByDeliveryID(w http.ResponseWriter, r *http.Re
 		return
 	}
 
-	// Converting the webhook payload to bytes
-	webhookPayloadBytes, err := json.Marshal(webhookDelivery.RequestPayload)
-	if err != nil {
-		m := fmt.Sprintf("Failed to convert webhook event data to bytes, error:%v; Failed to redeliver payload for webhook for event:<%s>, user:<%s>, org:<%s>", err.Error(), webhookDelivery.WebhookEventType, webhookDelivery.UserID, organisationId)
-		common.HandleError(w, http.StatusInternalServerError, m, err)
-		return
-	}
-
-	// Repository
-	individualRepo := individual.IndividualRepository{}
-	individualRepo.Init(organisationId)
-
 	// Get the details of who triggered webhook
-	u, err := individualRepo.Get(webhookDelivery.UserID)
+	userId, userName := wh.GetTriggeredBy(organisationId, webhookDelivery.UserID)
+
+	_, err = webhook_dispatcher.Enqueue(webhookDelivery.RequestPayload, organisationId, webhookDelivery.UserID)
 	if err != nil {
-		m := fmt.Sprintf("Failed to get user, error:%v; Failed to redeliver payload for webhook for event:<%s>, user:<%s>, org:<%s>", err.Error(), webhookDelivery.WebhookEventType, webhookDelivery.UserID, organisationId)
+		m := fmt.Sprintf("Failed to queue webhook delivery, error:%v; Failed to redeliver payload for webhook for event:<%s>, user:<%s>, org:<%s>", err.Error(), webhookDelivery.WebhookEventType, webhookDelivery.UserID, organisationId)
 		common.HandleError(w, http.StatusInternalServerError, m, err)
 		return
 	}
 
-	go webhook_dispatcher.ProcessWebhooks(webhookDelivery.WebhookEventType, webhookPayloadBytes)
-
 	// Log webhook calls in webhooks category
-	aLog := fmt.Sprintf("Organization webhook: %v triggered by user: %v by event: %v", webhook.PayloadURL, u.Email, webhookDelivery.WebhookEventType)
-	actionlog.LogOrgWebhookCalls(u.Id, u.Email, organisationId, aLog)
+	aLog := fmt.Sprintf("Organization webhook: %v triggered by user: %v by event: %v", webhook.PayloadURL, userName, webhookDelivery.WebhookEventType)
+	actionlog.LogOrgWebhookCalls(userId, userName, organisationId, aLog)
 
 	w.Header().Set(config.ContentTypeHeader, config.ContentTypeJSON)
 	w.WriteHeader(http.StatusOK)
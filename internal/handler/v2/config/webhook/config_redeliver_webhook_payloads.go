@@ -0,0 +1,123 @@
+package webhook
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/bb-consent/api/internal/common"
+	"github.com/bb-consent/api/internal/config"
+	"github.com/bb-consent/api/internal/token"
+	wh "github.com/bb-consent/api/internal/webhook"
+	"github.com/gorilla/mux"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type redeliverWebhookPayloadsReq struct {
+	Filter wh.WebhookDeliveryFilter `json:"filter"`
+}
+
+type redeliverWebhookPayloadsResp struct {
+	RedeliveryJob wh.WebhookRedeliveryJob `json:"redeliveryJob"`
+}
+
+func validateRedeliverWebhookPayloadsRequestBody(redeliverReq *redeliverWebhookPayloadsReq) error {
+	// Failed deliveries are redelivered by default
+	if len(redeliverReq.Filter.Status) == 0 {
+		redeliverReq.Filter.Status = wh.DeliveryStatus[wh.DeliveryStatusFailed]
+	}
+
+	err := redeliverReq.Filter.Validate()
+	if err != nil {
+		return err
+	}
+
+	// Deliveries which are yet to complete are attempted by the workers
+	if redeliverReq.Filter.Status == wh.DeliveryStatus[wh.DeliveryStatusPending] || redeliverReq.Filter.Status == wh.DeliveryStatus[wh.DeliveryStatusRetrying] {
+		return fmt.Errorf("deliveries with status %v can't be redelivered", redeliverReq.Filter.Status)
+	}
+
+	if len(redeliverReq.Filter.FromTimeStamp) == 0 || len(redeliverReq.Filter.ToTimeStamp) == 0 {
+		return errors.New("please provide from and to timestamp")
+	}
+
+	return nil
+}
+
+// ConfigRedeliverWebhookPayloads Redo payload delivery to the webhook for all
+// deliveries matching the filter, the redelivery is tracked as a job
+func ConfigRedeliverWebhookPayloads(w http.ResponseWriter, r *http.Request) {
+	// Headers
+	organisationId := r.Header.Get(config.OrganizationId)
+	organisationId = common.Sanitize(organisationId)
+
+	webhookId := mux.Vars(r)[config.WebhookId]
+	webhookId = common.Sanitize(webhookId)
+
+	// Request body
+	var redeliverReq redeliverWebhookPayloadsReq
+	b, _ := io.ReadAll(r.Body)
+	defer r.Body.Close()
+	json.Unmarshal(b, &redeliverReq)
+
+	err := validateRedeliverWebhookPayloadsRequestBody(&redeliverReq)
+	if err != nil {
+		common.HandleErrorV2(w, http.StatusBadRequest, err.Error(), err)
+		return
+	}
+
+	// Repository
+	webhookRepo := wh.WebhookRepository{}
+	webhookRepo.Init(organisationId)
+
+	// Fetching webhook by ID
+	webhook, err := webhookRepo.GetByOrgID(webhookId)
+	if err != nil {
+		m := fmt.Sprintf("Failed to get webhook:%v for organisation: %v", webhookId, organisationId)
+		common.HandleErrorV2(w, http.StatusNotFound, m, err)
+		return
+	}
+
+	if webhook.Disabled {
+		m := fmt.Sprintf("Webhook:%v is disabled", webhookId)
+		common.HandleErrorV2(w, http.StatusBadRequest, m, errors.New(m))
+		return
+	}
+
+	// Repository
+	jobRepo := wh.WebhookRedeliveryJobRepository{}
+	jobRepo.Init(organisationId)
+
+	now := time.Now().UTC().Format("2006-01-02T15:04:05Z")
+	redeliveryJob := wh.WebhookRedeliveryJob{
+		ID:                 primitive.NewObjectID().Hex(),
+		OrganisationId:     organisationId,
+		WebhookId:          webhook.ID,
+		UserId:             token.GetUserID(r),
+		Filter:             redeliverReq.Filter,
+		Status:             wh.RedeliveryJobStatusPending,
+		TimeStamp:          now,
+		HeartbeatTimeStamp: now,
+	}
+
+	redeliveryJob, err = jobRepo.Add(redeliveryJob)
+	if err != nil {
+		m := fmt.Sprintf("Failed to create redelivery job for webhook:%v for organisation: %v", webhookId, organisationId)
+		common.HandleErrorV2(w, http.StatusInternalServerError, m, err)
+		return
+	}
+
+	go wh.RunWebhookRedeliveryJob(redeliveryJob, webhook)
+
+	resp := redeliverWebhookPayloadsResp{
+		RedeliveryJob: redeliveryJob,
+	}
+
+	response, _ := json.Marshal(resp)
+	w.Header().Set(config.ContentTypeHeader, config.ContentTypeJSON)
+	w.WriteHeader(http.StatusOK)
+	w.Write(response)
+}
@@ -0,0 +1,125 @@
+package webhook
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/bb-consent/api/internal/common"
+	"github.com/bb-consent/api/internal/config"
+	wh "github.com/bb-consent/api/internal/webhook"
+	"github.com/gorilla/mux"
+)
+
+// Hours for which the previous secret is used for signing after rotation
+const defaultSecretExpiryInHours = 24
+
+// Maximum hours for which the previous secret can be used for signing after rotation
+const maxSecretExpiryInHours = 24 * 30
+
+type rotateWebhookSecretReq struct {
+	ExpiryInHours *int `json:"expiryInHours"`
+}
+
+type rotateWebhookSecretResp struct {
+	Webhook wh.Webhook `json:"webhook"`
+}
+
+// ConfigRotateWebhookSecret Generates a new secret for the webhook, the
+// current secret is used for signing along with the new secret until it expires
+func ConfigRotateWebhookSecret(w http.ResponseWriter, r *http.Request) {
+	// Headers
+	organisationId := r.Header.Get(config.OrganizationId)
+	organisationId = common.Sanitize(organisationId)
+
+	webhookId := mux.Vars(r)[config.WebhookId]
+	webhookId = common.Sanitize(webhookId)
+
+	// Request body is optional
+	var rotateReq rotateWebhookSecretReq
+	b, _ := io.ReadAll(r.Body)
+	defer r.Body.Close()
+	if len(b) > 0 {
+		err := json.Unmarshal(b, &rotateReq)
+		if err != nil {
+			m := "Failed to parse request body"
+			common.HandleErrorV2(w, http.StatusBadRequest, m, err)
+			return
+		}
+	}
+
+	expiryInHours := defaultSecretExpiryInHours
+	if rotateReq.ExpiryInHours != nil {
+		expiryInHours = *rotateReq.ExpiryInHours
+	}
+	if expiryInHours < 0 || expiryInHours > maxSecretExpiryInHours {
+		m := fmt.Sprintf("Expiry in hours should be between 0 and %v", maxSecretExpiryInHours)
+		common.HandleErrorV2(w, http.StatusBadRequest, m, errors.New(m))
+		return
+	}
+
+	// Repository
+	webhookRepo := wh.WebhookRepository{}
+	webhookRepo.Init(organisationId)
+
+	// Fetching webhook by ID
+	toBeUpdatedWebhook, err := webhookRepo.GetByOrgID(webhookId)
+	if err != nil {
+		m := fmt.Sprintf("Failed to get webhook:%v for organisation: %v", webhookId, organisationId)
+		common.HandleErrorV2(w, http.StatusNotFound, m, err)
+		return
+	}
+
+	newSecret, err := wh.GenerateWebhookSecret()
+	if err != nil {
+		m := fmt.Sprintf("Failed to generate secret for webhook:%v", webhookId)
+		common.HandleErrorV2(w, http.StatusInternalServerError, m, err)
+		return
+	}
+
+	now := time.Now().UTC()
+	currentTimestamp := now.Format("2006-01-02T15:04:05Z")
+
+	// Dropping the previous secrets which have expired
+	previousSecrets := []wh.WebhookSecret{}
+	for _, previousSecret := range toBeUpdatedWebhook.PreviousSecrets {
+		if previousSecret.ExpiryTimestamp > currentTimestamp {
+			previousSecrets = append(previousSecrets, previousSecret)
+		}
+	}
+
+	// Current secret is used for signing until it expires
+	if len(toBeUpdatedWebhook.SecretKey) > 0 && expiryInHours > 0 {
+		previousSecrets = append(previousSecrets, wh.WebhookSecret{
+			Secret:          toBeUpdatedWebhook.SecretKey,
+			Timestamp:       currentTimestamp,
+			ExpiryTimestamp: now.Add(time.Duration(expiryInHours) * time.Hour).Format("2006-01-02T15:04:05Z"),
+		})
+	}
+
+	toBeUpdatedWebhook.SecretKey = newSecret
+	toBeUpdatedWebhook.PreviousSecrets = previousSecrets
+
+	// Save to db
+	savedWebhook, err := webhookRepo.UpdateWebhook(toBeUpdatedWebhook)
+	if err != nil {
+		m := fmt.Sprintf("Failed to rotate secret for webhook:%v for organisation: %v", webhookId, organisationId)
+		common.HandleErrorV2(w, http.StatusInternalServerError, m, err)
+		return
+	}
+
+	// Client key and custom header values aren't returned
+	savedWebhook.ClientKey = ""
+	savedWebhook.Headers = wh.RedactHeaders(savedWebhook.Headers)
+	resp := rotateWebhookSecretResp{
+		Webhook: savedWebhook,
+	}
+
+	response, _ := json.Marshal(resp)
+	w.Header().Set(config.ContentTypeHeader, config.ContentTypeJSON)
+	w.WriteHeader(http.StatusOK)
+	w.Write(response)
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/bb-consent/api/internal/config"
 	wh "github.com/bb-consent/api/internal/webhook"
 	"github.com/gorilla/mux"
+	"golang.org/x/exp/slices"
 )
 
 func validateUpdatewebhookRequestBody(webhookReq updateWebhookReq, currentWebhook wh.Webhook, organisationId string, webhookId string, webhhokRepo wh.WebhookRepository) error {
@@ -27,6 +28,42 @@ func validateUpdatewebhookRequestBody(webhookReq updateWebhookReq, currentWebhoo
 		return errors.New("please prefix the endpoint URL with https:// or http://;")
 	}
 
+	// Check if the outbound network policy allows the webhook endpoint
+	err = wh.ValidateWebhookURL(webhookReq.Webhook.PayloadURL)
+	if err != nil {
+		return err
+	}
+
+	err = wh.ValidateSkipSSLVerification(webhookReq.Webhook.SkipSSLVerification)
+	if err != nil {
+		return err
+	}
+
+	// Check if the mutual TLS configuration and custom headers are valid
+	// Client key is optional if the client certificate isn't changed, as the
+	// stored key is used
+	clientKey := webhookReq.Webhook.ClientKey
+	if len(clientKey) == 0 && len(webhookReq.Webhook.ClientCertificate) > 0 && webhookReq.Webhook.ClientCertificate == currentWebhook.ClientCertificate {
+		clientKey, err = wh.DecryptSecret(currentWebhook.ClientKey)
+		if err != nil {
+			return errors.New("failed to decrypt client key, please provide client key")
+		}
+	}
+	err = wh.ValidateClientCertificate(webhookReq.Webhook.ClientCertificate, clientKey)
+	if err != nil {
+		return err
+	}
+
+	err = wh.ValidateCACertificates(webhookReq.Webhook.CACertificates)
+	if err != nil {
+		return err
+	}
+
+	err = wh.ValidateHeaders(webhookReq.Webhook.Headers)
+	if err != nil {
+		return err
+	}
+
 	// Check if webhook with provided payload URL already exists
 	tempWebhook, err := webhhokRepo.GetWebhookByPayloadURL(webhookReq.Webhook.PayloadURL)
 	if err == nil {
@@ -74,18 +111,62 @@ func validateUpdatewebhookRequestBody(webhookReq updateWebhookReq, currentWebhoo
 	if !isValidContentType {
 		return errors.New("please provide a valid content type")
 	}
-	return nil
+
+	// Check if the signature scheme provided is valid
+	if len(webhookReq.Webhook.SignatureScheme) > 0 && !slices.Contains(wh.SignatureSchemes, webhookReq.Webhook.SignatureScheme) {
+		return errors.New("please provide a valid signature scheme")
+	}
+
+	return validateWebhookFilter(webhookReq.Webhook.Filter, organisationId)
 }
 
-func updateWebhookFromUpdateWebhookRequestBody(requestBody updateWebhookReq, toBeUpdatedWebhook wh.Webhook) wh.Webhook {
+func updateWebhookFromUpdateWebhookRequestBody(requestBody updateWebhookReq, toBeUpdatedWebhook wh.Webhook) (wh.Webhook, error) {
 	toBeUpdatedWebhook.PayloadURL = requestBody.Webhook.PayloadURL
 	toBeUpdatedWebhook.ContentType = requestBody.Webhook.ContentType
 	toBeUpdatedWebhook.SubscribedEvents = requestBody.Webhook.SubscribedEvents
+	// Health is reset when a disabled webhook is enabled again, so that it
+	// isn't disabled again on the next failure
+	if toBeUpdatedWebhook.Disabled && !requestBody.Webhook.Disabled {
+		toBeUpdatedWebhook.ConsecutiveFailures = 0
+		toBeUpdatedWebhook.DisabledReason = ""
+	}
 	toBeUpdatedWebhook.Disabled = requestBody.Webhook.Disabled
 	toBeUpdatedWebhook.SecretKey = requestBody.Webhook.SecretKey
 	toBeUpdatedWebhook.SkipSSLVerification = requestBody.Webhook.SkipSSLVerification
+	toBeUpdatedWebhook.SignatureScheme = requestBody.Webhook.SignatureScheme
+	if len(toBeUpdatedWebhook.SignatureScheme) == 0 {
+		toBeUpdatedWebhook.SignatureScheme = wh.SignatureSchemeIGrant
+	}
+	toBeUpdatedWebhook.Filter = wh.WebhookFilter{
+		DataAgreementIds: uniqueSlice(requestBody.Webhook.Filter.DataAgreementIds),
+		LawfulBasis:      uniqueSlice(requestBody.Webhook.Filter.LawfulBasis),
+		MethodOfUse:      uniqueSlice(requestBody.Webhook.Filter.MethodOfUse),
+	}
 
-	return toBeUpdatedWebhook
+	toBeUpdatedWebhook.CACertificates = requestBody.Webhook.CACertificates
+	// Redacted header values returned by the api are replaced with the stored values
+	headers := requestBody.Webhook.Headers
+	for name, value := range headers {
+		if storedValue, ok := toBeUpdatedWebhook.Headers[name]; ok && value == wh.RedactedHeaderValue {
+			headers[name] = storedValue
+		}
+	}
+	toBeUpdatedWebhook.Headers = headers
+	// Stored client key is retained if the client certificate isn't changed
+	if len(requestBody.Webhook.ClientCertificate) == 0 || requestBody.Webhook.ClientCertificate != toBeUpdatedWebhook.ClientCertificate {
+		toBeUpdatedWebhook.ClientKey = ""
+	}
+	toBeUpdatedWebhook.ClientCertificate = requestBody.Webhook.ClientCertificate
+	if len(requestBody.Webhook.ClientKey) > 0 {
+		// Client key is stored encrypted
+		encryptedClientKey, err := wh.EncryptSecret(requestBody.Webhook.ClientKey)
+		if err != nil {
+			return toBeUpdatedWebhook, err
+		}
+		toBeUpdatedWebhook.ClientKey = encryptedClientKey
+	}
+
+	return toBeUpdatedWebhook, nil
 }
 
 type updateWebhookReq struct {
@@ -130,7 +211,12 @@ func ConfigUpdateWebhook(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	toBeUpdatedWebhook = updateWebhookFromUpdateWebhookRequestBody(webhookReq, toBeUpdatedWebhook)
+	toBeUpdatedWebhook, err = updateWebhookFromUpdateWebhookRequestBody(webhookReq, toBeUpdatedWebhook)
+	if err != nil {
+		m := fmt.Sprintf("Failed to update webhook:%v for organisation: %v", webhookId, organisationId)
+		common.HandleError(w, http.StatusInternalServerError, m, err)
+		return
+	}
 
 	// Save to db
 	savedWebhook, err := webhookRepo.UpdateWebhook(toBeUpdatedWebhook)
@@ -140,6 +226,9 @@ func ConfigUpdateWebhook(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Client key and custom header values aren't returned
+	savedWebhook.ClientKey = ""
+	savedWebhook.Headers = wh.RedactHeaders(savedWebhook.Headers)
 	resp := updateWebhookResp{
 		Webhook: savedWebhook,
 	}
@@ -0,0 +1,113 @@
+package onboard
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+
+	"github.com/asaskevich/govalidator"
+	"github.com/bb-consent/api/internal/common"
+	"github.com/bb-consent/api/internal/iam"
+	"github.com/bb-consent/api/internal/invitation"
+	"github.com/bb-consent/api/internal/org"
+	"github.com/bb-consent/api/internal/user"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type activateOrganisationAdminReq struct {
+	Token    string `json:"token" valid:"required"`
+	Password string `json:"password"`
+}
+
+// activateInvitedAdmin Activates the account of an admin who didn't have an
+// account, the password is set for the account
+func activateInvitedAdmin(i invitation.Invitation, password string) (int, string, error) {
+	u, err := user.Get(i.UserId)
+	if err != nil {
+		return http.StatusInternalServerError, "Failed to fetch invited admin", err
+	}
+
+	err = iam.SetPassword(u.IamID, password)
+	if err != nil {
+		return http.StatusBadRequest, "Failed to set password for invited admin", err
+	}
+	return http.StatusNoContent, "", nil
+}
+
+// addInvitedAdmin Adds the admin having an account to the organisation with
+// the role it was invited for
+func addInvitedAdmin(i invitation.Invitation) (int, string, error) {
+	o, err := org.Get(i.OrganisationId)
+	if err != nil {
+		return http.StatusInternalServerError, "Failed to fetch organisation", err
+	}
+	for _, admin := range o.Admins {
+		if admin.UserID == i.UserId {
+			return http.StatusNoContent, "", nil
+		}
+	}
+
+	_, err = org.AddAdminUsers(i.OrganisationId, org.Admin{UserID: i.UserId, RoleID: i.RoleId})
+	if err != nil {
+		return http.StatusInternalServerError, "Failed to add invited admin", err
+	}
+	_, err = user.SetRoleForOrganization(i.UserId, user.Role{RoleID: i.RoleId, OrgID: i.OrganisationId})
+	if err != nil {
+		org.DeleteAdminUsers(i.OrganisationId, org.Admin{UserID: i.UserId, RoleID: i.RoleId})
+		return http.StatusInternalServerError, "Failed to add invited admin", err
+	}
+	return http.StatusNoContent, "", nil
+}
+
+// OnboardActivateOrganisationAdmin Activates the account of an invited
+// organisation admin, or adds an admin having an account to the organisation
+func OnboardActivateOrganisationAdmin(w http.ResponseWriter, r *http.Request) {
+	var activateReq activateOrganisationAdminReq
+	b, _ := io.ReadAll(r.Body)
+	defer r.Body.Close()
+	json.Unmarshal(b, &activateReq)
+
+	// validating request payload
+	valid, err := govalidator.ValidateStruct(activateReq)
+	if !valid {
+		m := "missing mandatory params for activating admin"
+		common.HandleErrorV2(w, http.StatusBadRequest, m, err)
+		return
+	}
+
+	// Invitation is accepted first, so a token used concurrently activates
+	// the account once
+	i, err := invitation.Accept(activateReq.Token)
+	if err != nil {
+		m := "Invalid invitation"
+		if errors.Is(err, mongo.ErrNoDocuments) || errors.Is(err, invitation.ErrInvitationExpired) || errors.Is(err, invitation.ErrInvitationAccepted) {
+			common.HandleErrorV2(w, http.StatusBadRequest, m, err)
+			return
+		}
+		common.HandleErrorV2(w, http.StatusInternalServerError, m, err)
+		return
+	}
+
+	var status int
+	var m string
+	switch {
+	case i.ExistingUser:
+		status, m, err = addInvitedAdmin(i)
+	case len(activateReq.Password) == 0:
+		status, m, err = http.StatusBadRequest, "missing mandatory params for activating admin", errors.New("password is required")
+	default:
+		status, m, err = activateInvitedAdmin(i, activateReq.Password)
+	}
+	if err != nil {
+		// Invitation can be used again since the account isn't activated
+		if reopenErr := invitation.Reopen(i.Id); reopenErr != nil {
+			log.Printf("Failed to reopen invitation: %v: %v", i.Id, reopenErr)
+		}
+		common.HandleErrorV2(w, status, m, err)
+		return
+	}
+
+	w.WriteHeader(status)
+}
@@ -2,13 +2,11 @@ package onboard
 
 import (
 	"encoding/json"
-	"fmt"
 	"log"
 	"net/http"
 
-	"github.com/bb-consent/api/internal/common"
 	"github.com/bb-consent/api/internal/config"
-	"github.com/bb-consent/api/internal/org"
+	"github.com/bb-consent/api/internal/token"
 	"github.com/bb-consent/api/internal/user"
 )
 
@@ -23,20 +21,10 @@ type readOrgAdminResp struct {
 
 // OnboardReadOrganisationAdmin
 func OnboardReadOrganisationAdmin(w http.ResponseWriter, r *http.Request) {
-	// Headers
-	organisationId := r.Header.Get(config.OrganizationId)
-	organisationId = common.Sanitize(organisationId)
-
-	organisation, err := org.Get(organisationId)
-	if err != nil {
-		m := fmt.Sprintf("Failed to get organization by ID :%v", organisationId)
-		common.HandleErrorV2(w, http.StatusNotFound, m, err)
-		return
-	}
-
-	u, err := user.Get(organisation.Admins[0].UserID)
+	// Profile of the caller, every organisation role manages its own
+	u, err := user.Get(token.GetUserID(r))
 	if err != nil {
-		log.Println("failed to find organisation admin")
+		log.Println("failed to find user")
 		panic(err)
 	}
 	resp := readOrgAdminResp{
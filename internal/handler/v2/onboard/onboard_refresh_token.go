@@ -17,10 +17,10 @@ import (
 	"golang.org/x/oauth2"
 )
 
-func refreshTokenForExternalIdpIssuedToken(refreshToken string) (*oauth2.Token, error) {
+func refreshTokenForExternalIdpIssuedToken(refreshToken string, organisationId string) (*oauth2.Token, error) {
 	var token *oauth2.Token
 	// Get organisation
-	organisation, err := org.GetFirstOrganization()
+	organisation, err := org.Get(organisationId)
 	if err != nil {
 		return token, err
 	}
@@ -67,6 +67,9 @@ type refreshTokenResp struct {
 
 // OnboardRefreshToken
 func OnboardRefreshToken(w http.ResponseWriter, r *http.Request) {
+	// Headers
+	organisationId := common.Sanitize(r.Header.Get(config.OrganizationId))
+
 	var tReq tokenReq
 	b, _ := ioutil.ReadAll(r.Body)
 	defer r.Body.Close()
@@ -102,7 +105,7 @@ func OnboardRefreshToken(w http.ResponseWriter, r *http.Request) {
 
 	} else {
 		// Refresh Token For External Idp issued Token
-		t, err := refreshTokenForExternalIdpIssuedToken(tReq.RefreshToken)
+		t, err := refreshTokenForExternalIdpIssuedToken(tReq.RefreshToken, organisationId)
 		if err != nil {
 			log.Printf("Failed to refresh token for external idp")
 			common.HandleErrorV2(w, http.StatusBadRequest, err.Error(), err)
@@ -0,0 +1,64 @@
+package tenant
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/asaskevich/govalidator"
+	"github.com/bb-consent/api/internal/common"
+	"github.com/bb-consent/api/internal/config"
+	"github.com/bb-consent/api/internal/tenant"
+)
+
+type addTenantReq struct {
+	Organisation config.Organization `json:"organisation" valid:"required"`
+	Admin        config.User         `json:"admin" valid:"required"`
+	Policy       config.GlobalPolicy `json:"policy"`
+	// Make the existing user with the admin email the admin, its password isn't changed
+	ReuseExistingAdmin bool `json:"reuseExistingAdmin"`
+}
+
+type addTenantResp struct {
+	Tenant tenant.Tenant `json:"tenant"`
+}
+
+// PlatformCreateTenant Creates an organisation along with its admin
+func PlatformCreateTenant(w http.ResponseWriter, r *http.Request) {
+
+	// Request body
+	var tenantReq addTenantReq
+	b, _ := io.ReadAll(r.Body)
+	defer r.Body.Close()
+	json.Unmarshal(b, &tenantReq)
+
+	// validating request payload
+	valid, err := govalidator.ValidateStruct(tenantReq)
+	if !valid {
+		m := "Missing mandatory params for creating tenant"
+		common.HandleErrorV2(w, http.StatusBadRequest, m, err)
+		return
+	}
+
+	createdTenant, err := tenant.CreateTenant(tenantReq.Organisation, tenantReq.Admin, tenantReq.Policy, tenantReq.ReuseExistingAdmin)
+	if err != nil {
+		if errors.Is(err, tenant.ErrNotMultiTenant) || errors.Is(err, tenant.ErrAdminPasswordRequired) {
+			common.HandleErrorV2(w, http.StatusBadRequest, err.Error(), err)
+			return
+		}
+		if errors.Is(err, tenant.ErrAdminExists) {
+			common.HandleErrorV2(w, http.StatusConflict, err.Error(), err)
+			return
+		}
+		m := fmt.Sprintf("Failed to create tenant: %v", tenantReq.Organisation.Name)
+		common.HandleErrorV2(w, http.StatusInternalServerError, m, err)
+		return
+	}
+
+	resp := addTenantResp{
+		Tenant: createdTenant,
+	}
+	common.ReturnHTTPResponse(resp, w)
+}
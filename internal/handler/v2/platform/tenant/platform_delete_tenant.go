@@ -0,0 +1,38 @@
+package tenant
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/bb-consent/api/internal/common"
+	"github.com/bb-consent/api/internal/config"
+	"github.com/bb-consent/api/internal/tenant"
+	"github.com/gorilla/mux"
+)
+
+type deleteTenantResp struct {
+	Tenant tenant.Tenant `json:"tenant"`
+}
+
+// PlatformDeleteTenant Deletes a disabled organisation in the deployment
+func PlatformDeleteTenant(w http.ResponseWriter, r *http.Request) {
+	// Path params
+	organisationId := common.Sanitize(mux.Vars(r)[config.OrganizationId])
+
+	t, err := tenant.DeleteTenant(organisationId)
+	if err != nil {
+		if errors.Is(err, tenant.ErrTenantNotDisabled) {
+			common.HandleErrorV2(w, http.StatusBadRequest, err.Error(), err)
+			return
+		}
+		m := fmt.Sprintf("Failed to delete tenant: %v", organisationId)
+		common.HandleErrorV2(w, http.StatusInternalServerError, m, err)
+		return
+	}
+
+	resp := deleteTenantResp{
+		Tenant: t,
+	}
+	common.ReturnHTTPResponse(resp, w)
+}
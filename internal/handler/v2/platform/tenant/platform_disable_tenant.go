@@ -0,0 +1,33 @@
+package tenant
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/bb-consent/api/internal/common"
+	"github.com/bb-consent/api/internal/config"
+	"github.com/bb-consent/api/internal/tenant"
+	"github.com/gorilla/mux"
+)
+
+type disableTenantResp struct {
+	Tenant tenant.Tenant `json:"tenant"`
+}
+
+// PlatformDisableTenant Disables an organisation in the deployment
+func PlatformDisableTenant(w http.ResponseWriter, r *http.Request) {
+	// Path params
+	organisationId := common.Sanitize(mux.Vars(r)[config.OrganizationId])
+
+	t, err := tenant.DisableTenant(organisationId)
+	if err != nil {
+		m := fmt.Sprintf("Failed to disable tenant: %v", organisationId)
+		common.HandleErrorV2(w, http.StatusBadRequest, m, err)
+		return
+	}
+
+	resp := disableTenantResp{
+		Tenant: t,
+	}
+	common.ReturnHTTPResponse(resp, w)
+}
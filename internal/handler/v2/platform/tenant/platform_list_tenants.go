@@ -0,0 +1,28 @@
+package tenant
+
+import (
+	"net/http"
+
+	"github.com/bb-consent/api/internal/common"
+	"github.com/bb-consent/api/internal/tenant"
+)
+
+type listTenantsResp struct {
+	Tenants []tenant.Tenant `json:"tenants"`
+}
+
+// PlatformListTenants Lists all organisations in the deployment
+func PlatformListTenants(w http.ResponseWriter, r *http.Request) {
+
+	tenants, err := tenant.ListTenants()
+	if err != nil {
+		m := "Failed to list tenants"
+		common.HandleErrorV2(w, http.StatusInternalServerError, m, err)
+		return
+	}
+
+	resp := listTenantsResp{
+		Tenants: tenants,
+	}
+	common.ReturnHTTPResponse(resp, w)
+}
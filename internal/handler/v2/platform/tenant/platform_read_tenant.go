@@ -0,0 +1,33 @@
+package tenant
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/bb-consent/api/internal/common"
+	"github.com/bb-consent/api/internal/config"
+	"github.com/bb-consent/api/internal/tenant"
+	"github.com/gorilla/mux"
+)
+
+type readTenantResp struct {
+	Tenant tenant.Tenant `json:"tenant"`
+}
+
+// PlatformReadTenant Reads an organisation in the deployment
+func PlatformReadTenant(w http.ResponseWriter, r *http.Request) {
+	// Path params
+	organisationId := common.Sanitize(mux.Vars(r)[config.OrganizationId])
+
+	t, err := tenant.GetTenant(organisationId)
+	if err != nil {
+		m := fmt.Sprintf("Failed to fetch tenant: %v", organisationId)
+		common.HandleErrorV2(w, http.StatusBadRequest, m, err)
+		return
+	}
+
+	resp := readTenantResp{
+		Tenant: t,
+	}
+	common.ReturnHTTPResponse(resp, w)
+}
@@ -0,0 +1,53 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/bb-consent/api/internal/common"
+	"github.com/bb-consent/api/internal/config"
+	"github.com/bb-consent/api/internal/guardian"
+	"github.com/bb-consent/api/internal/individual"
+	"github.com/bb-consent/api/internal/rbac"
+	"github.com/bb-consent/api/internal/token"
+)
+
+// getActingGuardianId Returns the guardian in request header acting on
+// behalf of the individual, empty if the individual is acting by themselves.
+// Individuals logged in can act as a guardian only if they are the guardian,
+// organisations using api key can act as any guardian of the individual.
+func getActingGuardianId(r *http.Request, organisationId string, individualId string) (string, int, error) {
+	guardianId := common.Sanitize(r.Header.Get(config.GuardianHeaderKey))
+	if len(guardianId) == 0 {
+		return "", http.StatusOK, nil
+	}
+	if guardianId == individualId {
+		return "", http.StatusBadRequest, errors.New("guardian should be different from the individual")
+	}
+
+	headerType, _, _ := token.DecodeAuthHeader(r)
+	if headerType == token.AuthorizationToken && token.GetUserRole(r) == rbac.ROLE_USER && token.GetUserID(r) != guardianId {
+		return "", http.StatusForbidden, guardian.ErrNotActingGuardian
+	}
+
+	// Repository
+	individualRepo := individual.IndividualRepository{}
+	individualRepo.Init(organisationId)
+
+	_, err := individualRepo.Get(guardianId)
+	if err != nil {
+		return "", http.StatusBadRequest, fmt.Errorf("failed to fetch guardian: %v", guardianId)
+	}
+
+	// Repository
+	guardianRepo := guardian.GuardianRepository{}
+	guardianRepo.Init(organisationId)
+
+	_, err = guardianRepo.GetActingGuardian(individualId, guardianId)
+	if err != nil {
+		return "", http.StatusForbidden, guardian.ErrNotActingGuardian
+	}
+
+	return guardianId, http.StatusOK, nil
+}
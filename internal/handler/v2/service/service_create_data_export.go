@@ -0,0 +1,129 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bb-consent/api/internal/common"
+	"github.com/bb-consent/api/internal/config"
+	dataexport "github.com/bb-consent/api/internal/data_export"
+	"github.com/bb-consent/api/internal/individual"
+	"github.com/bb-consent/api/internal/token"
+	"github.com/bb-consent/api/internal/webhook"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type createDataExportResp struct {
+	DataExport dataexport.DataExport `json:"dataExport"`
+}
+
+func parseIncludeJsonLdQueryParams(r *http.Request) (bool, error) {
+	includeJsonLdString := r.URL.Query().Get("includeJsonLd")
+	if len(strings.TrimSpace(includeJsonLdString)) >= 1 {
+		return strconv.ParseBool(includeJsonLdString)
+	}
+	return false, nil
+}
+
+// dataExportDownloadUrl Returns the url the archive of the data export can be downloaded from
+func dataExportDownloadUrl(dataExportPath string, export dataexport.DataExport) string {
+	if export.Status != dataexport.StatusCompleted {
+		return ""
+	}
+	return strings.TrimSuffix(dataExportPath, "/") + "/archive"
+}
+
+// ServiceCreateDataExport Exports the data of the individual as a signed
+// archive. Exports of individuals with many consent records are run in
+// background, the progress can be read until the archive is ready.
+func ServiceCreateDataExport(w http.ResponseWriter, r *http.Request) {
+	// Headers
+	organisationId := common.Sanitize(r.Header.Get(config.OrganizationId))
+	individualId := common.Sanitize(r.Header.Get(config.IndividualHeaderKey))
+
+	includeJsonLd, err := parseIncludeJsonLdQueryParams(r)
+	if err != nil {
+		m := "Failed to parse includeJsonLd query param"
+		common.HandleErrorV2(w, http.StatusBadRequest, m, err)
+		return
+	}
+
+	// Repository
+	individualRepo := individual.IndividualRepository{}
+	individualRepo.Init(organisationId)
+
+	exportedIndividual, err := individualRepo.Get(individualId)
+	if err != nil {
+		m := fmt.Sprintf("Failed to fetch individual: %v", individualId)
+		common.HandleErrorV2(w, http.StatusBadRequest, m, err)
+		return
+	}
+
+	// Repository
+	exportRepo := dataexport.DataExportRepository{}
+	exportRepo.Init(organisationId)
+
+	if err := exportRepo.FailInterrupted(); err != nil {
+		log.Printf("Failed to mark interrupted data exports as failed for organisation: %v err: %v", organisationId, err)
+	}
+
+	// Data export which is yet to complete is returned instead of starting another one
+	dataExport, err := exportRepo.GetInProgress(individualId)
+	if err != nil && err != mongo.ErrNoDocuments {
+		m := fmt.Sprintf("Failed to fetch data export for individual: %v", individualId)
+		common.HandleErrorV2(w, http.StatusInternalServerError, m, err)
+		return
+	}
+
+	if err == mongo.ErrNoDocuments {
+		isLarge, err := dataexport.IsLarge(organisationId, individualId)
+		if err != nil {
+			m := fmt.Sprintf("Failed to count consent records for individual: %v", individualId)
+			common.HandleErrorV2(w, http.StatusInternalServerError, m, err)
+			return
+		}
+
+		dataExport = dataexport.DataExport{
+			Id:             primitive.NewObjectID().Hex(),
+			OrganisationId: organisationId,
+			IndividualId:   individualId,
+			UserId:         token.GetUserID(r),
+			IncludeJsonLd:  includeJsonLd,
+			Status:         dataexport.StatusPending,
+			TimeStamp:      time.Now().UTC().Format("2006-01-02T15:04:05Z"),
+		}
+
+		dataExport, err = exportRepo.Add(dataExport)
+		if err != nil {
+			m := fmt.Sprintf("Failed to create data export for individual: %v", individualId)
+			common.HandleErrorV2(w, http.StatusInternalServerError, m, err)
+			return
+		}
+
+		// Trigger webhooks for data download initiated event
+		go webhook.TriggerIndividualWebhookEvent(exportedIndividual, webhook.EventTypes[webhook.EventTypeDataDownloadInitiated])
+
+		if isLarge {
+			go dataexport.RunDataExport(dataExport)
+		} else {
+			dataExport = dataexport.RunDataExport(dataExport)
+		}
+	}
+
+	dataExport.DownloadUrl = dataExportDownloadUrl(r.URL.Path+"/"+dataExport.Id, dataExport)
+
+	resp := createDataExportResp{
+		DataExport: dataExport,
+	}
+
+	response, _ := json.Marshal(resp)
+	w.Header().Set(config.ContentTypeHeader, config.ContentTypeJSON)
+	w.WriteHeader(http.StatusOK)
+	w.Write(response)
+}
@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -10,6 +11,8 @@ import (
 	"github.com/bb-consent/api/internal/config"
 	daRecord "github.com/bb-consent/api/internal/dataagreement_record"
 	daRecordHistory "github.com/bb-consent/api/internal/dataagreement_record_history"
+	"github.com/bb-consent/api/internal/database"
+	"github.com/bb-consent/api/internal/outbox"
 	"github.com/bb-consent/api/internal/revision"
 	"github.com/bb-consent/api/internal/webhook"
 	"github.com/gorilla/mux"
@@ -44,6 +47,13 @@ func ServiceCreateDataAgreementRecord(w http.ResponseWriter, r *http.Request) {
 
 	dataAgreementId := common.Sanitize(mux.Vars(r)[config.DataAgreementId])
 
+	// Guardian acting on behalf of the individual
+	guardianId, statusCode, err := getActingGuardianId(r, organisationId, individualId)
+	if err != nil {
+		common.HandleErrorV2(w, statusCode, err.Error(), err)
+		return
+	}
+
 	// Repository
 	darRepo := daRecord.DataAgreementRecordRepository{}
 	darRepo.Init(organisationId)
@@ -87,6 +97,7 @@ func ServiceCreateDataAgreementRecord(w http.ResponseWriter, r *http.Request) {
 	newDaRecord := createDataAgreementRecord(dataAgreementId, rev, individualId)
 	newDaRecord.OrganisationId = organisationId
 	newDaRecord.IsDeleted = false
+	newDaRecord.AuthorizedByIndividualId = guardianId
 
 	// Create new revision
 	newRevision, err := revision.CreateRevisionForDataAgreementRecord(newDaRecord, individualId)
@@ -95,37 +106,58 @@ func ServiceCreateDataAgreementRecord(w http.ResponseWriter, r *http.Request) {
 		common.HandleErrorV2(w, http.StatusInternalServerError, m, err)
 		return
 	}
-
-	savedDaRecord, err := darRepo.Add(newDaRecord)
+	err = newRevision.AuthorizeByIndividual(guardianId)
 	if err != nil {
-		m := "Failed to create new data agreement record"
+		m := "Failed to create revision for new data agreement record"
 		common.HandleErrorV2(w, http.StatusInternalServerError, m, err)
 		return
 	}
 
-	// Save the revision to db
-	savedRevision, err := revision.Add(newRevision)
-	if err != nil {
-		m := fmt.Sprintf("Failed to create new revision: %v", newRevision.Id)
-		common.HandleErrorV2(w, http.StatusInternalServerError, m, err)
-		return
-	}
+	// Record, revision, history and webhook event are saved in a transaction
+	var savedDaRecord daRecord.DataAgreementRecord
+	var savedRevision revision.Revision
+	var m string
+	err = database.WithTransaction(func(ctx context.Context) error {
+		var err error
+		savedDaRecord, err = darRepo.AddWithContext(ctx, newDaRecord)
+		if err != nil {
+			m = "Failed to create new data agreement record"
+			return err
+		}
 
-	// Trigger webhooks
-	go webhook.TriggerConsentWebhookEvent(savedDaRecord, organisationId, webhook.EventTypes[30])
+		// Save the revision to db
+		savedRevision, err = revision.AddWithContext(ctx, newRevision)
+		if err != nil {
+			m = fmt.Sprintf("Failed to create new revision: %v", newRevision.Id)
+			return err
+		}
 
-	// Add data agreement record history
-	darH := daRecordHistory.DataAgreementRecordsHistory{}
-	darH.DataAgreementId = dataAgreementId
-	darH.OrganisationId = organisationId
-	darH.ConsentRecordId = savedDaRecord.Id
-	darH.IndividualId = individualId
-	err = daRecordHistory.DataAgreementRecordHistoryAdd(darH, savedDaRecord.OptIn)
+		// Webhooks are triggered by the outbox relay
+		_, err = outbox.AddConsentRecordEvent(ctx, savedDaRecord, organisationId, webhook.EventTypes[30])
+		if err != nil {
+			m = "Failed to add webhook event for data agreement record"
+			return err
+		}
+
+		// Add data agreement record history
+		darH := daRecordHistory.DataAgreementRecordsHistory{}
+		darH.DataAgreementId = dataAgreementId
+		darH.OrganisationId = organisationId
+		darH.ConsentRecordId = savedDaRecord.Id
+		darH.IndividualId = individualId
+		darH.AuthorizedByIndividualId = guardianId
+		err = daRecordHistory.DataAgreementRecordHistoryAddWithContext(ctx, darH, savedDaRecord.OptIn)
+		if err != nil {
+			m = "Failed to add data agreement record history"
+			return err
+		}
+		return nil
+	})
 	if err != nil {
-		m := "Failed to add data agreement record history"
 		common.HandleErrorV2(w, http.StatusInternalServerError, m, err)
 		return
 	}
+	outbox.Notify()
 
 	// response
 	resp := createDataAgreementRecordResp{
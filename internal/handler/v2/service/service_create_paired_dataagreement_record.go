@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -12,7 +13,9 @@ import (
 	"github.com/bb-consent/api/internal/dataagreement"
 	daRecord "github.com/bb-consent/api/internal/dataagreement_record"
 	daRecordHistory "github.com/bb-consent/api/internal/dataagreement_record_history"
+	"github.com/bb-consent/api/internal/database"
 	"github.com/bb-consent/api/internal/individual"
+	"github.com/bb-consent/api/internal/outbox"
 	"github.com/bb-consent/api/internal/revision"
 	"github.com/bb-consent/api/internal/signature"
 	"github.com/bb-consent/api/internal/webhook"
@@ -105,6 +108,13 @@ func ServiceCreatePairedDataAgreementRecord(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
+	// Guardian acting on behalf of the individual
+	guardianId, statusCode, err := getActingGuardianId(r, organisationId, individual.Id)
+	if err != nil {
+		common.HandleErrorV2(w, statusCode, err.Error(), err)
+		return
+	}
+
 	// Repository
 	darRepo := daRecord.DataAgreementRecordRepository{}
 	darRepo.Init(organisationId)
@@ -166,6 +176,7 @@ func ServiceCreatePairedDataAgreementRecord(w http.ResponseWriter, r *http.Reque
 	dataAgreementRecord.OrganisationId = organisationId
 	dataAgreementRecord.Id = primitive.NewObjectID().Hex()
 	dataAgreementRecord.SignatureId = toBeCreatedSignature.Id
+	dataAgreementRecord.AuthorizedByIndividualId = guardianId
 
 	newRecordRevision, err := revision.CreateRevisionForDataAgreementRecord(dataAgreementRecord, individualId)
 	if err != nil {
@@ -174,55 +185,71 @@ func ServiceCreatePairedDataAgreementRecord(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
+	// Serialized snapshot is replaced by the signed payload
+	newRecordRevision.AuthorizedByIndividualId = guardianId
 	newRecordRevision.SerializedSnapshot = toBeCreatedSignature.VerificationPayload
 	newRecordRevision.SerializedHash = toBeCreatedSignature.VerificationPayloadHash
 	newRecordRevision.SignedWithoutObjectId = true
 	toBeCreatedSignature.SignedWithoutObjectReference = true
 
-	savedDataAgreementRecord, err := darRepo.Add(dataAgreementRecord)
-	if err != nil {
-		m := fmt.Sprintf("Failed to update paired data agreement record: %v", savedDataAgreementRecord.Id)
-		common.HandleErrorV2(w, http.StatusInternalServerError, m, err)
-		return
-	}
-
-	savedRevision, err := revision.Add(newRecordRevision)
-	if err != nil {
-		m := fmt.Sprintf("Failed to add revision for data agreement record: %v", savedDataAgreementRecord.Id)
-		common.HandleErrorV2(w, http.StatusInternalServerError, m, err)
-		return
-	}
-
-	savedSignature, err := signature.Add(toBeCreatedSignature)
-	if err != nil {
-		m := fmt.Sprintf("Failed to add signature for data agreement record: %v", savedDataAgreementRecord.Id)
-		common.HandleErrorV2(w, http.StatusInternalServerError, m, err)
-		return
-	}
-
-	// Trigger webhooks
-	var eventType string
-	if savedDataAgreementRecord.OptIn {
-		eventType = webhook.EventTypes[30]
-
-	} else {
-		eventType = webhook.EventTypes[31]
-	}
-
-	go webhook.TriggerConsentWebhookEvent(savedDataAgreementRecord, organisationId, eventType)
-
-	// Add data agreement record history
-	darH := daRecordHistory.DataAgreementRecordsHistory{}
-	darH.DataAgreementId = dataAgreementRecord.DataAgreementId
-	darH.OrganisationId = organisationId
-	darH.ConsentRecordId = savedDataAgreementRecord.Id
-	darH.IndividualId = individual.Id
-	err = daRecordHistory.DataAgreementRecordHistoryAdd(darH, savedDataAgreementRecord.OptIn)
+	// Record, revision, signature, history and webhook event are saved in a transaction
+	var savedDataAgreementRecord daRecord.DataAgreementRecord
+	var savedRevision revision.Revision
+	var savedSignature signature.Signature
+	var m string
+	err = database.WithTransaction(func(ctx context.Context) error {
+		var err error
+		savedDataAgreementRecord, err = darRepo.AddWithContext(ctx, dataAgreementRecord)
+		if err != nil {
+			m = fmt.Sprintf("Failed to update paired data agreement record: %v", dataAgreementRecord.Id)
+			return err
+		}
+
+		savedRevision, err = revision.AddWithContext(ctx, newRecordRevision)
+		if err != nil {
+			m = fmt.Sprintf("Failed to add revision for data agreement record: %v", savedDataAgreementRecord.Id)
+			return err
+		}
+
+		savedSignature, err = signature.AddWithContext(ctx, toBeCreatedSignature)
+		if err != nil {
+			m = fmt.Sprintf("Failed to add signature for data agreement record: %v", savedDataAgreementRecord.Id)
+			return err
+		}
+
+		// Webhooks are triggered by the outbox relay
+		var eventType string
+		if savedDataAgreementRecord.OptIn {
+			eventType = webhook.EventTypes[30]
+
+		} else {
+			eventType = webhook.EventTypes[31]
+		}
+		_, err = outbox.AddConsentRecordEvent(ctx, savedDataAgreementRecord, organisationId, eventType)
+		if err != nil {
+			m = fmt.Sprintf("Failed to add webhook event for data agreement record: %v", savedDataAgreementRecord.Id)
+			return err
+		}
+
+		// Add data agreement record history
+		darH := daRecordHistory.DataAgreementRecordsHistory{}
+		darH.DataAgreementId = dataAgreementRecord.DataAgreementId
+		darH.OrganisationId = organisationId
+		darH.ConsentRecordId = savedDataAgreementRecord.Id
+		darH.IndividualId = individual.Id
+		darH.AuthorizedByIndividualId = guardianId
+		err = daRecordHistory.DataAgreementRecordHistoryAddWithContext(ctx, darH, savedDataAgreementRecord.OptIn)
+		if err != nil {
+			m = "Failed to add data agreement record history"
+			return err
+		}
+		return nil
+	})
 	if err != nil {
-		m := "Failed to add data agreement record history"
 		common.HandleErrorV2(w, http.StatusInternalServerError, m, err)
 		return
 	}
+	outbox.Notify()
 	// response
 	resp := createPairedDataAgreementRecordResp{
 		DataAgreementRecord: savedDataAgreementRecord,
@@ -0,0 +1,65 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/bb-consent/api/internal/common"
+	"github.com/bb-consent/api/internal/config"
+	dataexport "github.com/bb-consent/api/internal/data_export"
+	"github.com/gorilla/mux"
+)
+
+// ServiceDownloadDataExport Downloads the archive of a completed data export of the individual
+func ServiceDownloadDataExport(w http.ResponseWriter, r *http.Request) {
+	// Headers
+	organisationId := common.Sanitize(r.Header.Get(config.OrganizationId))
+	individualId := common.Sanitize(r.Header.Get(config.IndividualHeaderKey))
+
+	exportId := common.Sanitize(mux.Vars(r)[config.DataExportId])
+
+	// Repository
+	exportRepo := dataexport.DataExportRepository{}
+	exportRepo.Init(organisationId)
+
+	dataExport, err := exportRepo.Get(individualId, exportId)
+	if err != nil {
+		m := fmt.Sprintf("Failed to fetch data export: %v for individual: %v", exportId, individualId)
+		common.HandleErrorV2(w, http.StatusNotFound, m, err)
+		return
+	}
+
+	if dataExport.Status != dataexport.StatusCompleted {
+		m := fmt.Sprintf("Data export: %v is %v", exportId, dataExport.Status)
+		common.HandleErrorV2(w, http.StatusBadRequest, m, errors.New(m))
+		return
+	}
+
+	if dataExport.IsExpired() {
+		m := fmt.Sprintf("Data export: %v has expired", exportId)
+		common.HandleErrorV2(w, http.StatusGone, m, errors.New(m))
+		return
+	}
+
+	archive, err := dataexport.OpenArchive(exportId)
+	if err != nil {
+		m := fmt.Sprintf("Failed to fetch archive of data export: %v", exportId)
+		common.HandleErrorV2(w, http.StatusInternalServerError, m, err)
+		return
+	}
+	defer archive.Close()
+
+	w.Header().Set(config.ContentTypeHeader, config.ContentTypeZip)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"data-export-%v.zip\"", exportId))
+	w.Header().Set("Content-Length", strconv.FormatInt(dataExport.ArchiveSize, 10))
+	w.WriteHeader(http.StatusOK)
+
+	// Archive is streamed to the client in chunks
+	if _, err := io.Copy(w, archive); err != nil {
+		log.Printf("Failed to stream archive of data export: %v err: %v", exportId, err)
+	}
+}
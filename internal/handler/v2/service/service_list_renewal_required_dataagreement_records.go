@@ -0,0 +1,89 @@
+package service
+
+import (
+	"net/http"
+
+	"github.com/bb-consent/api/internal/common"
+	"github.com/bb-consent/api/internal/config"
+	"github.com/bb-consent/api/internal/dataagreement"
+	daRecord "github.com/bb-consent/api/internal/dataagreement_record"
+	"github.com/bb-consent/api/internal/paginate"
+	"github.com/bb-consent/api/internal/revision"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// renewalRequiredDataAgreementRecord Consent record to be renewed along with
+// the latest revision of data agreement the individual should consent to
+type renewalRequiredDataAgreementRecord struct {
+	daRecord.DataAgreementRecord
+	LatestDataAgreementRevisionId   string `json:"latestDataAgreementRevisionId"`
+	LatestDataAgreementRevisionHash string `json:"latestDataAgreementRevisionHash"`
+	LatestDataAgreementVersion      string `json:"latestDataAgreementVersion"`
+}
+
+type listRenewalRequiredDataAgreementRecordsResp struct {
+	DataAgreementRecords interface{}         `json:"consentRecords"`
+	Pagination           paginate.Pagination `json:"pagination"`
+}
+
+// ServiceListRenewalRequiredDataAgreementRecords Lists the consent records of
+// the individual which should be renewed for a new version of data agreement
+func ServiceListRenewalRequiredDataAgreementRecords(w http.ResponseWriter, r *http.Request) {
+
+	// Headers
+	organisationId := common.Sanitize(r.Header.Get(config.OrganizationId))
+	individualId := common.Sanitize(r.Header.Get(config.IndividualHeaderKey))
+
+	// Query params
+	offset, limit := paginate.ParsePaginationQueryParams(r)
+
+	pipeline, err := daRecord.CreatePipelineForFilteringDataAgreementRecordsByIndividualId(organisationId, individualId)
+	if err != nil {
+		m := "Failed to create pipeline"
+		common.HandleErrorV2(w, http.StatusInternalServerError, m, err)
+		return
+	}
+	pipeline = append(pipeline, bson.M{"$match": bson.M{"state": config.RenewalRequired}})
+	pipeline = append(pipeline, bson.M{"$sort": bson.M{"timestamp": -1}})
+
+	dataAgreementRecords, err := daRecord.GetAllUsingPipeline(pipeline)
+	if err != nil {
+		m := "Failed to fetch data agreement records"
+		common.HandleErrorV2(w, http.StatusInternalServerError, m, err)
+		return
+	}
+
+	darepo := dataagreement.DataAgreementRepository{}
+	darepo.Init(organisationId)
+
+	// Consent records of deleted data agreements can't be renewed
+	consentRecords := []interface{}{}
+	for _, dataAgreementRecord := range dataAgreementRecords {
+		da, err := darepo.Get(dataAgreementRecord.DataAgreementId)
+		if err != nil {
+			continue
+		}
+		latestRevision, err := revision.GetLatestByObjectIdAndSchemaName(dataAgreementRecord.DataAgreementId, config.DataAgreement)
+		if err != nil {
+			continue
+		}
+		consentRecords = append(consentRecords, renewalRequiredDataAgreementRecord{
+			DataAgreementRecord:             dataAgreementRecord,
+			LatestDataAgreementRevisionId:   latestRevision.Id,
+			LatestDataAgreementRevisionHash: latestRevision.SerializedHash,
+			LatestDataAgreementVersion:      da.Version,
+		})
+	}
+
+	query := paginate.PaginateObjectsQuery{
+		Limit:  limit,
+		Offset: offset,
+	}
+	result := paginate.PaginateObjects(query, consentRecords)
+
+	resp := listRenewalRequiredDataAgreementRecordsResp{
+		DataAgreementRecords: result.Items,
+		Pagination:           result.Pagination,
+	}
+	common.ReturnHTTPResponse(resp, w)
+}
@@ -0,0 +1,54 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/bb-consent/api/internal/common"
+	"github.com/bb-consent/api/internal/config"
+	dataexport "github.com/bb-consent/api/internal/data_export"
+	"github.com/gorilla/mux"
+)
+
+type readDataExportResp struct {
+	DataExport dataexport.DataExport `json:"dataExport"`
+}
+
+// ServiceReadDataExport Reads the progress of a data export of the individual
+func ServiceReadDataExport(w http.ResponseWriter, r *http.Request) {
+	// Headers
+	organisationId := common.Sanitize(r.Header.Get(config.OrganizationId))
+	individualId := common.Sanitize(r.Header.Get(config.IndividualHeaderKey))
+
+	exportId := common.Sanitize(mux.Vars(r)[config.DataExportId])
+
+	// Repository
+	exportRepo := dataexport.DataExportRepository{}
+	exportRepo.Init(organisationId)
+
+	if err := exportRepo.FailInterrupted(); err != nil {
+		log.Printf("Failed to mark interrupted data exports as failed for organisation: %v err: %v", organisationId, err)
+	}
+
+	dataExport, err := exportRepo.Get(individualId, exportId)
+	if err != nil {
+		m := fmt.Sprintf("Failed to fetch data export: %v for individual: %v", exportId, individualId)
+		common.HandleErrorV2(w, http.StatusNotFound, m, err)
+		return
+	}
+
+	if !dataExport.IsExpired() {
+		dataExport.DownloadUrl = dataExportDownloadUrl(r.URL.Path, dataExport)
+	}
+
+	resp := readDataExportResp{
+		DataExport: dataExport,
+	}
+
+	response, _ := json.Marshal(resp)
+	w.Header().Set(config.ContentTypeHeader, config.ContentTypeJSON)
+	w.WriteHeader(http.StatusOK)
+	w.Write(response)
+}
@@ -0,0 +1,28 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/bb-consent/api/internal/common"
+	"github.com/bb-consent/api/internal/config"
+	"github.com/bb-consent/api/internal/signingkey"
+)
+
+// ServiceReadOrganisationJwks Publishes the public keys erasure receipts and
+// data export manifests of the organisation are signed with
+func ServiceReadOrganisationJwks(w http.ResponseWriter, r *http.Request) {
+	organisationId := common.Sanitize(r.Header.Get(config.OrganizationId))
+
+	jwks, err := signingkey.GetJWKS(organisationId)
+	if err != nil {
+		m := fmt.Sprintf("Failed to fetch signing keys for organisation: %v", organisationId)
+		common.HandleErrorV2(w, http.StatusInternalServerError, m, err)
+		return
+	}
+
+	w.Header().Set(config.ContentTypeHeader, config.ContentTypeJSON)
+	response, _ := json.Marshal(jwks)
+	w.Write(response)
+}
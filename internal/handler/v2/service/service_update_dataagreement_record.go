@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -11,7 +12,9 @@ import (
 	"github.com/bb-consent/api/internal/dataagreement"
 	daRecord "github.com/bb-consent/api/internal/dataagreement_record"
 	daRecordHistory "github.com/bb-consent/api/internal/dataagreement_record_history"
+	"github.com/bb-consent/api/internal/database"
 	"github.com/bb-consent/api/internal/individual"
+	"github.com/bb-consent/api/internal/outbox"
 	"github.com/bb-consent/api/internal/revision"
 	"github.com/bb-consent/api/internal/webhook"
 	"github.com/gorilla/mux"
@@ -51,6 +54,13 @@ func ServiceUpdateDataAgreementRecord(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Guardian acting on behalf of the individual
+	guardianId, statusCode, err := getActingGuardianId(r, organisationId, individualId)
+	if err != nil {
+		common.HandleErrorV2(w, statusCode, err.Error(), err)
+		return
+	}
+
 	// Request body
 	var dataAgreementRecordReq updateDataAgreementRecordReq
 	var updateConsentReq updateconsentRecordReq
@@ -100,7 +110,8 @@ func ServiceUpdateDataAgreementRecord(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if toBeUpdatedDaRecord.OptIn == optIn {
+	// Consent record to be renewed is updated even if the opt-in is unchanged
+	if toBeUpdatedDaRecord.OptIn == optIn && toBeUpdatedDaRecord.State != config.RenewalRequired {
 		// response
 		resp := updateDataAgreementRecordResp{
 			DataAgreementRecord: toBeUpdatedDaRecord,
@@ -111,6 +122,7 @@ func ServiceUpdateDataAgreementRecord(w http.ResponseWriter, r *http.Request) {
 	}
 	toBeUpdatedDaRecord.OptIn = optIn
 	toBeUpdatedDaRecord.State = config.Unsigned
+	toBeUpdatedDaRecord.AuthorizedByIndividualId = guardianId
 
 	currentDataAgreementRevision, err := revision.GetLatestByObjectIdAndSchemaName(toBeUpdatedDaRecord.DataAgreementId, config.DataAgreement)
 	if err != nil {
@@ -119,6 +131,10 @@ func ServiceUpdateDataAgreementRecord(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Consent record is updated for the latest version of data agreement
+	toBeUpdatedDaRecord.DataAgreementRevisionId = currentDataAgreementRevision.Id
+	toBeUpdatedDaRecord.DataAgreementRevisionHash = currentDataAgreementRevision.SerializedHash
+
 	// Create new revision
 	newRevision, err := revision.UpdateRevisionForDataAgreementRecord(toBeUpdatedDaRecord, individualId, currentDataAgreementRevision)
 	if err != nil {
@@ -126,44 +142,65 @@ func ServiceUpdateDataAgreementRecord(w http.ResponseWriter, r *http.Request) {
 		common.HandleErrorV2(w, http.StatusInternalServerError, m, err)
 		return
 	}
-
-	savedDaRecord, err := darRepo.Update(toBeUpdatedDaRecord)
+	err = newRevision.AuthorizeByIndividual(guardianId)
 	if err != nil {
-		m := "Failed to update new data agreement record"
+		m := "Failed to create revision for new data agreement record"
 		common.HandleErrorV2(w, http.StatusInternalServerError, m, err)
 		return
 	}
 
-	// Save the revision to db
-	savedRevision, err := revision.Add(newRevision)
-	if err != nil {
-		m := fmt.Sprintf("Failed to create new revision: %v", newRevision.Id)
-		common.HandleErrorV2(w, http.StatusInternalServerError, m, err)
-		return
-	}
+	// Record, revision, history and webhook event are saved in a transaction
+	var savedDaRecord daRecord.DataAgreementRecord
+	var savedRevision revision.Revision
+	var m string
+	err = database.WithTransaction(func(ctx context.Context) error {
+		var err error
+		savedDaRecord, err = darRepo.UpdateWithContext(ctx, toBeUpdatedDaRecord)
+		if err != nil {
+			m = "Failed to update new data agreement record"
+			return err
+		}
 
-	// Trigger webhooks
-	var eventType string
-	if savedDaRecord.OptIn {
-		eventType = webhook.EventTypes[30]
+		// Save the revision to db
+		savedRevision, err = revision.AddWithContext(ctx, newRevision)
+		if err != nil {
+			m = fmt.Sprintf("Failed to create new revision: %v", newRevision.Id)
+			return err
+		}
 
-	} else {
-		eventType = webhook.EventTypes[31]
-	}
+		// Webhooks are triggered by the outbox relay
+		var eventType string
+		if savedDaRecord.OptIn {
+			eventType = webhook.EventTypes[30]
 
-	go webhook.TriggerConsentWebhookEvent(savedDaRecord, organisationId, eventType)
-	// Add data agreement record history
-	darH := daRecordHistory.DataAgreementRecordsHistory{}
-	darH.DataAgreementId = savedDaRecord.DataAgreementId
-	darH.OrganisationId = organisationId
-	darH.ConsentRecordId = savedDaRecord.Id
-	darH.IndividualId = individualId
-	err = daRecordHistory.DataAgreementRecordHistoryAdd(darH, savedDaRecord.OptIn)
+		} else {
+			eventType = webhook.EventTypes[31]
+		}
+		_, err = outbox.AddConsentRecordEvent(ctx, savedDaRecord, organisationId, eventType)
+		if err != nil {
+			m = "Failed to add webhook event for data agreement record"
+			return err
+		}
+
+		// Add data agreement record history
+		darH := daRecordHistory.DataAgreementRecordsHistory{}
+		darH.DataAgreementId = savedDaRecord.DataAgreementId
+		darH.OrganisationId = organisationId
+		darH.ConsentRecordId = savedDaRecord.Id
+		darH.IndividualId = individualId
+		darH.AuthorizedByIndividualId = guardianId
+		err = daRecordHistory.DataAgreementRecordHistoryAddWithContext(ctx, darH, savedDaRecord.OptIn)
+		if err != nil {
+			m = "Failed to add data agreement record history"
+			return err
+		}
+		return nil
+	})
 	if err != nil {
-		m := "Failed to add data agreement record history"
 		common.HandleErrorV2(w, http.StatusInternalServerError, m, err)
 		return
 	}
+	outbox.Notify()
 
 	// response
 	resp := updateDataAgreementRecordResp{
@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -11,8 +12,11 @@ import (
 	"github.com/bb-consent/api/internal/common"
 	"github.com/bb-consent/api/internal/config"
 	daRecord "github.com/bb-consent/api/internal/dataagreement_record"
+	"github.com/bb-consent/api/internal/database"
+	"github.com/bb-consent/api/internal/outbox"
 	"github.com/bb-consent/api/internal/revision"
 	"github.com/bb-consent/api/internal/signature"
+	"github.com/bb-consent/api/internal/webhook"
 	"github.com/gorilla/mux"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
@@ -69,6 +73,13 @@ func ServiceUpdateSignatureObject(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Guardian acting on behalf of the individual
+	guardianId, statusCode, err := getActingGuardianId(r, organisationId, individualId)
+	if err != nil {
+		common.HandleErrorV2(w, statusCode, err.Error(), err)
+		return
+	}
+
 	// Repository
 	darRepo := daRecord.DataAgreementRecordRepository{}
 	darRepo.Init(organisationId)
@@ -110,23 +121,9 @@ func ServiceUpdateSignatureObject(w http.ResponseWriter, r *http.Request) {
 	// update signaute for data agreement record
 	toBeUpdatedSignatureObject = createSignatureFromUpdateSignatureRequestBody(toBeUpdatedSignatureObject, signatureReq)
 
-	savedSignature, err := signature.Update(toBeUpdatedSignatureObject)
-	if err != nil {
-		m := "Failed to update signature for data agreement record"
-		common.HandleErrorV2(w, http.StatusInternalServerError, m, err)
-		return
-	}
-
 	// update the data agreement record state
 	toBeUpdatedDaRecord.State = config.Signed
-
-	// Save data agreement to db
-	savedDaRecord, err := darRepo.Update(toBeUpdatedDaRecord)
-	if err != nil {
-		m := "Failed to update data agreement record"
-		common.HandleErrorV2(w, http.StatusInternalServerError, m, err)
-		return
-	}
+	toBeUpdatedDaRecord.AuthorizedByIndividualId = guardianId
 
 	// Create new revision
 	newRevision, err := revision.UpdateRevisionForDataAgreementRecord(toBeUpdatedDaRecord, individualId, currentDataAgreementRevision)
@@ -135,16 +132,56 @@ func ServiceUpdateSignatureObject(w http.ResponseWriter, r *http.Request) {
 		common.HandleErrorV2(w, http.StatusInternalServerError, m, err)
 		return
 	}
-	newRevision.SerializedSnapshot = savedSignature.VerificationPayload
-	newRevision.SerializedHash = savedSignature.VerificationPayloadHash
+	// Serialized snapshot is replaced by the signed payload
+	newRevision.AuthorizedByIndividualId = guardianId
+	newRevision.SerializedSnapshot = toBeUpdatedSignatureObject.VerificationPayload
+	newRevision.SerializedHash = toBeUpdatedSignatureObject.VerificationPayloadHash
+
+	var savedSignature signature.Signature
+	var savedDaRecord daRecord.DataAgreementRecord
+	var savedRevision revision.Revision
+	var m string
+	err = database.WithTransaction(func(ctx context.Context) error {
+		var err error
+		savedSignature, err = signature.UpdateWithContext(ctx, toBeUpdatedSignatureObject)
+		if err != nil {
+			m = "Failed to update signature for data agreement record"
+			return err
+		}
+
+		// Save data agreement to db
+		savedDaRecord, err = darRepo.UpdateWithContext(ctx, toBeUpdatedDaRecord)
+		if err != nil {
+			m = "Failed to update data agreement record"
+			return err
+		}
 
-	// Save the revision to db
-	savedRevision, err := revision.Add(newRevision)
+		// Save the revision to db
+		savedRevision, err = revision.AddWithContext(ctx, newRevision)
+		if err != nil {
+			m = fmt.Sprintf("Failed to create new revision: %v", newRevision.Id)
+			return err
+		}
+
+		// Webhooks are triggered by the outbox relay
+		var eventType string
+		if savedDaRecord.OptIn {
+			eventType = webhook.EventTypes[webhook.EventTypeConsentAllowed]
+		} else {
+			eventType = webhook.EventTypes[webhook.EventTypeConsentDisAllowed]
+		}
+		_, err = outbox.AddConsentRecordEvent(ctx, savedDaRecord, organisationId, eventType)
+		if err != nil {
+			m = "Failed to add webhook event for data agreement record"
+			return err
+		}
+		return nil
+	})
 	if err != nil {
-		m := fmt.Sprintf("Failed to create new revision: %v", newRevision.Id)
 		common.HandleErrorV2(w, http.StatusInternalServerError, m, err)
 		return
 	}
+	outbox.Notify()
 
 	resp := updateSignatureforDataAgreementRecordResp{
 		DataAgreementRecord: savedDaRecord,
@@ -5,5 +5,9 @@ const AuditDataAgreementRecordRead = "/audit/consent-record/{consentRecordId}"
 const AuditListDataAgreements = "/audit/data-agreements"
 const AuditReadDataAgreement = "/audit/data-agreement/{dataAgreementId}"
 
+// Consent record and data agreement events
+const AuditStreamEvents = "/audit/events/stream"
+const AuditStreamEventsWebSocket = "/audit/events/ws"
+
 // organization action logs
 const AuditGetOrgLogs = "/audit/admin/logs"
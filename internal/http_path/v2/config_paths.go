@@ -34,11 +34,16 @@ const ConfigUpdateWebhook = "/config/webhook/{webhookId}"
 const ConfigDeleteWebhook = "/config/webhook/{webhookId}"
 const ConfigListWebhooks = "/config/webhooks"
 const ConfigPingWebhook = "/config/webhook/{webhookId}/ping"
+const ConfigRotateWebhookSecret = "/config/webhook/{webhookId}/secret/rotate"
 const ConfigListRecentWebhookDeliveries = "/config/webhooks/{webhookId}/deliveries"
 const ConfigReadRecentWebhookDelivery = "/config/webhooks/{webhookId}/delivery/{deliveryId}"
 const ConfigRedeliverWebhookPayloadByDeliveryID = "/config/webhooks/{webhookId}/delivery/{deliveryId}/redeliver"
+const ConfigRedeliverWebhookPayloads = "/config/webhooks/{webhookId}/deliveries/redeliver"
+const ConfigListWebhookRedeliveryJobs = "/config/webhooks/{webhookId}/redelivery-jobs"
+const ConfigReadWebhookRedeliveryJob = "/config/webhooks/{webhookId}/redelivery-job/{redeliveryJobId}"
 const ConfigListWebhookEventTypes = "/config/webhooks/event-types"
 const ConfigListWebhookPayloadContentTypes = "/config/webhooks/payload/content-types"
+const ConfigListWebhooksHealth = "/config/webhooks/health"
 
 // Organisation identity provider related API(s)
 const AddIdentityProvider = "/config/idp/open-id"
@@ -54,12 +59,29 @@ const ConfigUpdateIndividual = "/config/individual/{individualId}"
 const ConfigDeleteIndividual = "/config/individual/{individualId}"
 const ConfigListIndividuals = "/config/individuals"
 const ConfigCreateIndividualsInBulk = "/config/individual/upload"
+const ConfigEraseIndividual = "/config/individual/{individualId}/erasure"
+
+// Guardians of individual
+const ConfigCreateIndividualGuardian = "/config/individual/{individualId}/guardian"
+const ConfigListIndividualGuardians = "/config/individual/{individualId}/guardians"
+const ConfigUpdateIndividualGuardian = "/config/individual/{individualId}/guardian/{guardianId}"
+const ConfigDeleteIndividualGuardian = "/config/individual/{individualId}/guardian/{guardianId}"
 
 // Api key
 const ConfigCreateApiKey = "/config/admin/apikey"
 const ConfigUpdateApiKey = "/config/admin/apikey/{apiKeyId}"
 const ConfigDeleteApiKey = "/config/admin/apikey/{apiKeyId}"
 const ConfigListApiKey = "/config/admin/apikeys"
+const ConfigRevokeApiKey = "/config/admin/apikey/{apiKeyId}/revoke"
+const ConfigRetireApiKeySigningSecret = "/config/admin/apikey/signing-secret/{signingSecretId}/retire"
+const ConfigListApiKeySigningSecrets = "/config/admin/apikey/signing-secrets"
+
+// Organisation admins
+const ConfigListAdminRoles = "/config/admin/roles"
+const ConfigUpdateAdminRole = "/config/admin/user/{userId}/role"
+const ConfigInviteAdmin = "/config/admin/user"
+const ConfigListAdmins = "/config/admin/users"
+const ConfigDeleteAdmin = "/config/admin/user/{userId}"
 
 const ConfigReadPrivacyDashboard = "/config/privacy-dashboard"
 
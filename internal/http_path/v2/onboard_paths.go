@@ -27,5 +27,6 @@ const OnboardReadOrganisationAdmin = "/onboard/admin"
 const OnboardUpdateOrganisationAdmin = "/onboard/admin"
 const OnboardReadOrganisationAdminAvatar = "/onboard/admin/avatarimage"
 const OnboardUpdateOrganisationAdminAvatar = "/onboard/admin/avatarimage"
+const OnboardActivateOrganisationAdmin = "/onboard/admin/activate"
 
 const OnboardReadStatus = "/onboard/status"
@@ -0,0 +1,8 @@
+package http_path
+
+// Tenants
+const PlatformCreateTenant = "/platform/tenant"
+const PlatformReadTenant = "/platform/tenant/{organizationId}"
+const PlatformDeleteTenant = "/platform/tenant/{organizationId}"
+const PlatformDisableTenant = "/platform/tenant/{organizationId}/disable"
+const PlatformListTenants = "/platform/tenants"
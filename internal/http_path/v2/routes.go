@@ -4,6 +4,7 @@ import (
 	"net/http"
 
 	auditHandler "github.com/bb-consent/api/internal/handler/v2/audit"
+	adminHandler "github.com/bb-consent/api/internal/handler/v2/config/admin"
 	apiKeyHandler "github.com/bb-consent/api/internal/handler/v2/config/apikey"
 	dataAgreementHandler "github.com/bb-consent/api/internal/handler/v2/config/dataagreement"
 	dataAttributeHandler "github.com/bb-consent/api/internal/handler/v2/config/dataattribute"
@@ -14,6 +15,7 @@ import (
 	privacyDashboardHandler "github.com/bb-consent/api/internal/handler/v2/config/privacy_dashboard"
 	webhookHandler "github.com/bb-consent/api/internal/handler/v2/config/webhook"
 	onboardHandler "github.com/bb-consent/api/internal/handler/v2/onboard"
+	platformTenantHandler "github.com/bb-consent/api/internal/handler/v2/platform/tenant"
 	serviceHandler "github.com/bb-consent/api/internal/handler/v2/service"
 	serviceDataSharingHandler "github.com/bb-consent/api/internal/handler/v2/service/datasharing"
 	serviceIndividualHandler "github.com/bb-consent/api/internal/handler/v2/service/individual"
@@ -59,11 +61,16 @@ func SetRoutes(r *mux.Router, e *casbin.Enforcer, testMode bool) {
 	wrapper(ConfigDeleteWebhook, m.Chain(webhookHandler.ConfigDeleteWebhook, m.Logger(), m.LogApiCalls(), m.Authorize(e), m.SetApplicationMode(), m.ValidateAPIKeyAndIndividualId(), m.Authenticate(), m.AddContentType())).Methods("DELETE")
 	wrapper(ConfigListWebhooks, m.Chain(webhookHandler.ConfigListWebhooks, m.Logger(), m.LogApiCalls(), m.Authorize(e), m.SetApplicationMode(), m.ValidateAPIKeyAndIndividualId(), m.Authenticate(), m.AddContentType())).Methods("GET")
 	wrapper(ConfigPingWebhook, m.Chain(webhookHandler.ConfigPingWebhook, m.Logger(), m.LogApiCalls(), m.Authorize(e), m.SetApplicationMode(), m.ValidateAPIKeyAndIndividualId(), m.Authenticate(), m.AddContentType())).Methods("POST")
+	wrapper(ConfigRotateWebhookSecret, m.Chain(webhookHandler.ConfigRotateWebhookSecret, m.Logger(), m.LogApiCalls(), m.Authorize(e), m.SetApplicationMode(), m.ValidateAPIKeyAndIndividualId(), m.Authenticate(), m.AddContentType())).Methods("POST")
 	wrapper(ConfigListRecentWebhookDeliveries, m.Chain(webhookHandler.ConfigListRecentWebhookDeliveries, m.Logger(), m.LogApiCalls(), m.Authorize(e), m.SetApplicationMode(), m.ValidateAPIKeyAndIndividualId(), m.Authenticate(), m.AddContentType())).Methods("GET")
 	wrapper(ConfigReadRecentWebhookDelivery, m.Chain(webhookHandler.ConfigReadRecentWebhookDelivery, m.Logger(), m.LogApiCalls(), m.Authorize(e), m.SetApplicationMode(), m.ValidateAPIKeyAndIndividualId(), m.Authenticate(), m.AddContentType())).Methods("GET")
 	wrapper(ConfigRedeliverWebhookPayloadByDeliveryID, m.Chain(webhookHandler.ConfigRedeliverWebhookPayloadByDeliveryID, m.Logger(), m.LogApiCalls(), m.Authorize(e), m.SetApplicationMode(), m.ValidateAPIKeyAndIndividualId(), m.Authenticate(), m.AddContentType())).Methods("POST")
+	wrapper(ConfigRedeliverWebhookPayloads, m.Chain(webhookHandler.ConfigRedeliverWebhookPayloads, m.Logger(), m.LogApiCalls(), m.Authorize(e), m.SetApplicationMode(), m.ValidateAPIKeyAndIndividualId(), m.Authenticate(), m.AddContentType())).Methods("POST")
+	wrapper(ConfigListWebhookRedeliveryJobs, m.Chain(webhookHandler.ConfigListWebhookRedeliveryJobs, m.Logger(), m.LogApiCalls(), m.Authorize(e), m.SetApplicationMode(), m.ValidateAPIKeyAndIndividualId(), m.Authenticate(), m.AddContentType())).Methods("GET")
+	wrapper(ConfigReadWebhookRedeliveryJob, m.Chain(webhookHandler.ConfigReadWebhookRedeliveryJob, m.Logger(), m.LogApiCalls(), m.Authorize(e), m.SetApplicationMode(), m.ValidateAPIKeyAndIndividualId(), m.Authenticate(), m.AddContentType())).Methods("GET")
 	wrapper(ConfigListWebhookEventTypes, m.Chain(webhookHandler.ConfigListWebhookEventTypes, m.Logger(), m.LogApiCalls(), m.Authorize(e), m.SetApplicationMode(), m.ValidateAPIKeyAndIndividualId(), m.Authenticate(), m.AddContentType())).Methods("GET")
 	wrapper(ConfigListWebhookPayloadContentTypes, m.Chain(webhookHandler.ConfigListWebhookPayloadContentTypes, m.Logger(), m.LogApiCalls(), m.Authorize(e), m.SetApplicationMode(), m.ValidateAPIKeyAndIndividualId(), m.Authenticate(), m.AddContentType())).Methods("GET")
+	wrapper(ConfigListWebhooksHealth, m.Chain(webhookHandler.ConfigListWebhooksHealth, m.Logger(), m.LogApiCalls(), m.Authorize(e), m.SetApplicationMode(), m.ValidateAPIKeyAndIndividualId(), m.Authenticate(), m.AddContentType())).Methods("GET")
 
 	// Organisation identity provider related API(s)
 	wrapper(AddIdentityProvider, m.Chain(idpHandler.ConfigCreateIdp, m.Logger(), m.LogApiCalls(), m.Authorize(e), m.SetApplicationMode(), m.ValidateAPIKeyAndIndividualId(), m.Authenticate(), m.AddContentType())).Methods("POST")
@@ -78,11 +85,28 @@ func SetRoutes(r *mux.Router, e *casbin.Enforcer, testMode bool) {
 	wrapper(ConfigUpdateIndividual, m.Chain(configIndividualHandler.ConfigUpdateIndividual, m.Logger(), m.LogApiCalls(), m.Authorize(e), m.SetApplicationMode(), m.ValidateAPIKeyAndIndividualId(), m.Authenticate(), m.AddContentType())).Methods("PUT")
 	wrapper(ConfigListIndividuals, m.Chain(configIndividualHandler.ConfigListIndividuals, m.Logger(), m.LogApiCalls(), m.Authorize(e), m.SetApplicationMode(), m.ValidateAPIKeyAndIndividualId(), m.Authenticate(), m.AddContentType())).Methods("GET")
 
+	// Guardians of individual
+	wrapper(ConfigCreateIndividualGuardian, m.Chain(configIndividualHandler.ConfigCreateIndividualGuardian, m.Logger(), m.LogApiCalls(), m.Authorize(e), m.SetApplicationMode(), m.ValidateAPIKeyAndIndividualId(), m.Authenticate(), m.AddContentType())).Methods("POST")
+	wrapper(ConfigListIndividualGuardians, m.Chain(configIndividualHandler.ConfigListIndividualGuardians, m.Logger(), m.LogApiCalls(), m.Authorize(e), m.SetApplicationMode(), m.ValidateAPIKeyAndIndividualId(), m.Authenticate(), m.AddContentType())).Methods("GET")
+	wrapper(ConfigUpdateIndividualGuardian, m.Chain(configIndividualHandler.ConfigUpdateIndividualGuardian, m.Logger(), m.LogApiCalls(), m.Authorize(e), m.SetApplicationMode(), m.ValidateAPIKeyAndIndividualId(), m.Authenticate(), m.AddContentType())).Methods("PUT")
+	wrapper(ConfigDeleteIndividualGuardian, m.Chain(configIndividualHandler.ConfigDeleteIndividualGuardian, m.Logger(), m.LogApiCalls(), m.Authorize(e), m.SetApplicationMode(), m.ValidateAPIKeyAndIndividualId(), m.Authenticate(), m.AddContentType())).Methods("DELETE")
+	wrapper(ConfigEraseIndividual, m.Chain(configIndividualHandler.ConfigEraseIndividual, m.Logger(), m.LogApiCalls(), m.Authorize(e), m.SetApplicationMode(), m.ValidateAPIKeyAndIndividualId(), m.Authenticate(), m.AddContentType())).Methods("POST")
+
 	// Api key related api(s)
 	wrapper(ConfigCreateApiKey, m.Chain(apiKeyHandler.ConfigCreateApiKey, m.Logger(), m.LogApiCalls(), m.Authorize(e), m.SetApplicationMode(), m.ValidateAPIKeyAndIndividualId(), m.Authenticate(), m.AddContentType())).Methods("POST")
 	wrapper(ConfigDeleteApiKey, m.Chain(apiKeyHandler.ConfigDeleteApiKey, m.Logger(), m.LogApiCalls(), m.Authorize(e), m.SetApplicationMode(), m.ValidateAPIKeyAndIndividualId(), m.Authenticate(), m.AddContentType())).Methods("DELETE")
 	wrapper(ConfigUpdateApiKey, m.Chain(apiKeyHandler.ConfigUpdateApiKey, m.Logger(), m.LogApiCalls(), m.Authorize(e), m.SetApplicationMode(), m.ValidateAPIKeyAndIndividualId(), m.Authenticate(), m.AddContentType())).Methods("PUT")
 	wrapper(ConfigListApiKey, m.Chain(apiKeyHandler.ConfigListApiKey, m.Logger(), m.LogApiCalls(), m.Authorize(e), m.SetApplicationMode(), m.ValidateAPIKeyAndIndividualId(), m.Authenticate(), m.AddContentType())).Methods("GET")
+	wrapper(ConfigRevokeApiKey, m.Chain(apiKeyHandler.ConfigRevokeApiKey, m.Logger(), m.LogApiCalls(), m.Authorize(e), m.SetApplicationMode(), m.ValidateAPIKeyAndIndividualId(), m.Authenticate(), m.AddContentType())).Methods("PUT")
+	wrapper(ConfigRetireApiKeySigningSecret, m.Chain(apiKeyHandler.ConfigRetireApiKeySigningSecret, m.Logger(), m.LogApiCalls(), m.Authorize(e), m.SetApplicationMode(), m.ValidateAPIKeyAndIndividualId(), m.Authenticate(), m.AddContentType())).Methods("PUT")
+	wrapper(ConfigListApiKeySigningSecrets, m.Chain(apiKeyHandler.ConfigListApiKeySigningSecrets, m.Logger(), m.LogApiCalls(), m.Authorize(e), m.SetApplicationMode(), m.ValidateAPIKeyAndIndividualId(), m.Authenticate(), m.AddContentType())).Methods("GET")
+
+	// Organisation admin related api(s)
+	wrapper(ConfigListAdminRoles, m.Chain(adminHandler.ConfigListAdminRoles, m.Logger(), m.LogApiCalls(), m.Authorize(e), m.SetApplicationMode(), m.Authenticate(), m.AddContentType())).Methods("GET")
+	wrapper(ConfigUpdateAdminRole, m.Chain(adminHandler.ConfigUpdateAdminRole, m.Logger(), m.LogApiCalls(), m.Authorize(e), m.SetApplicationMode(), m.Authenticate(), m.AddContentType())).Methods("PUT")
+	wrapper(ConfigInviteAdmin, m.Chain(adminHandler.ConfigInviteAdmin, m.Logger(), m.LogApiCalls(), m.Authorize(e), m.SetApplicationMode(), m.Authenticate(), m.AddContentType())).Methods("POST")
+	wrapper(ConfigListAdmins, m.Chain(adminHandler.ConfigListAdmins, m.Logger(), m.LogApiCalls(), m.Authorize(e), m.SetApplicationMode(), m.Authenticate(), m.AddContentType())).Methods("GET")
+	wrapper(ConfigDeleteAdmin, m.Chain(adminHandler.ConfigDeleteAdmin, m.Logger(), m.LogApiCalls(), m.Authorize(e), m.SetApplicationMode(), m.Authenticate(), m.AddContentType())).Methods("DELETE")
 
 	wrapper(ConfigCreateIndividualsInBulk, m.Chain(configIndividualHandler.ConfigCreateIndividualsInBulk, m.Logger(), m.LogApiCalls(), m.Authorize(e), m.SetApplicationMode(), m.ValidateAPIKeyAndIndividualId(), m.Authenticate(), m.AddContentType())).Methods("POST")
 
@@ -117,6 +141,10 @@ func SetRoutes(r *mux.Router, e *casbin.Enforcer, testMode bool) {
 	wrapper(ServiceCreateDataAgreementRecord, m.Chain(serviceHandler.ServiceCreateDataAgreementRecord, m.Logger(), m.ValidateIndividualId(), m.Authorize(e), m.SetApplicationMode(), m.ValidateAPIKeyAndIndividualId(), m.Authenticate(), m.AddContentType())).Methods("POST")
 	wrapper(ServiceUpdateDataAgreementRecord, m.Chain(serviceHandler.ServiceUpdateDataAgreementRecord, m.Logger(), m.ValidateIndividualId(), m.Authorize(e), m.SetApplicationMode(), m.ValidateAPIKeyAndIndividualId(), m.Authenticate(), m.AddContentType())).Methods("PUT")
 	wrapper(ServiceDeleteIndividualDataAgreementRecords, m.Chain(serviceHandler.ServiceDeleteIndividualDataAgreementRecords, m.Logger(), m.ValidateIndividualId(), m.Authorize(e), m.SetApplicationMode(), m.ValidateAPIKeyAndIndividualId(), m.Authenticate(), m.AddContentType())).Methods("DELETE")
+	wrapper(ServiceEraseIndividual, m.Chain(serviceHandler.ServiceEraseIndividual, m.Logger(), m.ValidateIndividualId(), m.Authorize(e), m.SetApplicationMode(), m.ValidateAPIKeyAndIndividualId(), m.Authenticate(), m.AddContentType())).Methods("POST")
+	wrapper(ServiceCreateDataExport, m.Chain(serviceHandler.ServiceCreateDataExport, m.Logger(), m.ValidateIndividualId(), m.Authorize(e), m.SetApplicationMode(), m.ValidateAPIKeyAndIndividualId(), m.Authenticate(), m.AddContentType())).Methods("POST")
+	wrapper(ServiceReadDataExport, m.Chain(serviceHandler.ServiceReadDataExport, m.Logger(), m.ValidateIndividualId(), m.Authorize(e), m.SetApplicationMode(), m.ValidateAPIKeyAndIndividualId(), m.Authenticate(), m.AddContentType())).Methods("GET")
+	wrapper(ServiceDownloadDataExport, m.Chain(serviceHandler.ServiceDownloadDataExport, m.Logger(), m.ValidateIndividualId(), m.Authorize(e), m.SetApplicationMode(), m.ValidateAPIKeyAndIndividualId(), m.Authenticate(), m.AddContentType())).Methods("GET")
 	wrapper(ServiceCreatePairedDataAgreementRecord, m.Chain(serviceHandler.ServiceCreatePairedDataAgreementRecord, m.Logger(), m.ValidateIndividualId(), m.Authorize(e), m.SetApplicationMode(), m.ValidateAPIKeyAndIndividualId(), m.Authenticate(), m.AddContentType())).Methods("POST")
 	wrapper(ServiceUpdateSignatureObject, m.Chain(serviceHandler.ServiceUpdateSignatureObject, m.Logger(), m.ValidateIndividualId(), m.Authorize(e), m.SetApplicationMode(), m.ValidateAPIKeyAndIndividualId(), m.Authenticate(), m.AddContentType())).Methods("PUT")
 	wrapper(ServiceCreateBlankSignature, m.Chain(serviceHandler.ServiceCreateBlankSignature, m.Logger(), m.ValidateIndividualId(), m.Authorize(e), m.SetApplicationMode(), m.ValidateAPIKeyAndIndividualId(), m.Authenticate(), m.AddContentType())).Methods("POST")
@@ -126,10 +154,12 @@ func SetRoutes(r *mux.Router, e *casbin.Enforcer, testMode bool) {
 	wrapper(ServiceFetchRecordsForDataAgreement, m.Chain(serviceHandler.ServiceFetchRecordsForDataAgreement, m.Logger(), m.ValidateIndividualId(), m.Authorize(e), m.SetApplicationMode(), m.ValidateAPIKeyAndIndividualId(), m.Authenticate(), m.AddContentType())).Methods("GET")
 
 	wrapper(ServiceFetchRecordsHistory, m.Chain(serviceHandler.ServiceFetchRecordsHistory, m.Logger(), m.ValidateIndividualId(), m.Authorize(e), m.SetApplicationMode(), m.ValidateAPIKeyAndIndividualId(), m.Authenticate(), m.AddContentType())).Methods("GET")
+	wrapper(ServiceListRenewalRequiredDataAgreementRecords, m.Chain(serviceHandler.ServiceListRenewalRequiredDataAgreementRecords, m.Logger(), m.ValidateIndividualId(), m.Authorize(e), m.SetApplicationMode(), m.ValidateAPIKeyAndIndividualId(), m.Authenticate(), m.AddContentType())).Methods("GET")
 
 	wrapper(ServiceReadOrganisation, m.Chain(serviceHandler.ServiceReadOrganisation, m.LoggerNoAuth(), m.SetApplicationMode(), m.AddContentType())).Methods("GET")
 	wrapper(ServiceReadOrganisationLogoImage, m.Chain(serviceHandler.ServiceReadOrganisationLogoImage, m.LoggerNoAuth(), m.SetApplicationMode(), m.AddContentType())).Methods("GET")
 	wrapper(ServiceReadOrganisationCoverImage, m.Chain(serviceHandler.ServiceReadOrganisationCoverImage, m.LoggerNoAuth(), m.SetApplicationMode(), m.AddContentType())).Methods("GET")
+	wrapper(ServiceReadOrganisationJwks, m.Chain(serviceHandler.ServiceReadOrganisationJwks, m.LoggerNoAuth(), m.SetApplicationMode(), m.AddContentType())).Methods("GET")
 	wrapper(ServiceReadOrganisationImage, m.Chain(serviceHandler.ServiceReadOrganisationImage, m.Logger(), m.ValidateIndividualId(), m.Authorize(e), m.SetApplicationMode(), m.ValidateAPIKey(), m.Authenticate(), m.AddContentType())).Methods("GET")
 
 	// Individual related api(s)
@@ -141,6 +171,8 @@ func SetRoutes(r *mux.Router, e *casbin.Enforcer, testMode bool) {
 	// Audit api(s)
 
 	wrapper(AuditListDataAgreementRecords, m.Chain(auditHandler.AuditListDataAgreementRecords, m.Logger(), m.LogApiCalls(), m.Authorize(e), m.SetApplicationMode(), m.ValidateAPIKeyAndIndividualId(), m.Authenticate(), m.AddContentType())).Methods("GET")
+	wrapper(AuditStreamEvents, m.Chain(auditHandler.AuditStreamEvents, m.Logger(), m.LogApiCalls(), m.Authorize(e), m.SetApplicationMode(), m.ValidateAPIKeyAndIndividualId(), m.Authenticate(), m.AddContentType())).Methods("GET")
+	wrapper(AuditStreamEventsWebSocket, m.Chain(auditHandler.AuditStreamEventsWebSocket, m.Logger(), m.LogApiCalls(), m.Authorize(e), m.SetApplicationMode(), m.ValidateAPIKeyAndIndividualId(), m.Authenticate(), m.AddContentType())).Methods("GET")
 	wrapper(AuditDataAgreementRecordRead, m.Chain(auditHandler.AuditDataAgreementRecordRead, m.Logger(), m.LogApiCalls(), m.Authorize(e), m.SetApplicationMode(), m.ValidateAPIKeyAndIndividualId(), m.Authenticate(), m.AddContentType())).Methods("GET")
 	wrapper(AuditListDataAgreements, m.Chain(auditHandler.AuditListDataAgreements, m.Logger(), m.LogApiCalls(), m.Authorize(e), m.SetApplicationMode(), m.ValidateAPIKeyAndIndividualId(), m.Authenticate(), m.AddContentType())).Methods("GET")
 	wrapper(AuditReadDataAgreement, m.Chain(auditHandler.AuditReadDataAgreement, m.Logger(), m.LogApiCalls(), m.Authorize(e), m.SetApplicationMode(), m.ValidateAPIKeyAndIndividualId(), m.Authenticate(), m.AddContentType())).Methods("GET")
@@ -151,11 +183,12 @@ func SetRoutes(r *mux.Router, e *casbin.Enforcer, testMode bool) {
 	// Onboard api(s)
 
 	wrapper(LoginAdminUser, m.Chain(onboardHandler.LoginAdminUser, m.LoggerNoAuth(), m.AddContentType())).Methods("POST")
+	wrapper(OnboardActivateOrganisationAdmin, m.Chain(onboardHandler.OnboardActivateOrganisationAdmin, m.LoggerNoAuth(), m.AddContentType())).Methods("POST")
 	wrapper(LoginUser, m.Chain(onboardHandler.LoginUser, m.LoggerNoAuth(), m.AddContentType())).Methods("POST")
 	wrapper(OnboardResetPassword, m.Chain(onboardHandler.OnboardResetPassword, m.Logger(), m.Authorize(e), m.SetApplicationMode(), m.ValidateAPIKeyAndIndividualId(), m.Authenticate(), m.AddContentType())).Methods("PUT")
 	wrapper(OnboardLogoutUser, m.Chain(onboardHandler.OnboardLogoutUser, m.Logger(), m.Authorize(e), m.SetApplicationMode(), m.ValidateAPIKeyAndIndividualId(), m.Authenticate(), m.AddContentType())).Methods("POST")
 
-	wrapper(OnboardRefreshToken, m.Chain(onboardHandler.OnboardRefreshToken, m.AddContentType())).Methods("POST")
+	wrapper(OnboardRefreshToken, m.Chain(onboardHandler.OnboardRefreshToken, m.SetApplicationMode(), m.AddContentType())).Methods("POST")
 	wrapper(ExchangeAuthorizationCode, m.Chain(onboardHandler.ExchangeAuthorizationCode, m.LoggerNoAuth(), m.SetApplicationMode())).Methods("POST")
 	wrapper(OnboardForgotPassword, m.Chain(onboardHandler.OnboardForgotPassword, m.LoggerNoAuth(), m.SetApplicationMode())).Methods("PUT")
 
@@ -173,5 +206,14 @@ func SetRoutes(r *mux.Router, e *casbin.Enforcer, testMode bool) {
 
 	wrapper(OnboardReadStatus, m.Chain(onboardHandler.OnboardReadStatus, m.Logger(), m.Authorize(e), m.SetApplicationMode(), m.ValidateAPIKeyAndIndividualId(), m.Authenticate(), m.AddContentType())).Methods("GET")
 
+	// Platform api(s)
+
+	// Tenants
+	wrapper(PlatformCreateTenant, m.Chain(platformTenantHandler.PlatformCreateTenant, m.Logger(), m.Authorize(e), m.AuthenticatePlatformOperator(), m.AddContentType())).Methods("POST")
+	wrapper(PlatformReadTenant, m.Chain(platformTenantHandler.PlatformReadTenant, m.Logger(), m.Authorize(e), m.AuthenticatePlatformOperator(), m.AddContentType())).Methods("GET")
+	wrapper(PlatformDeleteTenant, m.Chain(platformTenantHandler.PlatformDeleteTenant, m.Logger(), m.Authorize(e), m.AuthenticatePlatformOperator(), m.AddContentType())).Methods("DELETE")
+	wrapper(PlatformDisableTenant, m.Chain(platformTenantHandler.PlatformDisableTenant, m.Logger(), m.Authorize(e), m.AuthenticatePlatformOperator(), m.AddContentType())).Methods("PUT")
+	wrapper(PlatformListTenants, m.Chain(platformTenantHandler.PlatformListTenants, m.Logger(), m.Authorize(e), m.AuthenticatePlatformOperator(), m.AddContentType())).Methods("GET")
+
 	wrapper(ServiceShowDataSharingUi, m.Chain(serviceDataSharingHandler.ServiceShowDataSharingUiHandler, m.LoggerNoAuth())).Methods("GET")
 }
@@ -21,6 +21,12 @@ const ServiceCreateDataAgreementRecord = "/service/individual/record/data-agreem
 const ServiceReadDataAgreementRecord = "/service/individual/record/data-agreement/{dataAgreementId}"
 const ServiceUpdateDataAgreementRecord = "/service/individual/record/consent-record/{consentRecordId}"
 const ServiceDeleteIndividualDataAgreementRecords = "/service/individual/record"
+const ServiceEraseIndividual = "/service/individual/erasure"
+
+// Data export
+const ServiceCreateDataExport = "/service/individual/data-export"
+const ServiceReadDataExport = "/service/individual/data-export/{exportId}"
+const ServiceDownloadDataExport = "/service/individual/data-export/{exportId}/archive"
 const ServiceCreatePairedDataAgreementRecord = "/service/individual/record/consent-record"
 
 const ServiceCreateBlankSignature = "/service/individual/record/consent-record/{consentRecordId}/signature"
@@ -30,6 +36,7 @@ const ServiceFetchIndividualDataAgreementRecords = "/service/individual/record/c
 const ServiceFetchRecordsForDataAgreement = "/service/individual/record/data-agreement/{dataAgreementId}/all"
 
 const ServiceFetchRecordsHistory = "/service/individual/record/consent-record/history"
+const ServiceListRenewalRequiredDataAgreementRecords = "/service/individual/record/consent-record/renewal-required"
 
 // Idp
 const ServiceReadIdp = "/service/idp/open-id"
@@ -39,6 +46,7 @@ const ServiceReadOrganisation = "/service/organisation"
 const ServiceReadOrganisationLogoImage = "/service/organisation/logoimage"
 const ServiceReadOrganisationCoverImage = "/service/organisation/coverimage"
 const ServiceReadOrganisationImage = "/service/image/{imageId}"
+const ServiceReadOrganisationJwks = "/service/organisation/jwks"
 
 // Individuals
 const ServiceCreateIndividual = "/service/individual"
@@ -183,6 +183,67 @@ func RegisterUser(email string, name string) (string, error) {
 	return iamId, nil
 }
 
+// RegisterOrganisationAdmin Registers an organisation admin without credentials, the
+// password is set by the admin while activating the account
+func RegisterOrganisationAdmin(email string, name string) (string, error) {
+	user := gocloak.User{
+		FirstName: &name,
+		Email:     &email,
+		Username:  &email,
+		Enabled:   gocloak.BoolP(true),
+	}
+
+	client := GetClient()
+
+	token, err := GetAdminToken(IamConfig.AdminUser, IamConfig.AdminPassword, "master", client)
+	if err != nil {
+		return "", err
+	}
+
+	iamId, err := client.CreateUser(context.Background(), token.AccessToken, IamConfig.Realm, user)
+	if err != nil {
+		return "", err
+	}
+
+	role, err := client.GetRealmRole(context.Background(), token.AccessToken, IamConfig.Realm, "organization-admin")
+	if err != nil {
+		return iamId, err
+	}
+
+	err = client.AddRealmRoleToUser(context.Background(), token.AccessToken, IamConfig.Realm, iamId, []gocloak.Role{*role})
+	if err != nil {
+		return iamId, err
+	}
+
+	return iamId, nil
+}
+
+// UnregisterOrganisationAdmin Removes the organisation admin from iam, for
+// e.g. when the invitation of the admin can't be completed
+func UnregisterOrganisationAdmin(iamId string) error {
+	client := GetClient()
+
+	token, err := GetAdminToken(IamConfig.AdminUser, IamConfig.AdminPassword, "master", client)
+	if err != nil {
+		return err
+	}
+
+	return client.DeleteUser(context.Background(), token.AccessToken, IamConfig.Realm, iamId)
+}
+
+// SetPassword Sets the password of the user
+func SetPassword(iamId string, password string) error {
+	client := GetClient()
+
+	token, err := GetAdminToken(IamConfig.AdminUser, IamConfig.AdminPassword, "master", client)
+	if err != nil {
+		return err
+	}
+
+	err = client.SetPassword(context.Background(), token.AccessToken, iamId, IamConfig.Realm, password, false)
+	return err
+}
+
 // UpdateIamIndividual Update individual info on IAM server end.
 func UpdateIamIndividual(name string, iamID string, email string) error {
 	user := gocloak.User{
@@ -102,3 +102,23 @@ func (iRepo *IndividualRepository) GetByIamID(iamId string) (Individual, error)
 
 	return result, err
 }
+
+// UpdateWithContext Updates the individual, context is used for transactions
+func (iRepo *IndividualRepository) UpdateWithContext(ctx context.Context, individual Individual) (Individual, error) {
+	filter := bson.M{"organisationid": iRepo.DefaultFilter["organisationid"], "_id": individual.Id}
+	update := bson.M{"$set": individual}
+
+	_, err := Collection().UpdateOne(ctx, filter, update)
+	if err != nil {
+		return individual, err
+	}
+	return individual, nil
+}
+
+// DeleteWithContext Deletes the individual, context is used for transactions
+func (iRepo *IndividualRepository) DeleteWithContext(ctx context.Context, individualId string) error {
+	filter := bson.M{"organisationid": iRepo.DefaultFilter["organisationid"], "_id": individualId}
+
+	_, err := Collection().DeleteOne(ctx, filter)
+	return err
+}
@@ -0,0 +1,160 @@
+package invitation
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/url"
+	"time"
+
+	"github.com/bb-consent/api/internal/config"
+	"github.com/bb-consent/api/internal/database"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Invitation Invitation for an organisation admin to activate the account.
+// Users having an account are added to the organisation only once they
+// accept the invitation. Only the hash of the activation token is persisted.
+type Invitation struct {
+	Id              string `json:"id" bson:"_id,omitempty"`
+	OrganisationId  string `json:"organisationId"`
+	UserId          string `json:"userId"`
+	Email           string `json:"email"`
+	RoleId          int    `json:"roleId"`
+	ExistingUser    bool   `json:"existingUser"` // User had an account when invited
+	TokenHash       string `json:"-"`
+	Timestamp       string `json:"timestamp"`
+	ExpiryTimestamp string `json:"expiryTimestamp"`
+	Accepted        bool   `json:"accepted"`
+}
+
+var ErrInvitationExpired = errors.New("invitation expired")
+var ErrInvitationAccepted = errors.New("invitation already accepted")
+
+var AdminInvitation config.AdminInvitation
+
+// Init Initialize the admin invitation configuration
+func Init(config *config.Configuration) {
+	AdminInvitation = config.AdminInvitation
+	if AdminInvitation.ExpiryInHours <= 0 {
+		// Default invitation expiry 3 days
+		AdminInvitation.ExpiryInHours = 72
+	}
+}
+
+func Collection() *mongo.Collection {
+	return database.DB.Client.Database(database.DB.Name).Collection("invitations")
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func generateToken() (string, error) {
+	b := make([]byte, 32)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// Create Creates an invitation and returns the activation token
+func Create(organisationId string, userId string, email string, roleId int, existingUser bool) (Invitation, string, error) {
+	token, err := generateToken()
+	if err != nil {
+		return Invitation{}, "", err
+	}
+
+	now := time.Now().UTC()
+	invitation := Invitation{
+		Id:              primitive.NewObjectID().Hex(),
+		OrganisationId:  organisationId,
+		UserId:          userId,
+		Email:           email,
+		RoleId:          roleId,
+		ExistingUser:    existingUser,
+		TokenHash:       hashToken(token),
+		Timestamp:       now.Format("2006-01-02T15:04:05Z"),
+		ExpiryTimestamp: now.Add(time.Duration(AdminInvitation.ExpiryInHours) * time.Hour).Format("2006-01-02T15:04:05Z"),
+	}
+
+	_, err = Collection().InsertOne(context.TODO(), invitation)
+	if err != nil {
+		return Invitation{}, "", err
+	}
+	return invitation, token, nil
+}
+
+// GetByToken Gets a pending invitation by activation token
+func GetByToken(token string) (Invitation, error) {
+	var result Invitation
+
+	err := Collection().FindOne(context.TODO(), bson.M{"tokenhash": hashToken(token)}).Decode(&result)
+	if err != nil {
+		return result, err
+	}
+	if result.Accepted {
+		return result, ErrInvitationAccepted
+	}
+	expiryTime, err := time.Parse("2006-01-02T15:04:05Z", result.ExpiryTimestamp)
+	if err != nil || expiryTime.Before(time.Now()) {
+		return result, ErrInvitationExpired
+	}
+	return result, nil
+}
+
+// Accept Marks the pending invitation as accepted, the invitation is
+// accepted only once even if the token is used concurrently
+func Accept(token string) (Invitation, error) {
+	var result Invitation
+
+	filter := bson.M{
+		"tokenhash":       hashToken(token),
+		"accepted":        false,
+		"expirytimestamp": bson.M{"$gt": time.Now().UTC().Format("2006-01-02T15:04:05Z")},
+	}
+	update := bson.M{"$set": bson.M{"accepted": true}}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	err := Collection().FindOneAndUpdate(context.TODO(), filter, update, opts).Decode(&result)
+	if err == mongo.ErrNoDocuments {
+		// Reason the invitation can't be accepted
+		if _, err := GetByToken(token); err != nil {
+			return result, err
+		}
+		return result, ErrInvitationAccepted
+	}
+	return result, err
+}
+
+// Reopen Marks the accepted invitation as pending, for e.g. when activating
+// the account failed after accepting the invitation
+func Reopen(invitationId string) error {
+	_, err := Collection().UpdateOne(context.TODO(), bson.M{"_id": invitationId}, bson.M{"$set": bson.M{"accepted": false}})
+	return err
+}
+
+// DeleteAllForUser Deletes the invitations of the user to an organisation
+func DeleteAllForUser(organisationId string, userId string) error {
+	_, err := Collection().DeleteMany(context.TODO(), bson.M{"organisationid": organisationId, "userid": userId})
+	return err
+}
+
+// GetActivationLink Returns the link the invited admin follows to activate the account
+func GetActivationLink(token string) string {
+	activationUrl, err := url.Parse(AdminInvitation.ActivationUrl)
+	if err != nil {
+		return AdminInvitation.ActivationUrl + "?token=" + url.QueryEscape(token)
+	}
+	query := activationUrl.Query()
+	query.Set("token", token)
+	activationUrl.RawQuery = query.Encode()
+	return activationUrl.String()
+}
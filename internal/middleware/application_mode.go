@@ -1,19 +1,29 @@
 package middleware
 
 import (
+	"errors"
+	"net"
 	"net/http"
+	"strings"
 
 	"github.com/bb-consent/api/internal/common"
 	"github.com/bb-consent/api/internal/config"
 	"github.com/bb-consent/api/internal/org"
+	"github.com/bb-consent/api/internal/token"
 )
 
 var ApplicationMode string
 var Organization config.Organization
+var MultiTenancy config.MultiTenancy
+
+var errOrganisationNotSpecified = errors.New("organisation is not specified in the request")
+var errOrganisationDisabled = errors.New("organisation is disabled")
+var errOrganisationMismatch = errors.New("requested organisation doesn't match the authenticated organisation")
 
 func ApplicationModeInit(config *config.Configuration) {
 	ApplicationMode = config.ApplicationMode
 	Organization = config.Organization
+	MultiTenancy = config.MultiTenancy
 }
 
 // SetApplicationMode sets application modes for routes to either single tenant or multi tenant
@@ -33,6 +43,18 @@ func SetApplicationMode() Middleware {
 				}
 
 			case config.MultiTenant:
+				err := multiTenantConfig(r)
+				if err != nil {
+					if errors.Is(err, errOrganisationMismatch) {
+						m := "Unauthorized access;User doesn't have access to the organization;"
+						common.HandleError(w, http.StatusForbidden, m, err)
+						return
+					}
+					m := "Failed to find organization"
+					common.HandleError(w, http.StatusBadRequest, m, err)
+					return
+				}
+
 			default:
 				err := singleTenantConfig(r)
 				if err != nil {
@@ -58,3 +80,64 @@ func singleTenantConfig(r *http.Request) error {
 	r.Header.Set(config.OrganizationId, organizationId)
 	return nil
 }
+
+// multiTenantConfig Resolves the organisation for the request. Organisation
+// identified while authenticating the caller takes precedence, otherwise
+// the organisation header or host mapping is used.
+func multiTenantConfig(r *http.Request) error {
+	requestedOrganisationId := getRequestedOrganisationId(r)
+
+	organisationId := token.GetOrganisationId(r)
+	if len(organisationId) == 0 {
+		organisationId = requestedOrganisationId
+	} else if len(requestedOrganisationId) > 0 && requestedOrganisationId != organisationId {
+		return errOrganisationMismatch
+	}
+
+	organization, err := getEnabledOrganisation(organisationId)
+	if err != nil {
+		return err
+	}
+	r.Header.Set(config.OrganizationId, organization.ID)
+	return nil
+}
+
+// getRequestedOrganisationId Returns the organisation explicitly requested
+// by the client either through the organisation header or the request host
+func getRequestedOrganisationId(r *http.Request) string {
+	organisationId := strings.TrimSpace(r.Header.Get(config.OrganisationHeaderKey))
+	if len(organisationId) > 0 {
+		return common.Sanitize(organisationId)
+	}
+
+	host := r.Host
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	return MultiTenancy.HostMapping[strings.ToLower(host)]
+}
+
+// getEnabledOrganisation
+func getEnabledOrganisation(organisationId string) (org.Organization, error) {
+	if len(organisationId) == 0 {
+		return org.Organization{}, errOrganisationNotSpecified
+	}
+
+	organization, err := org.Get(organisationId)
+	if err != nil {
+		return organization, err
+	}
+	if !organization.Enabled {
+		return organization, errOrganisationDisabled
+	}
+	return organization, nil
+}
+
+// getOrganisationForRequest Returns the organisation the request is
+// addressed to before the caller has been identified
+func getOrganisationForRequest(r *http.Request) (org.Organization, error) {
+	if ApplicationMode != config.MultiTenant {
+		return org.GetFirstOrganization()
+	}
+	return getEnabledOrganisation(getRequestedOrganisationId(r))
+}
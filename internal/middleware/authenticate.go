@@ -2,9 +2,12 @@ package middleware
 
 import (
 	"context"
+	"errors"
 	"log"
 	"net/http"
 
+	"github.com/bb-consent/api/internal/common"
+	"github.com/bb-consent/api/internal/config"
 	"github.com/bb-consent/api/internal/error_handler"
 	"github.com/bb-consent/api/internal/idp"
 	"github.com/bb-consent/api/internal/individual"
@@ -61,17 +64,81 @@ func storeAccessTokenInRequestContext(headerValue string, w http.ResponseWriter,
 	token.Set(r, t)
 }
 
-func verifyTokenAndIdentifyRole(accessToken string, r *http.Request) error {
-	// Get organisation
-	organization, err := org.GetFirstOrganization()
-	if err != nil {
-		m := "Failed to fetch organisation"
-		error_handler.Exit(http.StatusInternalServerError, m)
+// getOrganisationIdForAdmin Returns the organisation the admin is acting on.
+// In multi tenant mode the organisation must be requested and is honoured
+// only if the admin has a role in it.
+func getOrganisationIdForAdmin(u user.User, r *http.Request) (string, error) {
+	if ApplicationMode != config.MultiTenant {
+		organization, err := org.GetFirstOrganization()
+		if err != nil {
+			return "", err
+		}
+		return organization.ID, nil
+	}
+
+	requestedOrganisationId := getRequestedOrganisationId(r)
+	if len(requestedOrganisationId) == 0 {
+		return "", errOrganisationNotSpecified
+	}
+	for _, role := range u.Roles {
+		if role.OrgID == requestedOrganisationId {
+			return role.OrgID, nil
+		}
 	}
+	return "", errOrganisationMismatch
+}
 
+// getRoleForOrganisation Returns the rbac role assigned to the user in the organisation
+func getRoleForOrganisation(u user.User, organisationId string) string {
+	role := u.Roles[0]
+	for _, r := range u.Roles {
+		if r.OrgID == organisationId {
+			role = r
+			break
+		}
+	}
+	return rbac.GetRbacRoleForOrgRole(common.GetRole(role.RoleID).Role)
+}
+
+func verifyTokenAndIdentifyRole(accessToken string, r *http.Request) error {
 	// Verify token against Consent BB IDP
 	tokenPayload, err := token.ParseToken(accessToken)
 
+	if err == nil {
+		// If user is an organisation admin, organisation is
+		// identified from the roles assigned to the user
+		u, err := user.GetByIamID(tokenPayload.IamID)
+		if err == nil && len(u.Roles) > 0 {
+			organisationId, err := getOrganisationIdForAdmin(u, r)
+			if err != nil {
+				switch {
+				case errors.Is(err, errOrganisationNotSpecified):
+					m := "Failed to find organization"
+					error_handler.Exit(http.StatusBadRequest, m)
+				case errors.Is(err, errOrganisationMismatch):
+					m := "Unauthorized access;User doesn't have access to the organization;"
+					error_handler.Exit(http.StatusForbidden, m)
+				default:
+					m := "Failed to fetch organisation"
+					error_handler.Exit(http.StatusInternalServerError, m)
+				}
+			}
+			token.SetOrganisationId(r, organisationId)
+
+			// Set user Id and user roles to request context
+			token.SetUserToRequestContext(r, u.ID, getRoleForOrganisation(u, organisationId))
+			return nil
+		}
+	}
+
+	// Get organisation
+	organization, orgErr := getOrganisationForRequest(r)
+	if orgErr != nil {
+		m := "Failed to fetch organisation"
+		error_handler.Exit(http.StatusBadRequest, m)
+	}
+	token.SetOrganisationId(r, organization.ID)
+
 	// Repository
 	individualRepo := individual.IndividualRepository{}
 	individualRepo.Init(organization.ID)
@@ -143,11 +210,7 @@ func verifyTokenAndIdentifyRole(accessToken string, r *http.Request) error {
 	}
 
 	// Set user Id and user roles to request context
-	if len(user.Roles) > 0 {
-		token.SetUserToRequestContext(r, user.ID, rbac.ROLE_ADMIN)
-	} else {
-		token.SetUserToRequestContext(r, user.ID, rbac.ROLE_USER)
-	}
+	token.SetUserToRequestContext(r, user.ID, rbac.ROLE_USER)
 
 	return nil
 }
@@ -0,0 +1,68 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/bb-consent/api/internal/config"
+	"github.com/bb-consent/api/internal/error_handler"
+	"github.com/bb-consent/api/internal/rbac"
+	"github.com/bb-consent/api/internal/token"
+)
+
+var PlatformOperator config.PlatformOperator
+
+// PlatformOperatorInit Initializes the platform operators allowed to provision tenants
+func PlatformOperatorInit(config *config.Configuration) {
+	PlatformOperator = config.PlatformOperator
+}
+
+func isPlatformOperator(t token.AccessToken) bool {
+	for _, username := range PlatformOperator.Usernames {
+		if strings.EqualFold(username, t.PreferredUsername) || strings.EqualFold(username, t.Email) {
+			return true
+		}
+	}
+	return false
+}
+
+// AuthenticatePlatformOperator Validates the token and checks the user is a platform operator.
+func AuthenticatePlatformOperator() Middleware {
+
+	// Create a new Middleware
+	return func(f http.HandlerFunc) http.HandlerFunc {
+
+		// Define the http.HandlerFunc
+		return func(w http.ResponseWriter, r *http.Request) {
+			// To catch panic and recover the error
+			// Once the error is recovered respond by
+			// writing the error to HTTP response
+			defer error_handler.HandleExit(w)
+			headerType, headerValue := getAccessTokenFromHeader(w, r)
+
+			if headerType != token.AuthorizationToken {
+				m := "Invalid authorization header, Authorization failed"
+				error_handler.Exit(http.StatusUnauthorized, m)
+			}
+
+			t, err := token.ParseToken(headerValue)
+			if err != nil {
+				m := "Invalid token, Authorization failed"
+				error_handler.Exit(http.StatusUnauthorized, m)
+			}
+			token.Set(r, t)
+
+			if !isPlatformOperator(t) {
+				m := "Unauthorized access;User is not a platform operator;"
+				error_handler.Exit(http.StatusForbidden, m)
+			}
+
+			// Set user Id and user roles to request context
+			token.SetUserID(r, t.IamID)
+			token.SetUserRole(r, rbac.ROLE_PLATFORM_OPERATOR)
+
+			// Call the next middleware/handler in chain
+			f(w, r)
+		}
+	}
+}
@@ -4,10 +4,15 @@ import (
 	"errors"
 	"log"
 	"net/http"
+	"strings"
 
+	"github.com/bb-consent/api/internal/apikey"
 	"github.com/bb-consent/api/internal/common"
+	"github.com/bb-consent/api/internal/config"
+	daRecord "github.com/bb-consent/api/internal/dataagreement_record"
 	"github.com/bb-consent/api/internal/token"
 	"github.com/casbin/casbin/v2"
+	"github.com/gorilla/mux"
 )
 
 func Authorize(e *casbin.Enforcer) Middleware {
@@ -43,6 +48,7 @@ func Authorize(e *casbin.Enforcer) Middleware {
 			if headerType == token.AuthorizationAPIKey {
 				// decode claims
 				claims := decodeApiKey(headerValue, w)
+				token.SetApiKeyClaims(r, claims)
 				res, err := verifyApiKeyScope(claims.Scopes, e, r)
 				if err != nil {
 					m := "Failed to enforce casbin authentication;"
@@ -55,6 +61,21 @@ func Authorize(e *casbin.Enforcer) Middleware {
 					common.HandleError(w, http.StatusForbidden, m, nil)
 					return
 				}
+				// verify data agreements the apikey is restricted to
+				if len(claims.DataAgreementIds) > 0 {
+					res, err = verifyApiKeyDataAgreements(claims, r)
+					if err != nil {
+						m := "Failed to identify the data agreement for the request;"
+						common.HandleError(w, http.StatusBadRequest, m, err)
+						return
+					}
+					if !res {
+						log.Printf("Api key is not allowed to access the data agreement")
+						m := "Unauthorized access;Api key is not allowed to access the data agreement;"
+						common.HandleError(w, http.StatusForbidden, m, nil)
+						return
+					}
+				}
 			}
 
 			// Call the next middleware/handler in chain
@@ -79,3 +100,65 @@ func verifyApiKeyScope(Scopes []string, e *casbin.Enforcer, r *http.Request) (bo
 	}
 	return false, nil
 }
+
+// getDataAgreementIdForRequest Returns the data agreement addressed by the
+// request either through the path, query params or the consent record
+func getDataAgreementIdForRequest(organisationId string, r *http.Request) (string, error) {
+	vars := mux.Vars(r)
+	if dataAgreementId, ok := vars[config.DataAgreementId]; ok {
+		return common.Sanitize(dataAgreementId), nil
+	}
+	if dataAgreementId := r.URL.Query().Get(config.DataAgreementId); len(dataAgreementId) > 0 {
+		return common.Sanitize(dataAgreementId), nil
+	}
+	if consentRecordId, ok := vars[config.ConsentRecordId]; ok {
+		// Repository
+		darRepo := daRecord.DataAgreementRecordRepository{}
+		darRepo.Init(organisationId)
+
+		consentRecord, err := darRepo.Get(common.Sanitize(consentRecordId))
+		if err != nil {
+			return "", err
+		}
+		return consentRecord.DataAgreementId, nil
+	}
+	return "", nil
+}
+
+// isDataAgreementRoute Checks if the route exposes data agreements or consent records
+func isDataAgreementRoute(path string) bool {
+	return strings.Contains(path, "/data-agreement") ||
+		strings.Contains(path, "/consent-record") ||
+		strings.Contains(path, "/individual/record") ||
+		strings.Contains(path, "/audit/events")
+}
+
+// isIndividualRoute Checks if the route acts on all the data of the
+// individual across data agreements, api keys restricted to data agreements
+// are denied access to them
+func isIndividualRoute(path string) bool {
+	return strings.HasSuffix(strings.TrimSuffix(path, "/"), "/erasure") ||
+		strings.Contains(path, "/individual/data-export")
+}
+
+// verifyApiKeyDataAgreements verify the request addresses a data agreement the apikey is restricted to.
+// Routes exposing data agreements or consent records without addressing a single data agreement are denied.
+func verifyApiKeyDataAgreements(claims apikey.Claims, r *http.Request) (bool, error) {
+	if isIndividualRoute(r.URL.Path) {
+		return false, nil
+	}
+
+	dataAgreementId, err := getDataAgreementIdForRequest(claims.OrganisationId, r)
+	if err != nil {
+		return false, err
+	}
+	if len(dataAgreementId) == 0 {
+		return !isDataAgreementRoute(r.URL.Path), nil
+	}
+	for _, allowed := range claims.DataAgreementIds {
+		if dataAgreementId == allowed {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -23,6 +23,7 @@ func apiKeyAuthentication(claims apikey.Claims, w http.ResponseWriter, r *http.R
 	t.Email = orgAdmin.Email
 	t.IamID = orgAdmin.IamID
 	token.Set(r, t)
+	token.SetOrganisationId(r, claims.OrganisationId)
 	token.SetUserToRequestContext(r, claims.OrganisationAdminId, rbac.ROLE_ADMIN)
 
 }
@@ -44,6 +45,7 @@ func ValidateAPIKey() Middleware {
 			if headerType == token.AuthorizationAPIKey {
 				claims := decodeApiKey(headerValue, w)
 				apiKeyAuthentication(claims, w, r)
+				recordApiKeyUsage(claims, r)
 			}
 
 			// Call the next middleware/handler in chain
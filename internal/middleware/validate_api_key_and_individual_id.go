@@ -1,6 +1,8 @@
 package middleware
 
 import (
+	"log"
+	"net"
 	"net/http"
 	"strings"
 
@@ -14,7 +16,7 @@ import (
 )
 
 func decodeApiKey(headerValue string, w http.ResponseWriter) apikey.Claims {
-	claims, err := apikey.Decode(headerValue)
+	claims, err := apikey.Authenticate(headerValue)
 
 	if err != nil {
 		m := "Invalid token, Authorization failed"
@@ -24,9 +26,35 @@ func decodeApiKey(headerValue string, w http.ResponseWriter) apikey.Claims {
 	return claims
 }
 
+// recordApiKeyUsage Records the api key usage, failure to record doesn't fail the request
+func recordApiKeyUsage(claims apikey.Claims, r *http.Request) {
+	// Repository
+	apiKeyRepo := apikey.ApiKeyRepository{}
+	apiKeyRepo.Init(claims.OrganisationId)
+
+	err := apiKeyRepo.RecordUsage(claims.Id, getClientIp(r))
+	if err != nil {
+		log.Printf("Failed to record usage of api key: %v: %v", claims.Id, err)
+	}
+}
+
+// getClientIp Returns the ip address of the client, honouring the forwarded header set by proxies
+func getClientIp(r *http.Request) string {
+	forwardedFor := r.Header.Get("X-Forwarded-For")
+	if len(strings.TrimSpace(forwardedFor)) > 0 {
+		return strings.TrimSpace(strings.Split(forwardedFor, ",")[0])
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
 func performAPIKeyAuthentication(claims apikey.Claims, tag string, w http.ResponseWriter, r *http.Request) {
 
 	t := token.AccessToken{}
+	token.SetOrganisationId(r, claims.OrganisationId)
 
 	// Check if individualId is present in request header for service tag
 	// If present validate user
@@ -107,6 +135,7 @@ func ValidateAPIKeyAndIndividualId() Middleware {
 					error_handler.Exit(http.StatusBadRequest, m)
 				}
 				performAPIKeyAuthentication(claims, tag, w, r)
+				recordApiKeyUsage(claims, r)
 			}
 
 			// Call the next middleware/handler in chain
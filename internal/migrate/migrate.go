@@ -3,6 +3,7 @@ package migrate
 import (
 	"context"
 	"fmt"
+	"net/textproto"
 	"strings"
 	"time"
 
@@ -35,6 +36,7 @@ func Migrate() {
 	migrateTimestampInApiKeyCollection()
 	migrateOrganisationIdInIDPCollection()
 	migrateExpiryTimestampInApiKeyCollection()
+	migrateApiKeyToKeyHashInApiKeyCollection()
 	migrateUnusedFieldsFromOrganistaionColloction()
 	migrateIsOnboardedFromIDPInindividualCollection()
 	migrateConsentRecordIdAndIndividualIdInConsentHistoryCollection()
@@ -56,6 +58,7 @@ func Migrate() {
 	migrateIdToStringInSignaturesCollection()
 	migrateIdToStringInRevisionsCollection()
 	migrateSchemaNameAndAuthorizedByOtherInRevisionCollection()
+	migrateRedactCustomHeadersInWebhookDeliveriesCollection()
 }
 
 func migrateThirdPartyDataSharingToTrueInPolicyCollection() {
@@ -214,6 +217,41 @@ func migrateExpiryTimestampInApiKeyCollection() {
 
 }
 
+func migrateApiKeyToKeyHashInApiKeyCollection() {
+	apiKeyCollection := apikey.Collection()
+
+	var results []bson.M
+
+	cursor, err := apiKeyCollection.Find(context.TODO(), bson.M{"apikey": bson.M{"$exists": true}})
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	defer cursor.Close(context.TODO())
+
+	if err := cursor.All(context.TODO(), &results); err != nil {
+		fmt.Println(err)
+	}
+
+	for _, apiKey := range results {
+		key, ok := apiKey["apikey"].(string)
+		if !ok {
+			continue
+		}
+
+		filter := bson.M{"_id": apiKey["_id"]}
+		update := bson.M{
+			"$set":   bson.M{"keyhash": apikey.Hash(key)},
+			"$unset": bson.M{"apikey": 1},
+		}
+
+		_, err = apiKeyCollection.UpdateOne(context.TODO(), filter, update)
+		if err != nil {
+			fmt.Println(err)
+		}
+	}
+}
+
 func migrateUnusedFieldsFromOrganistaionColloction() {
 
 	orgCollection := org.Collection()
@@ -1071,3 +1109,36 @@ func migrateSchemaNameAndAuthorizedByOtherInRevisionCollection() {
 
 	}
 }
+
+func migrateRedactCustomHeadersInWebhookDeliveriesCollection() {
+	var webhooks []webhook.Webhook
+
+	cursor, err := webhook.WebhookCollection().Find(context.TODO(), bson.M{"headers": bson.M{"$gt": bson.M{}}})
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	defer cursor.Close(context.TODO())
+
+	if err := cursor.All(context.TODO(), &webhooks); err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	for _, w := range webhooks {
+		for name := range w.Headers {
+			if strings.ContainsAny(name, ".$") {
+				continue
+			}
+			// Header names are stored canonicalised in the delivery records
+			field := "requestheaders." + textproto.CanonicalMIMEHeaderKey(name)
+
+			filter := bson.M{"webhookid": w.ID, field: bson.M{"$exists": true}}
+			update := bson.M{"$set": bson.M{field: []string{webhook.RedactedHeaderValue}}}
+			_, err := webhook.WebhookDeliveryCollection().UpdateMany(context.TODO(), filter, update)
+			if err != nil {
+				fmt.Println(err)
+			}
+		}
+	}
+}
@@ -152,6 +152,20 @@ func DeleteAdminUsers(organizationID string, admin Admin) (Organization, error)
 	return o, err
 }
 
+// UpdateAdminRole Updates the role of an admin user of organization
+func UpdateAdminRole(organizationID string, userID string, roleID int) (Organization, error) {
+
+	filter := bson.M{"_id": organizationID, "admins.userid": userID}
+	update := bson.M{"$set": bson.M{"admins.$.roleid": roleID}}
+
+	_, err := Collection().UpdateOne(context.TODO(), filter, update)
+	if err != nil {
+		return Organization{}, err
+	}
+	o, err := Get(organizationID)
+	return o, err
+}
+
 // UpdateOrganizationsOrgType Updates the embedded organization type snippet of all Organization
 func UpdateOrganizationsOrgType(oType orgtype.OrgType) error {
 
@@ -246,6 +260,33 @@ func GetName(organizationID string) (string, error) {
 	return result.Name, err
 }
 
+// GetAll Gets all organizations
+func GetAll() ([]Organization, error) {
+
+	var results []Organization
+
+	options := options.Find().SetSort(bson.D{{Key: "name", Value: 1}})
+	cursor, err := Collection().Find(context.TODO(), bson.M{}, options)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.TODO())
+
+	if err := cursor.All(context.TODO(), &results); err != nil {
+		return nil, err
+	}
+
+	return results, err
+}
+
+// Delete Deletes the organization by ID
+func Delete(organizationID string) error {
+
+	_, err := Collection().DeleteOne(context.TODO(), bson.M{"_id": organizationID})
+
+	return err
+}
+
 // GetOrganizationsCount Get organizations count
 func GetOrganizationsCount() (int64, error) {
 	count, err := Collection().CountDocuments(context.TODO(), bson.D{})
@@ -0,0 +1,149 @@
+package outbox
+
+import (
+	"context"
+	"time"
+
+	daRecord "github.com/bb-consent/api/internal/dataagreement_record"
+	"github.com/bb-consent/api/internal/database"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Outbox message status const
+const (
+	MessageStatusPending   = "pending"
+	MessageStatusPublished = "published"
+	MessageStatusFailed    = "failed"
+)
+
+// Message Consent event written in the same transaction as the consent
+// record, the relay publishes it once the transaction is committed
+type Message struct {
+	Id                   string                       `json:"id" bson:"_id,omitempty"`
+	OrganisationId       string                       `json:"organisationId"`
+	IndividualId         string                       `json:"individualId"`
+	EventType            string                       `json:"eventType"`
+	ConsentRecord        daRecord.DataAgreementRecord `json:"consentRecord"`
+	Status               string                       `json:"status"`
+	Attempts             int                          `json:"attempts"`
+	LastError            string                       `json:"lastError"`
+	TimeStamp            string                       `json:"timestamp"`
+	NextAttemptTimeStamp string                       `json:"nextAttemptTimestamp"`
+	LockedUntilTimeStamp string                       `json:"lockedUntilTimestamp"`
+	PublishedTimeStamp   string                       `json:"publishedTimestamp"`
+}
+
+func Collection() *mongo.Collection {
+	return database.DB.Client.Database(database.DB.Name).Collection("outbox")
+}
+
+// AddConsentRecordEvent Adds the consent record event to the outbox, context
+// should be the one of the transaction saving the consent record
+func AddConsentRecordEvent(ctx context.Context, consentRecord daRecord.DataAgreementRecord, organisationId string, eventType string) (Message, error) {
+	now := time.Now().UTC().Format("2006-01-02T15:04:05Z")
+
+	// Object IDs increase with time, messages are published in the order of IDs
+	message := Message{
+		Id:                   primitive.NewObjectID().Hex(),
+		OrganisationId:       organisationId,
+		IndividualId:         consentRecord.IndividualId,
+		EventType:            eventType,
+		ConsentRecord:        consentRecord,
+		Status:               MessageStatusPending,
+		TimeStamp:            now,
+		NextAttemptTimeStamp: now,
+	}
+
+	_, err := Collection().InsertOne(ctx, &message)
+	return message, err
+}
+
+// listDue Lists the pending messages which are due to be published, in the
+// order they were added. Messages waiting for a retry aren't listed, so they
+// don't hold back the messages added after them.
+func listDue(limit int64) ([]Message, error) {
+	results := []Message{}
+
+	filter := bson.M{
+		"status":               MessageStatusPending,
+		"nextattempttimestamp": bson.M{"$lte": time.Now().UTC().Format("2006-01-02T15:04:05Z")},
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}).SetLimit(limit)
+	cursor, err := Collection().Find(context.TODO(), filter, opts)
+	if err != nil {
+		return results, err
+	}
+	defer cursor.Close(context.TODO())
+
+	if err := cursor.All(context.TODO(), &results); err != nil {
+		return results, err
+	}
+	return results, nil
+}
+
+// hasEarlierPending Checks if a message of the individual added before the
+// message is yet to be published
+func hasEarlierPending(message Message) (bool, error) {
+	filter := bson.M{
+		"individualid": message.IndividualId,
+		"status":       MessageStatusPending,
+		"_id":          bson.M{"$lt": message.Id},
+	}
+	count, err := Collection().CountDocuments(context.TODO(), filter, options.Count().SetLimit(1))
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// claim Locks the pending message so that it is published by a single relay
+// across replicas, returns mongo.ErrNoDocuments if it is locked or published
+func claim(messageId string, lockedUntil time.Time) (Message, error) {
+	var result Message
+
+	nowTimestamp := time.Now().UTC().Format("2006-01-02T15:04:05Z")
+	filter := bson.M{
+		"_id":    messageId,
+		"status": MessageStatusPending,
+		"$or": []bson.M{
+			{"lockeduntiltimestamp": ""},
+			{"lockeduntiltimestamp": bson.M{"$lte": nowTimestamp}},
+		},
+	}
+	update := bson.M{"$set": bson.M{"lockeduntiltimestamp": lockedUntil.UTC().Format("2006-01-02T15:04:05Z")}}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	err := Collection().FindOneAndUpdate(context.TODO(), filter, update, opts).Decode(&result)
+	return result, err
+}
+
+// update Updates the outbox message
+func update(message Message) error {
+	_, err := Collection().ReplaceOne(context.TODO(), bson.M{"_id": message.Id}, message)
+	return err
+}
+
+// deletePublishedBefore Deletes the messages published before the timestamp
+func deletePublishedBefore(timestamp time.Time) (int64, error) {
+	filter := bson.M{
+		"status":             MessageStatusPublished,
+		"publishedtimestamp": bson.M{"$lte": timestamp.UTC().Format("2006-01-02T15:04:05Z")},
+	}
+	result, err := Collection().DeleteMany(context.TODO(), filter)
+	if err != nil {
+		return 0, err
+	}
+	return result.DeletedCount, nil
+}
+
+// DeleteByConsentRecordIdsWithContext Deletes the messages of the consent records, context is used for transactions
+func DeleteByConsentRecordIdsWithContext(ctx context.Context, organisationId string, consentRecordIds []string) (int64, error) {
+	result, err := Collection().DeleteMany(ctx, bson.M{"organisationid": organisationId, "consentrecord._id": bson.M{"$in": consentRecordIds}})
+	if err != nil {
+		return 0, err
+	}
+	return result.DeletedCount, nil
+}
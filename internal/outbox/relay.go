@@ -0,0 +1,169 @@
+package outbox
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"github.com/bb-consent/api/internal/webhook"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const (
+	// Interval at which the outbox is polled for messages added by other replicas
+	relayPollInterval = 5 * time.Second
+
+	// Time for which the relay holds a claimed message
+	relayLockDuration = 5 * time.Minute
+
+	// Number of pending messages read in a pass
+	relayBatchSize = 100
+
+	// Delay before publishing a message again after failure, doubled for every attempt
+	relayInitialBackoff = 10 * time.Second
+	relayMaxBackoff     = 30 * time.Minute
+
+	// Number of attempts after which a message is marked as failed, so the
+	// later messages of the individual are published
+	relayMaxAttempts = 20
+
+	// Published messages are kept for the retention period and cleaned up
+	// at the interval
+	publishedRetention       = 7 * 24 * time.Hour
+	publishedCleanupInterval = time.Hour
+)
+
+// relayNotifications Wakes up the relay when messages are committed
+var relayNotifications = make(chan struct{}, 1)
+
+var startRelayOnce sync.Once
+
+// Notify Wakes up the relay, to be called after the transaction adding
+// messages is committed
+func Notify() {
+	select {
+	case relayNotifications <- struct{}{}:
+	default:
+	}
+}
+
+// getBackoff Returns the delay before publishing the message again
+func getBackoff(attempts int) time.Duration {
+	backoff := relayInitialBackoff
+	for i := 1; i < attempts && backoff < relayMaxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > relayMaxBackoff {
+		backoff = relayMaxBackoff
+	}
+	return backoff
+}
+
+// publish Triggers the webhooks and event sinks for the message, message ID
+// is the event ID so publishing again doesn't duplicate the event
+func publish(message Message) error {
+	return webhook.TriggerConsentWebhookEvent(message.Id, message.ConsentRecord, message.OrganisationId, message.EventType)
+}
+
+// relayPendingMessages Publishes the pending messages which are due.
+// Messages of an individual are published in order, so a message is skipped
+// while an earlier message of the individual is pending.
+func relayPendingMessages() {
+	messages, err := listDue(relayBatchSize)
+	if err != nil {
+		log.Printf("Failed to list pending outbox messages: %v", err)
+		return
+	}
+
+	blockedIndividuals := map[string]bool{}
+	for _, message := range messages {
+		if blockedIndividuals[message.IndividualId] {
+			continue
+		}
+
+		blocked, err := hasEarlierPending(message)
+		if err != nil {
+			log.Printf("Failed to check earlier outbox messages of message:%v err:%v", message.Id, err)
+		}
+		if err != nil || blocked {
+			blockedIndividuals[message.IndividualId] = true
+			continue
+		}
+
+		// Message is being published by another replica
+		claimed, err := claim(message.Id, time.Now().UTC().Add(relayLockDuration))
+		if err != nil {
+			if err != mongo.ErrNoDocuments {
+				log.Printf("Failed to claim outbox message: %v", err)
+			}
+			blockedIndividuals[message.IndividualId] = true
+			continue
+		}
+		message = claimed
+
+		message.Attempts++
+		message.LockedUntilTimeStamp = ""
+		err = publish(message)
+		if err != nil {
+			log.Printf("Failed to publish outbox message:%v for event:%v, org:%v err:%v", message.Id, message.EventType, message.OrganisationId, err)
+			message.LastError = err.Error()
+			if message.Attempts >= relayMaxAttempts {
+				message.Status = MessageStatusFailed
+			} else {
+				message.NextAttemptTimeStamp = time.Now().UTC().Add(getBackoff(message.Attempts)).Format("2006-01-02T15:04:05Z")
+				blockedIndividuals[message.IndividualId] = true
+			}
+		} else {
+			message.Status = MessageStatusPublished
+			message.LastError = ""
+			message.PublishedTimeStamp = time.Now().UTC().Format("2006-01-02T15:04:05Z")
+		}
+
+		// Message is published again after the lock expires if it isn't saved
+		if err := update(message); err != nil {
+			log.Printf("Failed to update outbox message:%v err:%v", message.Id, err)
+			blockedIndividuals[message.IndividualId] = true
+		}
+	}
+}
+
+// cleanupPublishedMessages Deletes the messages published before the retention period
+func cleanupPublishedMessages() {
+	count, err := deletePublishedBefore(time.Now().Add(-publishedRetention))
+	if err != nil {
+		log.Printf("Failed to delete published outbox messages: %v", err)
+		return
+	}
+	if count > 0 {
+		log.Printf("Deleted %v published outbox messages", count)
+	}
+}
+
+// relay Publishes messages when woken up or at poll interval
+func relay() {
+	ticker := time.NewTicker(relayPollInterval)
+	defer ticker.Stop()
+
+	var lastCleanup time.Time
+	for {
+		relayPendingMessages()
+
+		if time.Since(lastCleanup) >= publishedCleanupInterval {
+			cleanupPublishedMessages()
+			lastCleanup = time.Now()
+		}
+
+		select {
+		case <-relayNotifications:
+		case <-ticker.C:
+		}
+	}
+}
+
+// StartRelay Starts the relay publishing consent events from the outbox
+func StartRelay() {
+	startRelayOnce.Do(func() {
+		go relay()
+		log.Println("Started outbox relay")
+	})
+}
@@ -1,13 +1,67 @@
 package rbac
 
-import "github.com/casbin/casbin/v2/model"
+import (
+	"strings"
+
+	"github.com/casbin/casbin/v2/model"
+)
 
 // RBAC User Roles
 const (
 	ROLE_USER  string = "user"
 	ROLE_ADMIN string = "organisation_admin"
+
+	ROLE_DPO             string = "dpo"
+	ROLE_DEVELOPER       string = "developer"
+	ROLE_AUDITOR         string = "auditor"
+	ROLE_READ_ONLY_ADMIN string = "read_only_admin"
+
+	ROLE_PLATFORM_OPERATOR string = "platform_operator"
+)
+
+// API key scopes
+const (
+	SCOPE_SERVICE string = "service"
+	SCOPE_AUDIT   string = "audit"
+	SCOPE_CONFIG  string = "config"
+	SCOPE_ONBOARD string = "onboard"
+
+	SCOPE_POLICIES_READ             string = "policies:read"
+	SCOPE_POLICIES_WRITE            string = "policies:write"
+	SCOPE_DATA_AGREEMENTS_READ      string = "data-agreements:read"
+	SCOPE_DATA_AGREEMENTS_WRITE     string = "data-agreements:write"
+	SCOPE_CONSENT_RECORDS_READ      string = "consent-records:read"
+	SCOPE_CONSENT_RECORDS_WRITE     string = "consent-records:write"
+	SCOPE_INDIVIDUALS_READ          string = "individuals:read"
+	SCOPE_INDIVIDUALS_WRITE         string = "individuals:write"
+	SCOPE_WEBHOOKS_MANAGE           string = "webhooks:manage"
+	SCOPE_IDENTITY_PROVIDERS_MANAGE string = "identity-providers:manage"
+	SCOPE_API_KEYS_MANAGE           string = "api-keys:manage"
+	SCOPE_AUDIT_LOGS_READ           string = "audit-logs:read"
 )
 
+// GetApiKeyScopes Returns the scopes that can be granted to an api key
+func GetApiKeyScopes() []string {
+	return []string{
+		SCOPE_SERVICE,
+		SCOPE_AUDIT,
+		SCOPE_CONFIG,
+		SCOPE_ONBOARD,
+		SCOPE_POLICIES_READ,
+		SCOPE_POLICIES_WRITE,
+		SCOPE_DATA_AGREEMENTS_READ,
+		SCOPE_DATA_AGREEMENTS_WRITE,
+		SCOPE_CONSENT_RECORDS_READ,
+		SCOPE_CONSENT_RECORDS_WRITE,
+		SCOPE_INDIVIDUALS_READ,
+		SCOPE_INDIVIDUALS_WRITE,
+		SCOPE_WEBHOOKS_MANAGE,
+		SCOPE_IDENTITY_PROVIDERS_MANAGE,
+		SCOPE_API_KEYS_MANAGE,
+		SCOPE_AUDIT_LOGS_READ,
+	}
+}
+
 // GetRbacPolicies
 func GetRbacPolicies(testMode bool) [][]string {
 
@@ -28,22 +82,39 @@ func GetRbacPolicies(testMode bool) [][]string {
 		{"organisation_admin", "/config/data-agreements/data-attributes", "GET"},
 		{"organisation_admin", "/config/webhooks/event-types", "GET"},
 		{"organisation_admin", "/config/webhooks/payload/content-types", "GET"},
+		{"organisation_admin", "/config/webhooks/health", "GET"},
 		{"organisation_admin", "/config/webhooks", "GET"},
 		{"organisation_admin", "/config/webhook", "POST"},
 		{"organisation_admin", "/config/webhook/{webhookId}", "(GET)|(PUT)|(DELETE)"},
 		{"organisation_admin", "/config/webhook/{webhookId}/ping", "POST"},
+		{"organisation_admin", "/config/webhook/{webhookId}/secret/rotate", "POST"},
 		{"organisation_admin", "/config/webhooks/{webhookId}/deliveries", "GET"},
 		{"organisation_admin", "/config/webhooks/{webhookId}/delivery/{deliveryId}", "GET"},
 		{"organisation_admin", "/config/webhooks/{webhookId}/delivery/{deliveryId}/redeliver", "POST"},
+		{"organisation_admin", "/config/webhooks/{webhookId}/deliveries/redeliver", "POST"},
+		{"organisation_admin", "/config/webhooks/{webhookId}/redelivery-jobs", "GET"},
+		{"organisation_admin", "/config/webhooks/{webhookId}/redelivery-job/{redeliveryJobId}", "GET"},
 		{"organisation_admin", "/config/idp/open-id", "POST"},
 		{"organisation_admin", "/config/idp/open-ids", "GET"},
 		{"organisation_admin", "/config/idp/open-id/{idpId}", "(GET)|(PUT)|(DELETE)"},
 		{"organisation_admin", "/config/individuals", "GET"},
 		{"organisation_admin", "/config/individual", "POST"},
 		{"organisation_admin", "/config/individual/{individualId}", "(GET)|(PUT)"},
+		{"organisation_admin", "/config/individual/{individualId}/guardian", "POST"},
+		{"organisation_admin", "/config/individual/{individualId}/guardians", "GET"},
+		{"organisation_admin", "/config/individual/{individualId}/guardian/{guardianId}", "(PUT)|(DELETE)"},
+		{"organisation_admin", "/config/individual/{individualId}/erasure", "POST"},
 		{"organisation_admin", "/config/admin/apikey", "POST"},
 		{"organisation_admin", "/config/admin/apikey/{apiKeyId}", "(PUT)|(DELETE)"},
 		{"organisation_admin", "/config/admin/apikeys", "GET"},
+		{"organisation_admin", "/config/admin/apikey/{apiKeyId}/revoke", "PUT"},
+		{"organisation_admin", "/config/admin/apikey/signing-secret/{signingSecretId}/retire", "PUT"},
+		{"organisation_admin", "/config/admin/apikey/signing-secrets", "GET"},
+		{"organisation_admin", "/config/admin/roles", "GET"},
+		{"organisation_admin", "/config/admin/user/{userId}/role", "PUT"},
+		{"organisation_admin", "/config/admin/user", "POST"},
+		{"organisation_admin", "/config/admin/users", "GET"},
+		{"organisation_admin", "/config/admin/user/{userId}", "DELETE"},
 		{"user", "/service/data-agreements", "GET"},
 		{"user", "/service/data-agreement/{dataAgreementId}", "GET"},
 		{"user", "/service/data-agreement/{dataAgreementId}/data-attributes", "GET"},
@@ -58,6 +129,8 @@ func GetRbacPolicies(testMode bool) [][]string {
 		{"user", "/service/individual/record/consent-record/{consentRecordId}/signature", "(POST)|(PUT)"},
 		{"user", "/service/individual/record/data-agreement/{dataAgreementId}/all", "GET"},
 		{"organisation_admin", "/audit/consent-records", "GET"},
+		{"organisation_admin", "/audit/events/stream", "GET"},
+		{"organisation_admin", "/audit/events/ws", "GET"},
 		{"organisation_admin", "/audit/consent-record/{consentRecordId}", "GET"},
 		{"organisation_admin", "/audit/data-agreements", "GET"},
 		{"organisation_admin", "/audit/data-agreement/{dataAgreementId}", "GET"},
@@ -76,6 +149,7 @@ func GetRbacPolicies(testMode bool) [][]string {
 		{"organisation_admin", "/onboard/status", "GET"},
 		{"user", "/onboard/password/reset", "PUT"},
 		{"user", "/service/individual/record/consent-record/history", "GET"},
+		{"user", "/service/individual/record/consent-record/renewal-required", "GET"},
 		{"user", "/service/idp/open-id", "GET"},
 		{"user", "/service/organisation", "GET"},
 		{"user", "/service/organisation/coverimage", "GET"},
@@ -84,9 +158,15 @@ func GetRbacPolicies(testMode bool) [][]string {
 		{"user", "/service/individual/{individualId}", "(GET)|(PUT)"},
 		{"user", "/service/image/{imageId}", "GET"},
 		{"user", "/service/individual/record", "DELETE"},
+		{"user", "/service/individual/erasure", "POST"},
+		{"user", "/service/individual/data-export", "POST"},
+		{"user", "/service/individual/data-export/{exportId}", "GET"},
+		{"user", "/service/individual/data-export/{exportId}/archive", "GET"},
 		{"user", "/onboard/logout", "POST"},
 		{"organisation_admin", "/onboard/logout", "POST"},
 		{"audit", "/audit/consent-records", "GET"},
+		{"audit", "/audit/events/stream", "GET"},
+		{"audit", "/audit/events/ws", "GET"},
 		{"audit", "/audit/consent-record/{consentRecordId}", "GET"},
 		{"audit", "/audit/data-agreements", "GET"},
 		{"audit", "/audit/data-agreement/{dataAgreementId}", "GET"},
@@ -105,22 +185,32 @@ func GetRbacPolicies(testMode bool) [][]string {
 		{"config", "/config/data-agreements/data-attributes", "GET"},
 		{"config", "/config/webhooks/event-types", "GET"},
 		{"config", "/config/webhooks/payload/content-types", "GET"},
+		{"config", "/config/webhooks/health", "GET"},
 		{"config", "/config/webhooks", "GET"},
 		{"config", "/config/webhook", "POST"},
 		{"config", "/config/webhook/{webhookId}", "(GET)|(PUT)|(DELETE)"},
 		{"config", "/config/webhook/{webhookId}/ping", "POST"},
+		{"config", "/config/webhook/{webhookId}/secret/rotate", "POST"},
 		{"config", "/config/webhooks/{webhookId}/deliveries", "GET"},
 		{"config", "/config/webhooks/{webhookId}/delivery/{deliveryId}", "GET"},
 		{"config", "/config/webhooks/{webhookId}/delivery/{deliveryId}/redeliver", "POST"},
+		{"config", "/config/webhooks/{webhookId}/deliveries/redeliver", "POST"},
+		{"config", "/config/webhooks/{webhookId}/redelivery-jobs", "GET"},
+		{"config", "/config/webhooks/{webhookId}/redelivery-job/{redeliveryJobId}", "GET"},
 		{"config", "/config/idp/open-id", "POST"},
 		{"config", "/config/idp/open-ids", "GET"},
 		{"config", "/config/idp/open-id/{idpId}", "(GET)|(PUT)|(DELETE)"},
 		{"config", "/config/individuals", "GET"},
 		{"config", "/config/individual", "POST"},
 		{"config", "/config/individual/{individualId}", "(GET)|(PUT)"},
+		{"config", "/config/individual/{individualId}/guardian", "POST"},
+		{"config", "/config/individual/{individualId}/guardians", "GET"},
+		{"config", "/config/individual/{individualId}/guardian/{guardianId}", "(PUT)|(DELETE)"},
+		{"config", "/config/individual/{individualId}/erasure", "POST"},
 		{"config", "/config/admin/apikey", "POST"},
 		{"config", "/config/admin/apikey/{apiKeyId}", "(PUT)|(DELETE)"},
 		{"config", "/config/admin/apikeys", "GET"},
+		{"config", "/config/admin/apikey/{apiKeyId}/revoke", "PUT"},
 		{"service", "/service/data-agreements", "GET"},
 		{"service", "/service/data-agreement/{dataAgreementId}", "GET"},
 		{"service", "/service/data-agreement/{dataAgreementId}/data-attributes", "GET"},
@@ -135,6 +225,7 @@ func GetRbacPolicies(testMode bool) [][]string {
 		{"service", "/service/individual/record/consent-record/{consentRecordId}/signature", "(POST)|(PUT)"},
 		{"service", "/service/individual/record/data-agreement/{dataAgreementId}/all", "GET"},
 		{"service", "/service/individual/record/consent-record/history", "GET"},
+		{"service", "/service/individual/record/consent-record/renewal-required", "GET"},
 		{"service", "/service/idp/open-id", "GET"},
 		{"service", "/service/organisation", "GET"},
 		{"service", "/service/organisation/coverimage", "GET"},
@@ -144,6 +235,10 @@ func GetRbacPolicies(testMode bool) [][]string {
 		{"service", "/service/individual/{individualId}", "(GET)|(PUT)"},
 		{"service", "/service/image/{imageId}", "GET"},
 		{"service", "/service/individual/record", "DELETE"},
+		{"service", "/service/individual/erasure", "POST"},
+		{"service", "/service/individual/data-export", "POST"},
+		{"service", "/service/individual/data-export/{exportId}", "GET"},
+		{"service", "/service/individual/data-export/{exportId}/archive", "GET"},
 		{"onboard", "/onboard/organisation", "(GET)|(PUT)"},
 		{"onboard", "/onboard/organisation/coverimage", "(GET)|(POST)"},
 		{"onboard", "/onboard/organisation/logoimage", "(GET)|(POST)"},
@@ -155,8 +250,90 @@ func GetRbacPolicies(testMode bool) [][]string {
 		{"onboard", "/onboard/logout", "POST"},
 		{"config", "/config/logs/purge", "DELETE"},
 		{"organisation_admin", "/config/logs/purge", "DELETE"},
+		{"platform_operator", "/platform/tenant", "POST"},
+		{"platform_operator", "/platform/tenants", "GET"},
+		{"platform_operator", "/platform/tenant/{organizationId}", "(GET)|(DELETE)"},
+		{"platform_operator", "/platform/tenant/{organizationId}/disable", "PUT"},
+		{"policies:read", "/config/policy/{policyId}", "GET"},
+		{"policies:read", "/config/policy/{policyId}/revisions", "GET"},
+		{"policies:read", "/config/policies", "GET"},
+		{"policies:read", "/service/policy/{policyId}", "GET"},
+		{"policies:write", "/config/policy", "POST"},
+		{"policies:write", "/config/policy/{policyId}", "(PUT)|(DELETE)"},
+		{"data-agreements:read", "/config/data-agreement/{dataAgreementId}", "GET"},
+		{"data-agreements:read", "/config/data-agreements", "GET"},
+		{"data-agreements:read", "/config/data-agreement/{dataAgreementId}/revisions", "GET"},
+		{"data-agreements:read", "/config/data-agreement/{dataAgreementId}/revision/{revisionId}", "GET"},
+		{"data-agreements:read", "/config/data-agreement/{dataAgreementId}/data-attributes", "GET"},
+		{"data-agreements:read", "/config/data-agreements/data-attributes", "GET"},
+		{"data-agreements:read", "/service/data-agreements", "GET"},
+		{"data-agreements:read", "/service/data-agreement/{dataAgreementId}", "GET"},
+		{"data-agreements:read", "/service/data-agreement/{dataAgreementId}/data-attributes", "GET"},
+		{"data-agreements:read", "/service/verification/data-agreements", "GET"},
+		{"data-agreements:read", "/audit/data-agreements", "GET"},
+		{"data-agreements:read", "/audit/data-agreement/{dataAgreementId}", "GET"},
+		{"data-agreements:write", "/config/data-agreement", "POST"},
+		{"data-agreements:write", "/config/data-agreement/{dataAgreementId}", "(PUT)|(DELETE)"},
+		{"data-agreements:write", "/config/data-agreements/data-attribute/{dataAttributeId}", "PUT"},
+		{"consent-records:read", "/service/verification/consent-record/{consentRecordId}", "GET"},
+		{"consent-records:read", "/service/verification/consent-records", "GET"},
+		{"consent-records:read", "/service/individual/record/data-agreement/{dataAgreementId}", "GET"},
+		{"consent-records:read", "/service/individual/record/consent-record", "GET"},
+		{"consent-records:read", "/service/individual/record/data-agreement/{dataAgreementId}/all", "GET"},
+		{"consent-records:read", "/service/individual/record/consent-record/history", "GET"},
+		{"consent-records:read", "/service/individual/record/consent-record/renewal-required", "GET"},
+		{"consent-records:read", "/service/individual/data-export/{exportId}", "GET"},
+		{"consent-records:read", "/service/individual/data-export/{exportId}/archive", "GET"},
+		{"consent-records:read", "/audit/consent-records", "GET"},
+		{"consent-records:read", "/audit/events/stream", "GET"},
+		{"consent-records:read", "/audit/events/ws", "GET"},
+		{"consent-records:read", "/audit/consent-record/{consentRecordId}", "GET"},
+		{"consent-records:write", "/service/individual/record/consent-record/draft", "POST"},
+		{"consent-records:write", "/service/individual/record/data-agreement/{dataAgreementId}", "POST"},
+		{"consent-records:write", "/service/individual/record/consent-record/{consentRecordId}", "PUT"},
+		{"consent-records:write", "/service/individual/record/consent-record", "POST"},
+		{"consent-records:write", "/service/individual/record/consent-record/{consentRecordId}/signature", "(POST)|(PUT)"},
+		{"consent-records:write", "/service/individual/record", "DELETE"},
+		{"consent-records:write", "/service/individual/data-export", "POST"},
+		{"individuals:read", "/config/individuals", "GET"},
+		{"individuals:read", "/config/individual/{individualId}", "GET"},
+		{"individuals:read", "/config/individual/{individualId}/guardians", "GET"},
+		{"individuals:read", "/service/individuals", "GET"},
+		{"individuals:read", "/service/individual/{individualId}", "GET"},
+		{"individuals:write", "/config/individual", "POST"},
+		{"individuals:write", "/config/individual/{individualId}", "PUT"},
+		{"individuals:write", "/config/individual/{individualId}/guardian", "POST"},
+		{"individuals:write", "/config/individual/{individualId}/guardian/{guardianId}", "(PUT)|(DELETE)"},
+		{"individuals:write", "/config/individual/{individualId}/erasure", "POST"},
+		{"individuals:write", "/service/individual", "POST"},
+		{"individuals:write", "/service/individual/{individualId}", "PUT"},
+		{"individuals:write", "/service/individual/erasure", "POST"},
+		{"webhooks:manage", "/config/webhooks/event-types", "GET"},
+		{"webhooks:manage", "/config/webhooks/payload/content-types", "GET"},
+		{"webhooks:manage", "/config/webhooks/health", "GET"},
+		{"webhooks:manage", "/config/webhooks", "GET"},
+		{"webhooks:manage", "/config/webhook", "POST"},
+		{"webhooks:manage", "/config/webhook/{webhookId}", "(GET)|(PUT)|(DELETE)"},
+		{"webhooks:manage", "/config/webhook/{webhookId}/ping", "POST"},
+		{"webhooks:manage", "/config/webhook/{webhookId}/secret/rotate", "POST"},
+		{"webhooks:manage", "/config/webhooks/{webhookId}/deliveries", "GET"},
+		{"webhooks:manage", "/config/webhooks/{webhookId}/delivery/{deliveryId}", "GET"},
+		{"webhooks:manage", "/config/webhooks/{webhookId}/delivery/{deliveryId}/redeliver", "POST"},
+		{"webhooks:manage", "/config/webhooks/{webhookId}/deliveries/redeliver", "POST"},
+		{"webhooks:manage", "/config/webhooks/{webhookId}/redelivery-jobs", "GET"},
+		{"webhooks:manage", "/config/webhooks/{webhookId}/redelivery-job/{redeliveryJobId}", "GET"},
+		{"identity-providers:manage", "/config/idp/open-id", "POST"},
+		{"identity-providers:manage", "/config/idp/open-ids", "GET"},
+		{"identity-providers:manage", "/config/idp/open-id/{idpId}", "(GET)|(PUT)|(DELETE)"},
+		{"api-keys:manage", "/config/admin/apikey", "POST"},
+		{"api-keys:manage", "/config/admin/apikey/{apiKeyId}", "(PUT)|(DELETE)"},
+		{"api-keys:manage", "/config/admin/apikeys", "GET"},
+		{"api-keys:manage", "/config/admin/apikey/{apiKeyId}/revoke", "PUT"},
+		{"audit-logs:read", "/audit/admin/logs", "GET"},
 	}
 
+	policies = append(policies, getOrganisationRolePolicies(policies)...)
+
 	for _, policy := range policies {
 		if testMode {
 			policy[1] = policy[1] + "/" // suffix with '/'
@@ -168,6 +345,100 @@ func GetRbacPolicies(testMode bool) [][]string {
 	return policies
 }
 
+// GetRbacRoleForOrgRole Returns the rbac role for the organisation role assigned to the user
+func GetRbacRoleForOrgRole(orgRole string) string {
+	switch orgRole {
+	case "Admin":
+		return ROLE_ADMIN
+	case "Dpo":
+		return ROLE_DPO
+	case "Developer":
+		return ROLE_DEVELOPER
+	case "Auditor":
+		return ROLE_AUDITOR
+	case "ReadOnlyAdmin":
+		return ROLE_READ_ONLY_ADMIN
+	default:
+		return ""
+	}
+}
+
+// isOnboardRouteForOrganisationRole Routes every organisation role needs for
+// managing its own profile and signing in to the admin dashboard
+func isOnboardRouteForOrganisationRole(path string, method string) bool {
+	switch path {
+	case "/onboard/admin", "/onboard/admin/avatarimage", "/onboard/password/reset", "/onboard/logout":
+		return true
+	}
+	return strings.HasPrefix(path, "/onboard/") && method == "GET"
+}
+
+// organisationRoleAccess Decides which of the organisation admin routes are granted to the other organisation roles
+var organisationRoleAccess = map[string]func(path string, method string) bool{
+	// Webhook signing secrets are redacted for read only admins
+	ROLE_READ_ONLY_ADMIN: func(path string, method string) bool {
+		return method == "GET" || isOnboardRouteForOrganisationRole(path, method)
+	},
+	ROLE_AUDITOR: func(path string, method string) bool {
+		return (strings.HasPrefix(path, "/audit/") && method == "GET") ||
+			isOnboardRouteForOrganisationRole(path, method)
+	},
+	ROLE_DPO: func(path string, method string) bool {
+		return strings.HasPrefix(path, "/config/policy") ||
+			strings.HasPrefix(path, "/config/policies") ||
+			strings.HasPrefix(path, "/config/data-agreement") ||
+			(strings.HasPrefix(path, "/config/individual") && method == "GET") ||
+			(strings.HasPrefix(path, "/config/privacy-dashboard") && method == "GET") ||
+			(strings.HasPrefix(path, "/audit/") && method == "GET") ||
+			isOnboardRouteForOrganisationRole(path, method)
+	},
+	// Developers can only list api keys, creating or changing them would
+	// grant scopes beyond the role e.g. changing data agreements
+	ROLE_DEVELOPER: func(path string, method string) bool {
+		return strings.HasPrefix(path, "/config/webhook") ||
+			(strings.HasPrefix(path, "/config/admin/apikey") && method == "GET") ||
+			strings.HasPrefix(path, "/config/idp/") ||
+			(strings.HasPrefix(path, "/config/policy") && method == "GET") ||
+			(strings.HasPrefix(path, "/config/policies") && method == "GET") ||
+			(strings.HasPrefix(path, "/config/data-agreement") && method == "GET") ||
+			isOnboardRouteForOrganisationRole(path, method)
+	},
+}
+
+// getMethods Returns the methods in a policy method regex e.g. (GET)|(PUT)
+func getMethods(methodRegex string) []string {
+	var methods []string
+	for _, method := range strings.Split(methodRegex, "|") {
+		methods = append(methods, strings.Trim(method, "()"))
+	}
+	return methods
+}
+
+// getOrganisationRolePolicies Generates policies for the organisation roles
+// from the routes granted to the organisation admin
+func getOrganisationRolePolicies(policies [][]string) [][]string {
+	var rolePolicies [][]string
+	for _, role := range []string{ROLE_READ_ONLY_ADMIN, ROLE_AUDITOR, ROLE_DPO, ROLE_DEVELOPER} {
+		hasAccess := organisationRoleAccess[role]
+		for _, policy := range policies {
+			if policy[0] != ROLE_ADMIN {
+				continue
+			}
+
+			var methods []string
+			for _, method := range getMethods(policy[2]) {
+				if hasAccess(policy[1], method) {
+					methods = append(methods, "("+method+")")
+				}
+			}
+			if len(methods) > 0 {
+				rolePolicies = append(rolePolicies, []string{role, policy[1], strings.Join(methods, "|")})
+			}
+		}
+	}
+	return rolePolicies
+}
+
 // CreateRbacModel
 func CreateRbacModel() model.Model {
 	// Initialize a new model.
@@ -15,8 +15,13 @@ func Collection() *mongo.Collection {
 
 // Add Adds the revision to the db
 func Add(revision Revision) (Revision, error) {
+	return AddWithContext(context.TODO(), revision)
+}
+
+// AddWithContext Adds the revision to the db, context is used for transactions
+func AddWithContext(ctx context.Context, revision Revision) (Revision, error) {
 
-	_, err := Collection().InsertOne(context.TODO(), revision)
+	_, err := Collection().InsertOne(ctx, revision)
 	if err != nil {
 		return Revision{}, err
 	}
@@ -92,3 +97,41 @@ func ListAllByObjectIdAndSchemaName(objectId string, schemaName string) ([]Revis
 
 	return results, err
 }
+
+// ListObjectIdsLastRevisedBefore Lists the ids of the objects whose latest
+// revision was made at or before the timestamp, objects without revisions
+// aren't listed
+func ListObjectIdsLastRevisedBefore(objectIds []string, schemaName string, timestamp string) ([]string, error) {
+	results := []string{}
+
+	pipeline := []bson.M{
+		{"$match": bson.M{"objectid": bson.M{"$in": objectIds}, "schemaname": schemaName}},
+		{"$group": bson.M{"_id": "$objectid", "timestamp": bson.M{"$max": "$timestamp"}}},
+		{"$match": bson.M{"timestamp": bson.M{"$lte": timestamp}}},
+	}
+	cursor, err := Collection().Aggregate(context.TODO(), pipeline)
+	if err != nil {
+		return results, err
+	}
+	defer cursor.Close(context.TODO())
+
+	var objects []struct {
+		Id string `bson:"_id"`
+	}
+	if err := cursor.All(context.TODO(), &objects); err != nil {
+		return results, err
+	}
+	for _, object := range objects {
+		results = append(results, object.Id)
+	}
+	return results, nil
+}
+
+// DeleteByObjectIdsWithContext Deletes the revisions of the objects, context is used for transactions
+func DeleteByObjectIdsWithContext(ctx context.Context, objectIds []string, schemaName string) (int64, error) {
+	result, err := Collection().DeleteMany(ctx, bson.M{"objectid": bson.M{"$in": objectIds}, "schemaname": schemaName})
+	if err != nil {
+		return 0, err
+	}
+	return result.DeletedCount, nil
+}
@@ -72,6 +72,20 @@ func (r *Revision) CreateRevision(objectData interface{}) error {
 	}
 	r.ObjectData = string(objectDataSerialised)
 
+	return r.createSerializedSnapshot()
+}
+
+// AuthorizeByIndividual Records the individual, for e.g. guardian, who
+// authorized the revision on behalf of another individual
+func (r *Revision) AuthorizeByIndividual(individualId string) error {
+	r.AuthorizedByIndividualId = individualId
+
+	// Serialised snapshot includes the individual authorizing the revision
+	return r.createSerializedSnapshot()
+}
+
+// createSerializedSnapshot Creates the serialized snapshot and hash of the revision
+func (r *Revision) createSerializedSnapshot() error {
 	var revisionForSerializedSnapshot RevisionForSerializedSnapshot
 	revisionForSerializedSnapshot.SchemaName = r.SchemaName
 	revisionForSerializedSnapshot.ObjectId = r.ObjectId
@@ -96,7 +110,6 @@ func (r *Revision) CreateRevision(objectData interface{}) error {
 	}
 
 	return nil
-
 }
 
 // UpdateRevision
@@ -14,8 +14,13 @@ func Collection() *mongo.Collection {
 
 // Add Adds the signature to the db
 func Add(signature Signature) (Signature, error) {
+	return AddWithContext(context.TODO(), signature)
+}
+
+// AddWithContext Adds the signature to the db, context is used for transactions
+func AddWithContext(ctx context.Context, signature Signature) (Signature, error) {
 
-	_, err := Collection().InsertOne(context.TODO(), signature)
+	_, err := Collection().InsertOne(ctx, signature)
 	if err != nil {
 		return Signature{}, err
 	}
@@ -36,13 +41,27 @@ func Get(signatureId string) (Signature, error) {
 
 // Update Updates the signature
 func Update(signature Signature) (Signature, error) {
+	return UpdateWithContext(context.TODO(), signature)
+}
+
+// UpdateWithContext Updates the signature, context is used for transactions
+func UpdateWithContext(ctx context.Context, signature Signature) (Signature, error) {
 
 	filter := bson.M{"_id": signature.Id}
 	update := bson.M{"$set": signature}
 
-	_, err := Collection().UpdateOne(context.TODO(), filter, update)
+	_, err := Collection().UpdateOne(ctx, filter, update)
 	if err != nil {
 		return signature, err
 	}
 	return signature, nil
 }
+
+// DeleteByIdsWithContext Deletes the signatures, context is used for transactions
+func DeleteByIdsWithContext(ctx context.Context, signatureIds []string) (int64, error) {
+	result, err := Collection().DeleteMany(ctx, bson.M{"_id": bson.M{"$in": signatureIds}})
+	if err != nil {
+		return 0, err
+	}
+	return result.DeletedCount, nil
+}
@@ -0,0 +1,238 @@
+package signingkey
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bb-consent/api/internal/database"
+	"github.com/bb-consent/api/internal/webhook_dispatcher"
+	"github.com/dgrijalva/jwt-go"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// SigningKey Key pair used for signing documents issued to individuals, for
+// e.g. erasure receipts and data export manifests. The id of the key is set
+// as `kid` in the header of the signed document and the public key is
+// published in the JWKS of the organisation, so the documents can be
+// verified without access to any secret.
+type SigningKey struct {
+	Id             string `json:"id" bson:"_id,omitempty"`
+	OrganisationId string `json:"-"`
+	PrivateKey     string `json:"-"` // PKCS #8 encoded EC P-256 private key, base64 and encrypted like webhook secrets
+	Timestamp      string `json:"timestamp"`
+}
+
+// PublicKey Public key of a signing key as a JWK
+type PublicKey struct {
+	Kty string `json:"kty"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+	Kid string `json:"kid"`
+	Alg string `json:"alg"`
+	Use string `json:"use"`
+}
+
+// JWKS Public keys of the signing keys of an organisation
+type JWKS struct {
+	Keys []PublicKey `json:"keys"`
+}
+
+var ErrInvalidSigningKey = errors.New("signing key is not an EC P-256 key")
+
+func Collection() *mongo.Collection {
+	return database.DB.Client.Database(database.DB.Name).Collection("signingKeys")
+}
+
+// createSigningKey Generates and adds a new signing key for the organisation
+func createSigningKey(organisationId string) (SigningKey, error) {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return SigningKey{}, err
+	}
+	encodedKey, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	if err != nil {
+		return SigningKey{}, err
+	}
+
+	encryptedKey, err := webhook_dispatcher.EncryptSecret(base64.StdEncoding.EncodeToString(encodedKey))
+	if err != nil {
+		return SigningKey{}, err
+	}
+
+	signingKey := SigningKey{
+		Id:             primitive.NewObjectID().Hex(),
+		OrganisationId: organisationId,
+		PrivateKey:     encryptedKey,
+		Timestamp:      time.Now().UTC().Format("2006-01-02T15:04:05Z"),
+	}
+
+	_, err = Collection().InsertOne(context.TODO(), signingKey)
+	if err != nil {
+		return SigningKey{}, err
+	}
+	return signingKey, nil
+}
+
+// getOrCreateCurrentSigningKey Gets the newest signing key of the
+// organisation, a signing key is created if there is none
+func getOrCreateCurrentSigningKey(organisationId string) (SigningKey, error) {
+	var result SigningKey
+
+	opts := options.FindOne().SetSort(bson.D{{Key: "timestamp", Value: -1}, {Key: "_id", Value: -1}})
+	err := Collection().FindOne(context.TODO(), bson.M{"organisationid": organisationId}, opts).Decode(&result)
+	if err == mongo.ErrNoDocuments {
+		return createSigningKey(organisationId)
+	}
+	return result, err
+}
+
+// decryptPrivateKey Decrypts the private key of the signing key, keys stored
+// before they were encrypted don't have the id of the encryption key and are
+// returned as is
+func (signingKey SigningKey) decryptPrivateKey() (string, error) {
+	if !strings.Contains(signingKey.PrivateKey, ":") {
+		return signingKey.PrivateKey, nil
+	}
+	return webhook_dispatcher.DecryptSecret(signingKey.PrivateKey)
+}
+
+// ecdsaPrivateKey Decodes the private key of the signing key
+func (signingKey SigningKey) ecdsaPrivateKey() (*ecdsa.PrivateKey, error) {
+	decryptedKey, err := signingKey.decryptPrivateKey()
+	if err != nil {
+		return nil, err
+	}
+	encodedKey, err := base64.StdEncoding.DecodeString(decryptedKey)
+	if err != nil {
+		return nil, err
+	}
+	key, err := x509.ParsePKCS8PrivateKey(encodedKey)
+	if err != nil {
+		return nil, err
+	}
+	privateKey, ok := key.(*ecdsa.PrivateKey)
+	if !ok || privateKey.Curve != elliptic.P256() {
+		return nil, ErrInvalidSigningKey
+	}
+	return privateKey, nil
+}
+
+// publicKey Returns the public key of the signing key as a JWK
+func (signingKey SigningKey) publicKey() (PublicKey, error) {
+	privateKey, err := signingKey.ecdsaPrivateKey()
+	if err != nil {
+		return PublicKey{}, err
+	}
+
+	// Coordinates are padded to the size of the curve as required by RFC 7518
+	x := make([]byte, 32)
+	y := make([]byte, 32)
+	privateKey.X.FillBytes(x)
+	privateKey.Y.FillBytes(y)
+
+	return PublicKey{
+		Kty: "EC",
+		Crv: "P-256",
+		X:   base64.RawURLEncoding.EncodeToString(x),
+		Y:   base64.RawURLEncoding.EncodeToString(y),
+		Kid: signingKey.Id,
+		Alg: jwt.SigningMethodES256.Alg(),
+		Use: "sig",
+	}, nil
+}
+
+// Sign Signs the claims as an ES256 JWT with the current signing key of the
+// organisation, the id of the key is set as `kid` in the header
+func Sign(organisationId string, claims jwt.Claims) (string, error) {
+	signingKey, err := getOrCreateCurrentSigningKey(organisationId)
+	if err != nil {
+		return "", err
+	}
+	privateKey, err := signingKey.ecdsaPrivateKey()
+	if err != nil {
+		return "", err
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodES256, claims)
+	token.Header["kid"] = signingKey.Id
+	return token.SignedString(privateKey)
+}
+
+// GetJWKS Gets the public keys of the signing keys of the organisation,
+// newest first
+func GetJWKS(organisationId string) (JWKS, error) {
+	jwks := JWKS{Keys: []PublicKey{}}
+
+	opts := options.Find().SetSort(bson.D{{Key: "timestamp", Value: -1}, {Key: "_id", Value: -1}})
+	cursor, err := Collection().Find(context.TODO(), bson.M{"organisationid": organisationId}, opts)
+	if err != nil {
+		return jwks, err
+	}
+	defer cursor.Close(context.TODO())
+
+	var signingKeys []SigningKey
+	if err := cursor.All(context.TODO(), &signingKeys); err != nil {
+		return jwks, err
+	}
+
+	for _, signingKey := range signingKeys {
+		publicKey, err := signingKey.publicKey()
+		if err != nil {
+			return jwks, err
+		}
+		jwks.Keys = append(jwks.Keys, publicKey)
+	}
+	return jwks, nil
+}
+
+// ReencryptPrivateKeys Encrypts the private keys of the signing keys which
+// aren't encrypted or are encrypted with a previous encryption key, so the
+// previous key can be removed from the configuration
+func ReencryptPrivateKeys() error {
+	var signingKeys []SigningKey
+
+	cursor, err := Collection().Find(context.TODO(), bson.M{})
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(context.TODO())
+
+	if err := cursor.All(context.TODO(), &signingKeys); err != nil {
+		return err
+	}
+
+	for _, signingKey := range signingKeys {
+		if webhook_dispatcher.IsEncryptedWithCurrentKey(signingKey.PrivateKey) {
+			continue
+		}
+
+		privateKey, err := signingKey.decryptPrivateKey()
+		if err != nil {
+			return fmt.Errorf("failed to decrypt private key of signing key %v: %v", signingKey.Id, err)
+		}
+		encryptedKey, err := webhook_dispatcher.EncryptSecret(privateKey)
+		if err != nil {
+			return err
+		}
+
+		filter := bson.M{"_id": signingKey.Id, "privatekey": signingKey.PrivateKey}
+		update := bson.M{"$set": bson.M{"privatekey": encryptedKey}}
+		_, err = Collection().UpdateOne(context.TODO(), filter, update)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package tenant
+
+import (
+	"log"
+
+	"github.com/bb-consent/api/internal/config"
+	"github.com/bb-consent/api/internal/fixture"
+	"github.com/bb-consent/api/internal/org"
+)
+
+// MultiTenantConfiguration If the application starts in multi tenant mode then create organisation type and
+// bootstrap the organisation from configuration if no organisation exists yet
+func MultiTenantConfiguration(config *config.Configuration) {
+
+	// Note: Organisations are not deleted or updated in multi tenant mode,
+	// the organisation in the configuration file is only used to bootstrap
+	// an empty deployment.
+
+	// Create organisation type
+	orgType := createOrganisationType(config)
+
+	count, err := org.GetOrganizationsCount()
+	if err != nil {
+		log.Println("failed to count organization")
+		panic(err)
+	}
+	if count > 0 {
+		return
+	}
+
+	// Create an organisation admin
+	organisationAdmin := createOrganisationAdmin(config)
+	organisationAdminId := organisationAdmin.ID
+
+	// Create organisation
+	createOrganisation(config, orgType, organisationAdminId)
+
+	// Load image assets for organisation
+	err = fixture.LoadImageAssetsForSingleTenantConfiguration()
+	if err != nil {
+		log.Println("Error occured while loading image assets for organisation")
+	}
+
+	// Create global policy
+	createGlobalPolicy(config, organisationAdminId)
+}
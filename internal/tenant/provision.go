@@ -0,0 +1,209 @@
+package tenant
+
+import (
+	"errors"
+	"log"
+	"strings"
+
+	"github.com/bb-consent/api/internal/common"
+	"github.com/bb-consent/api/internal/config"
+	"github.com/bb-consent/api/internal/fixture"
+	"github.com/bb-consent/api/internal/org"
+	"github.com/bb-consent/api/internal/orgtype"
+	"github.com/bb-consent/api/internal/policy"
+	"github.com/bb-consent/api/internal/user"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Configuration Stores the loaded configuration used as defaults while provisioning tenants
+var Configuration *config.Configuration
+
+// Init Initializes the tenant provisioning configuration
+func Init(config *config.Configuration) {
+	Configuration = config
+}
+
+// ErrNotMultiTenant is returned when tenants are provisioned outside multi tenant mode
+var ErrNotMultiTenant = errors.New("tenants can only be provisioned in multi-tenant application mode")
+
+// ErrTenantNotDisabled is returned when an enabled tenant is deleted
+var ErrTenantNotDisabled = errors.New("tenant must be disabled before it is deleted")
+
+// ErrAdminExists is returned when the admin email belongs to an existing user and reusing it isn't requested
+var ErrAdminExists = errors.New("user with the admin email already exists, reuse of the existing admin must be requested explicitly")
+
+// ErrAdminPasswordRequired is returned when the admin to be created has no password
+var ErrAdminPasswordRequired = errors.New("admin password is required for creating the admin")
+
+// Tenant Organisation provisioned in the deployment along with its admins
+type Tenant struct {
+	Organisation org.Organization `json:"organisation"`
+	Admins       []user.User      `json:"admins"`
+}
+
+func getOrCreateOrganisationType() (orgtype.OrgType, error) {
+	orgType, err := orgtype.GetFirstType()
+	if err == mongo.ErrNoDocuments {
+		log.Printf("Organization type doesn't exist, creating organization type.")
+		return orgtype.AddOrganizationType(Configuration.Policy)
+	}
+	return orgType, err
+}
+
+// getOrCreateOrganisationAdmin Creates the organisation admin, an existing
+// user with the admin email is used only when reusing it is requested, since
+// its password isn't changed
+func getOrCreateOrganisationAdmin(admin config.User, reuseExistingAdmin bool) (user.User, error) {
+	u, err := user.GetByEmail(admin.Username)
+	if err == nil {
+		if !reuseExistingAdmin {
+			return user.User{}, ErrAdminExists
+		}
+		log.Printf("Reusing existing user: %v as organisation admin.", admin.Username)
+		return u, nil
+	}
+	if err != mongo.ErrNoDocuments {
+		return user.User{}, err
+	}
+	if len(admin.Password) == 0 {
+		return user.User{}, ErrAdminPasswordRequired
+	}
+	log.Printf("Failed to get user: %v, creating new user.", admin.Username)
+	return user.RegisterUser(admin, Configuration.Iam)
+}
+
+func getTenantAdmins(o org.Organization) []user.User {
+	admins := []user.User{}
+	for _, admin := range o.Admins {
+		u, err := user.Get(admin.UserID)
+		if err != nil {
+			continue
+		}
+		admins = append(admins, u)
+	}
+	return admins
+}
+
+// CreateTenant Creates an organisation along with its admin, default images and global policy.
+// An existing user with the admin email is made the admin only if reuseExistingAdmin is set.
+func CreateTenant(organisation config.Organization, admin config.User, globalPolicy config.GlobalPolicy, reuseExistingAdmin bool) (Tenant, error) {
+	if Configuration.ApplicationMode != config.MultiTenant {
+		return Tenant{}, ErrNotMultiTenant
+	}
+
+	// Configuration for the tenant, defaults are taken from the loaded configuration
+	tenantConfig := *Configuration
+	tenantConfig.Organization = organisation
+	tenantConfig.User = admin
+	if len(strings.TrimSpace(globalPolicy.Name)) > 0 {
+		tenantConfig.Policy = globalPolicy
+	}
+
+	orgType, err := getOrCreateOrganisationType()
+	if err != nil {
+		return Tenant{}, err
+	}
+
+	// Create an organisation admin, both in IAM and db
+	organisationAdmin, err := getOrCreateOrganisationAdmin(admin, reuseExistingAdmin)
+	if err != nil {
+		return Tenant{}, err
+	}
+
+	o, err := org.AddOrganization(organisation, orgType.ID, organisationAdmin.ID)
+	if err != nil {
+		return Tenant{}, err
+	}
+
+	organisationAdmin, err = user.AddRole(organisationAdmin.ID, user.Role{RoleID: common.GetRoleID("Admin"), OrgID: o.ID})
+	if err != nil {
+		return Tenant{}, err
+	}
+
+	// Load image assets for organisation
+	err = fixture.LoadImageAssetsForOrganisation(o)
+	if err != nil {
+		log.Println("Error occured while loading image assets for organisation")
+	}
+
+	// Create global policy
+	createdPolicy, err := createDefaultPolicy(&tenantConfig, o, organisationAdmin.ID)
+	if err != nil {
+		return Tenant{}, err
+	}
+
+	// Repository
+	prepo := policy.PolicyRepository{}
+	prepo.Init(o.ID)
+
+	_, err = prepo.Add(createdPolicy)
+	if err != nil {
+		return Tenant{}, err
+	}
+
+	o, err = org.Get(o.ID)
+	if err != nil {
+		return Tenant{}, err
+	}
+
+	return Tenant{Organisation: o, Admins: []user.User{organisationAdmin}}, nil
+}
+
+// ListTenants Lists all organisations in the deployment
+func ListTenants() ([]Tenant, error) {
+	organisations, err := org.GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	tenants := []Tenant{}
+	for _, o := range organisations {
+		tenants = append(tenants, Tenant{Organisation: o, Admins: getTenantAdmins(o)})
+	}
+	return tenants, nil
+}
+
+// GetTenant Gets an organisation in the deployment
+func GetTenant(organisationId string) (Tenant, error) {
+	o, err := org.Get(organisationId)
+	if err != nil {
+		return Tenant{}, err
+	}
+	return Tenant{Organisation: o, Admins: getTenantAdmins(o)}, nil
+}
+
+// DisableTenant Disables an organisation, requests to the organisation are rejected afterwards
+func DisableTenant(organisationId string) (Tenant, error) {
+	o, err := org.SetEnabled(organisationId, false)
+	if err != nil {
+		return Tenant{}, err
+	}
+	return Tenant{Organisation: o, Admins: getTenantAdmins(o)}, nil
+}
+
+// DeleteTenant Deletes a disabled organisation and removes the organisation roles from its admins.
+// Records created by the organisation are retained.
+func DeleteTenant(organisationId string) (Tenant, error) {
+	o, err := org.Get(organisationId)
+	if err != nil {
+		return Tenant{}, err
+	}
+	if o.Enabled {
+		return Tenant{}, ErrTenantNotDisabled
+	}
+
+	admins := getTenantAdmins(o)
+	for _, admin := range admins {
+		_, err = user.RemoveRolesForOrganization(admin.ID, o.ID)
+		if err != nil {
+			return Tenant{}, err
+		}
+	}
+
+	err = org.Delete(o.ID)
+	if err != nil {
+		return Tenant{}, err
+	}
+
+	return Tenant{Organisation: o, Admins: admins}, nil
+}
@@ -10,6 +10,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/bb-consent/api/internal/apikey"
 	"github.com/bb-consent/api/internal/config"
 	"github.com/bb-consent/api/internal/iam"
 	jwt "github.com/dgrijalva/jwt-go"
@@ -113,6 +114,8 @@ const userIDKey = "userID"
 const rolesKey = "roles"
 const APIKey = "apiKey"
 const UserRoleKey = "role"
+const organisationIdKey = "organisationId"
+const apiKeyClaimsKey = "apiKeyClaims"
 
 // Set Set the token to context
 func Set(r *http.Request, token AccessToken) {
@@ -204,6 +207,19 @@ func SetUserToRequestContext(r *http.Request, userID string, userRole string) {
 
 }
 
+// SetOrganisationId Set organisation the authenticated user belongs to
+func SetOrganisationId(r *http.Request, organisationId string) {
+	context.Set(r, organisationIdKey, organisationId)
+}
+
+// GetOrganisationId Get organisation the authenticated user belongs to
+func GetOrganisationId(r *http.Request) string {
+	if context.Get(r, organisationIdKey) == nil {
+		return ""
+	}
+	return context.Get(r, organisationIdKey).(string)
+}
+
 // ParseTokenUnverified parses the token and returns the accessToken struct
 func ParseTokenUnverified(tokenString string) (AccessToken, error) {
 	accToken := AccessToken{}
@@ -221,3 +237,15 @@ func ParseTokenUnverified(tokenString string) (AccessToken, error) {
 
 	return accToken, nil
 }
+
+// SetApiKeyClaims Set claims of the api key the request is authenticated with
+func SetApiKeyClaims(r *http.Request, claims apikey.Claims) {
+	context.Set(r, apiKeyClaimsKey, claims)
+}
+
+// GetApiKeyClaims Get claims of the api key the request is authenticated
+// with, returns false if the request isn't authenticated with an api key
+func GetApiKeyClaims(r *http.Request) (apikey.Claims, bool) {
+	claims, ok := context.Get(r, apiKeyClaimsKey).(apikey.Claims)
+	return claims, ok
+}
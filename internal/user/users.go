@@ -187,6 +187,27 @@ func AddRole(userId string, role Role) (User, error) {
 	return u, err
 }
 
+// RemoveRolesForOrganization Remove all roles of the user for an organization
+func RemoveRolesForOrganization(userId string, orgId string) (User, error) {
+
+	_, err := Collection().UpdateOne(context.TODO(), bson.M{"_id": userId}, bson.M{"$pull": bson.M{"roles": bson.M{"orgid": orgId}}})
+	if err != nil {
+		return User{}, err
+	}
+	u, err := Get(userId)
+	return u, err
+}
+
+// SetRoleForOrganization Replaces the roles of the user for an organization with the given role
+func SetRoleForOrganization(userId string, role Role) (User, error) {
+
+	_, err := RemoveRolesForOrganization(userId, role.OrgID)
+	if err != nil {
+		return User{}, err
+	}
+	return AddRole(userId, role)
+}
+
 // UpdateOrganizationsSubscribedUsers Updates the embedded organization snippet for all users
 func UpdateOrganizationsSubscribedUsers(org org.Organization) error {
 	filter := bson.M{"orgs.orgid": org.ID}
@@ -2,9 +2,11 @@ package webhook
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/bb-consent/api/internal/common"
 	"github.com/bb-consent/api/internal/database"
+	"github.com/bb-consent/api/internal/webhook_dispatcher"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
@@ -12,36 +14,47 @@ import (
 
 // Webhook Defines the structure for an organisation webhook
 type Webhook struct {
-	ID                  string   `json:"id" bson:"_id,omitempty"`           // Webhook ID
-	OrganisationId      string   `json:"orgId" bson:"orgid"`                // Organisation ID
-	PayloadURL          string   `json:"payloadUrl" valid:"required"`       // Webhook payload URL
-	ContentType         string   `json:"contentType" valid:"required"`      // Webhook payload content type for e.g application/json
-	SubscribedEvents    []string `json:"subscribedEvents" valid:"required"` // Events subscribed for e.g. user.data.delete
-	Disabled            bool     `json:"disabled"`                          // Disabled or not
-	SecretKey           string   `json:"secretKey" valid:"required"`        // For calculating SHA256 HMAC to verify data integrity and authenticity
-	SkipSSLVerification bool     `json:"skipSslVerification"`               // Skip SSL certificate verification or not (expiry is checked)
-	TimeStamp           string   `json:"timestamp"`                         // UTC timestamp
-	IsDeleted           bool     `json:"-"`
+	ID                  string            `json:"id" bson:"_id,omitempty"`           // Webhook ID
+	OrganisationId      string            `json:"orgId" bson:"orgid"`                // Organisation ID
+	PayloadURL          string            `json:"payloadUrl" valid:"required"`       // Webhook payload URL
+	ContentType         string            `json:"contentType" valid:"required"`      // Webhook payload content type for e.g application/json
+	SubscribedEvents    []string          `json:"subscribedEvents" valid:"required"` // Events subscribed for e.g. user.data.delete
+	Disabled            bool              `json:"disabled"`                          // Disabled or not
+	SecretKey           string            `json:"secretKey" valid:"required"`        // For calculating SHA256 HMAC to verify data integrity and authenticity
+	SkipSSLVerification bool              `json:"skipSslVerification"`               // Skip SSL certificate verification or not (expiry is checked)
+	TimeStamp           string            `json:"timestamp"`                         // UTC timestamp
+	IsDeleted           bool              `json:"-"`
+	Filter              WebhookFilter     `json:"filter"`              // Restricts the events delivered to the webhook
+	PreviousSecrets     []WebhookSecret   `json:"previousSecrets"`     // Previous secrets which are used for signing until they expire
+	SignatureScheme     string            `json:"signatureScheme"`     // Scheme for signing payloads for e.g. igrant or standard-webhooks
+	ClientCertificate   string            `json:"clientCertificate"`   // PEM encoded client certificate for mutual TLS
+	ClientKey           string            `json:"clientKey,omitempty"` // PEM encoded private key of client certificate, stored encrypted
+	CACertificates      string            `json:"caCertificates"`      // PEM encoded CA certificates for verifying the endpoint
+	Headers             map[string]string `json:"headers"`             // Custom headers posted to webhook endpoint
+
+	ConsecutiveFailures  int    `json:"consecutiveFailures"`  // Failed delivery attempts in a row
+	LastSuccessTimeStamp string `json:"lastSuccessTimestamp"` // UTC timestamp of last successful delivery attempt
+	LastFailureTimeStamp string `json:"lastFailureTimestamp"` // UTC timestamp of last failed delivery attempt
+	DisabledReason       string `json:"disabledReason"`       // Reason for the webhook to be disabled automatically
 }
 
-// WebhookDelivery Details of payload delivery to webhook endpoint
-type WebhookDelivery struct {
-	ID                      string              `bson:"_id,omitempty"` // Webhook delivery ID
-	WebhookID               string              // Webhook ID
-	UserID                  string              // ID of user who triggered the webhook event
-	WebhookEventType        string              // Webhook event type for e.g. data.delete.initiated
-	RequestHeaders          map[string][]string // HTTP headers posted to webhook endpoint
-	RequestPayload          interface{}         // JSON payload posted to webhook endpoint
-	ResponseHeaders         map[string][]string // HTTP response headers received from webhook endpoint
-	ResponseBody            string              // HTTP response body received from webhook endpoint in bytes
-	ResponseStatusCode      int                 // HTTP response status code
-	ResponseStatusStr       string              // HTTP response status string
-	ExecutionStartTimeStamp string              // UTC timestamp when webhook execution started
-	ExecutionEndTimeStamp   string              // UTC timestamp when webhook execution ended
-	Status                  string              // Status of webhook delivery for e.g. failed or completed
-	StatusDescription       string              // Describe the status for e.g. Reason for failure
+// EndpointOptions Returns TLS and HTTP options for posting to webhook endpoint
+func (webhook Webhook) EndpointOptions() webhook_dispatcher.EndpointOptions {
+	return webhook_dispatcher.EndpointOptions{
+		SkipSSLVerification: webhook.SkipSSLVerification,
+		ClientCertificate:   webhook.ClientCertificate,
+		ClientKey:           webhook.ClientKey,
+		CACertificates:      webhook.CACertificates,
+		Headers:             webhook.Headers,
+	}
 }
 
+// WebhookDelivery Details of payload delivery to webhook endpoint
+type WebhookDelivery = webhook_dispatcher.WebhookDelivery
+
+// WebhookSecret Previous secret of a webhook which is used for signing payloads until it expires
+type WebhookSecret = webhook_dispatcher.WebhookSecret
+
 func WebhookCollection() *mongo.Collection {
 	return database.DB.Client.Database(database.DB.Name).Collection("webhooks")
 }
@@ -91,6 +104,47 @@ func UpdateWebhook(webhook Webhook) (Webhook, error) {
 	return webhook, err
 }
 
+// ReencryptClientKeys Re-encrypts the client keys of the webhooks which are
+// encrypted with a previous encryption key, so the previous key can be
+// removed from the configuration
+func ReencryptClientKeys() error {
+	var webhooks []Webhook
+
+	cursor, err := WebhookCollection().Find(context.TODO(), bson.M{"clientkey": bson.M{"$gt": ""}})
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(context.TODO())
+
+	if err := cursor.All(context.TODO(), &webhooks); err != nil {
+		return err
+	}
+
+	for _, webhook := range webhooks {
+		if webhook_dispatcher.IsEncryptedWithCurrentKey(webhook.ClientKey) {
+			continue
+		}
+
+		clientKey, err := DecryptSecret(webhook.ClientKey)
+		if err != nil {
+			return fmt.Errorf("failed to decrypt client key of webhook %v: %v", webhook.ID, err)
+		}
+		encryptedClientKey, err := EncryptSecret(clientKey)
+		if err != nil {
+			return err
+		}
+
+		// Client key is replaced only if it wasn't updated meanwhile
+		filter := bson.M{"_id": webhook.ID, "clientkey": webhook.ClientKey}
+		update := bson.M{"$set": bson.M{"clientkey": encryptedClientKey}}
+		_, err = WebhookCollection().UpdateOne(context.TODO(), filter, update)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // GetActiveWebhooksByOrgID Gets all active webhooks for a particular organisation
 func GetActiveWebhooksByOrgID(orgID string) (results []Webhook, err error) {
 	filter := bson.M{"orgid": orgID, "disabled": false, "isdeleted": false}
@@ -185,6 +239,45 @@ func GetAllDeliveryByWebhookId(webhookId string) (results []WebhookDelivery, err
 	return results, nil
 }
 
+// WebhookDeliveryStatusCount Number of deliveries to a webhook by delivery status
+type WebhookDeliveryStatusCount struct {
+	Status    string // Status of webhook delivery
+	IsSuccess bool   // Endpoint responded with 2XX status code
+	Count     int64  // Number of deliveries
+}
+
+// GetDeliveryStatusCountsByWebhookId Gets the number of deliveries to a webhook grouped by delivery status
+func GetDeliveryStatusCountsByWebhookId(webhookId string) ([]WebhookDeliveryStatusCount, error) {
+	var results []WebhookDeliveryStatusCount
+
+	pipeline := []bson.M{
+		{"$match": bson.M{"webhookid": webhookId}},
+		{"$group": bson.M{
+			"_id": bson.M{
+				"status": "$status",
+				"issuccess": bson.M{"$and": []interface{}{
+					bson.M{"$gte": []interface{}{"$responsestatuscode", 200}},
+					bson.M{"$lte": []interface{}{"$responsestatuscode", 299}},
+				}},
+			},
+			"count": bson.M{"$sum": 1},
+		}},
+		{"$project": bson.M{"_id": 0, "status": "$_id.status", "issuccess": "$_id.issuccess", "count": 1}},
+	}
+
+	cursor, err := WebhookDeliveryCollection().Aggregate(context.TODO(), pipeline)
+	if err != nil {
+		return results, err
+	}
+	defer cursor.Close(context.TODO())
+
+	if err := cursor.All(context.TODO(), &results); err != nil {
+		return results, err
+	}
+
+	return results, nil
+}
+
 type WebhookRepository struct {
 	DefaultFilter bson.M
 }
@@ -0,0 +1,116 @@
+package webhook
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+)
+
+// Matches HTTP status code class for e.g. 5xx
+var responseStatusClassRegex = regexp.MustCompile(`^[1-5][xX][xX]$`)
+
+// WebhookDeliveryFilter Filters for searching payload deliveries to a webhook
+type WebhookDeliveryFilter struct {
+	Status             string `json:"status"`             // Status of webhook delivery for e.g. failed
+	EventType          string `json:"eventType"`          // Webhook event type for e.g. consent.allowed
+	ResponseStatusCode string `json:"responseStatusCode"` // HTTP response status code for e.g. 503 or class for e.g. 5xx
+	FromTimeStamp      string `json:"fromTimestamp"`      // Deliveries started at or after the UTC timestamp
+	ToTimeStamp        string `json:"toTimestamp"`        // Deliveries started at or before the UTC timestamp
+}
+
+// normaliseTimeStamp Converts RFC3339 timestamp to UTC timestamp format stored in db
+func normaliseTimeStamp(timestamp string) (string, error) {
+	t, err := time.Parse(time.RFC3339, timestamp)
+	if err != nil {
+		return "", fmt.Errorf("invalid timestamp: %v, please provide RFC3339 timestamp", timestamp)
+	}
+	return t.UTC().Format("2006-01-02T15:04:05Z"), nil
+}
+
+// Validate Checks the filter values and normalises the timestamps
+func (f *WebhookDeliveryFilter) Validate() error {
+	if len(f.Status) > 0 && !slices.Contains(maps.Values(DeliveryStatus), f.Status) {
+		return fmt.Errorf("invalid delivery status: %v", f.Status)
+	}
+
+	if len(f.EventType) > 0 && !slices.Contains(maps.Values(EventTypes), f.EventType) {
+		return fmt.Errorf("invalid event type: %v", f.EventType)
+	}
+
+	if len(f.ResponseStatusCode) > 0 && !responseStatusClassRegex.MatchString(f.ResponseStatusCode) {
+		code, err := strconv.Atoi(f.ResponseStatusCode)
+		if err != nil || code < 100 || code > 599 {
+			return fmt.Errorf("invalid response status code: %v", f.ResponseStatusCode)
+		}
+	}
+
+	var err error
+	if len(f.FromTimeStamp) > 0 {
+		f.FromTimeStamp, err = normaliseTimeStamp(f.FromTimeStamp)
+		if err != nil {
+			return err
+		}
+	}
+	if len(f.ToTimeStamp) > 0 {
+		f.ToTimeStamp, err = normaliseTimeStamp(f.ToTimeStamp)
+		if err != nil {
+			return err
+		}
+	}
+	if len(f.FromTimeStamp) > 0 && len(f.ToTimeStamp) > 0 && f.FromTimeStamp > f.ToTimeStamp {
+		return errors.New("from timestamp should be before to timestamp")
+	}
+
+	return nil
+}
+
+// Query Returns the db filter for payload deliveries to the webhook
+func (f WebhookDeliveryFilter) Query(webhookId string) bson.M {
+	query := bson.M{"webhookid": webhookId}
+
+	if len(f.Status) > 0 {
+		query["status"] = f.Status
+	}
+
+	if len(f.EventType) > 0 {
+		query["webhookeventtype"] = f.EventType
+	}
+
+	if len(f.ResponseStatusCode) > 0 {
+		if responseStatusClassRegex.MatchString(f.ResponseStatusCode) {
+			class, _ := strconv.Atoi(f.ResponseStatusCode[:1])
+			query["responsestatuscode"] = bson.M{"$gte": class * 100, "$lte": class*100 + 99}
+		} else {
+			code, _ := strconv.Atoi(f.ResponseStatusCode)
+			query["responsestatuscode"] = code
+		}
+	}
+
+	timeRange := bson.M{}
+	if len(f.FromTimeStamp) > 0 {
+		timeRange["$gte"] = f.FromTimeStamp
+	}
+	if len(f.ToTimeStamp) > 0 {
+		timeRange["$lte"] = f.ToTimeStamp
+	}
+	if len(timeRange) > 0 {
+		query["executionstarttimestamp"] = timeRange
+	}
+
+	return query
+}
+
+// SearchDeliveriesPipeline Returns the pipeline for listing payload deliveries
+// to the webhook matching the filter, latest deliveries first
+func SearchDeliveriesPipeline(webhookId string, filter WebhookDeliveryFilter) []bson.M {
+	return []bson.M{
+		{"$match": filter.Query(webhookId)},
+		{"$sort": bson.M{"executionstarttimestamp": -1}},
+	}
+}
@@ -0,0 +1,41 @@
+package webhook
+
+// WebhookFilter Restricts the events delivered to a webhook, empty filter
+// attributes match all events
+type WebhookFilter struct {
+	DataAgreementIds []string `json:"dataAgreementIds"` // Data agreements for which events are delivered
+	LawfulBasis      []string `json:"lawfulBasis"`      // Lawful basis of processing for which events are delivered
+	MethodOfUse      []string `json:"methodOfUse"`      // Method of use for which events are delivered
+}
+
+// FilterableWebhookEventData Interface implemented by webhook event data related to a data agreement
+type FilterableWebhookEventData interface {
+	GetDataAgreementID() string
+	GetLawfulBasis() string
+	GetMethodOfUse() string
+}
+
+func matchesFilterAttribute(filterValues []string, value string) bool {
+	if len(filterValues) == 0 {
+		return true
+	}
+	for _, filterValue := range filterValues {
+		if filterValue == value {
+			return true
+		}
+	}
+	return false
+}
+
+// Matches Checks if the webhook event data matches the filter. Events which
+// aren't related to a data agreement are not filtered.
+func (f WebhookFilter) Matches(webhookEventData WebhookEventData) bool {
+	filterableEventData, ok := webhookEventData.(FilterableWebhookEventData)
+	if !ok {
+		return true
+	}
+
+	return matchesFilterAttribute(f.DataAgreementIds, filterableEventData.GetDataAgreementID()) &&
+		matchesFilterAttribute(f.LawfulBasis, filterableEventData.GetLawfulBasis()) &&
+		matchesFilterAttribute(f.MethodOfUse, filterableEventData.GetMethodOfUse())
+}
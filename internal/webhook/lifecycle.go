@@ -0,0 +1,184 @@
+package webhook
+
+import (
+	"github.com/bb-consent/api/internal/apikey"
+	"github.com/bb-consent/api/internal/dataagreement"
+	"github.com/bb-consent/api/internal/individual"
+	"github.com/bb-consent/api/internal/policy"
+	"github.com/bb-consent/api/internal/revision"
+)
+
+// DataAgreementWebhookEvent Details of data agreement lifecycle event
+type DataAgreementWebhookEvent struct {
+	DataAgreementId           string `json:"dataAgreementId"`
+	DataAgreementRevisionId   string `json:"dataAgreementRevisionId"`
+	DataAgreementRevisionHash string `json:"dataAgreementRevisionHash"`
+	Version                   string `json:"version"`
+	Purpose                   string `json:"purpose"`
+	LawfulBasis               string `json:"lawfulBasis"`
+	MethodOfUse               string `json:"methodOfUse"`
+	Lifecycle                 string `json:"lifecycle"`
+	Active                    bool   `json:"active"`
+	OrganisationId            string `json:"organisationId"`
+	UserId                    string `json:"-"`
+}
+
+// GetOrganisationID Returns organisation ID
+func (e DataAgreementWebhookEvent) GetOrganisationID() string {
+	return e.OrganisationId
+}
+
+// GetUserID Returns user ID
+func (e DataAgreementWebhookEvent) GetUserID() string {
+	return e.UserId
+}
+
+// GetDataAgreementID Returns data agreement ID
+func (e DataAgreementWebhookEvent) GetDataAgreementID() string {
+	return e.DataAgreementId
+}
+
+// GetLawfulBasis Returns lawful basis of the data agreement
+func (e DataAgreementWebhookEvent) GetLawfulBasis() string {
+	return e.LawfulBasis
+}
+
+// GetMethodOfUse Returns method of use of the data agreement
+func (e DataAgreementWebhookEvent) GetMethodOfUse() string {
+	return e.MethodOfUse
+}
+
+// PolicyWebhookEvent Details of policy lifecycle event
+type PolicyWebhookEvent struct {
+	PolicyId           string `json:"policyId"`
+	PolicyRevisionId   string `json:"policyRevisionId"`
+	PolicyRevisionHash string `json:"policyRevisionHash"`
+	Version            string `json:"version"`
+	Name               string `json:"name"`
+	Url                string `json:"url"`
+	OrganisationId     string `json:"organisationId"`
+	UserId             string `json:"-"`
+}
+
+// GetOrganisationID Returns organisation ID
+func (e PolicyWebhookEvent) GetOrganisationID() string {
+	return e.OrganisationId
+}
+
+// GetUserID Returns user ID
+func (e PolicyWebhookEvent) GetUserID() string {
+	return e.UserId
+}
+
+// IndividualWebhookEvent Details of individual lifecycle event
+type IndividualWebhookEvent struct {
+	IndividualId   string `json:"individualId"`
+	ExternalId     string `json:"externalId"`
+	ExternalIdType string `json:"externalIdType"`
+	OrganisationId string `json:"organisationId"`
+}
+
+// GetOrganisationID Returns organisation ID
+func (e IndividualWebhookEvent) GetOrganisationID() string {
+	return e.OrganisationId
+}
+
+// GetUserID Returns user ID
+func (e IndividualWebhookEvent) GetUserID() string {
+	return e.IndividualId
+}
+
+// ApiKeyWebhookEvent Details of api key lifecycle event
+type ApiKeyWebhookEvent struct {
+	ApiKeyId        string   `json:"apiKeyId"`
+	Name            string   `json:"name"`
+	Scopes          []string `json:"scopes"`
+	ExpiryTimestamp string   `json:"expiryTimestamp"`
+	Revoked         bool     `json:"revoked"`
+	OrganisationId  string   `json:"organisationId"`
+	UserId          string   `json:"-"`
+}
+
+// GetOrganisationID Returns organisation ID
+func (e ApiKeyWebhookEvent) GetOrganisationID() string {
+	return e.OrganisationId
+}
+
+// GetUserID Returns user ID
+func (e ApiKeyWebhookEvent) GetUserID() string {
+	return e.UserId
+}
+
+// TriggerDataAgreementWebhookEvent Trigger webhook for data agreement related events
+func TriggerDataAgreementWebhookEvent(da dataagreement.DataAgreement, rev revision.Revision, userId string, eventType string) {
+
+	// Constructing webhook event data attribute
+	dataAgreementWebhookEvent := DataAgreementWebhookEvent{
+		DataAgreementId:           da.Id,
+		DataAgreementRevisionId:   rev.Id,
+		DataAgreementRevisionHash: rev.SerializedHash,
+		Version:                   da.Version,
+		Purpose:                   da.Purpose,
+		LawfulBasis:               da.LawfulBasis,
+		MethodOfUse:               da.MethodOfUse,
+		Lifecycle:                 da.Lifecycle,
+		Active:                    da.Active,
+		OrganisationId:            da.OrganisationId,
+		UserId:                    userId,
+	}
+
+	// triggering the webhook
+	TriggerWebhooks(dataAgreementWebhookEvent, eventType)
+}
+
+// TriggerPolicyWebhookEvent Trigger webhook for policy related events
+func TriggerPolicyWebhookEvent(p policy.Policy, rev revision.Revision, userId string, eventType string) {
+
+	// Constructing webhook event data attribute
+	policyWebhookEvent := PolicyWebhookEvent{
+		PolicyId:           p.Id,
+		PolicyRevisionId:   rev.Id,
+		PolicyRevisionHash: rev.SerializedHash,
+		Version:            p.Version,
+		Name:               p.Name,
+		Url:                p.Url,
+		OrganisationId:     p.OrganisationId,
+		UserId:             userId,
+	}
+
+	// triggering the webhook
+	TriggerWebhooks(policyWebhookEvent, eventType)
+}
+
+// TriggerIndividualWebhookEvent Trigger webhook for individual related events
+func TriggerIndividualWebhookEvent(i individual.Individual, eventType string) {
+
+	// Constructing webhook event data attribute
+	individualWebhookEvent := IndividualWebhookEvent{
+		IndividualId:   i.Id,
+		ExternalId:     i.ExternalId,
+		ExternalIdType: i.ExternalIdType,
+		OrganisationId: i.OrganisationId,
+	}
+
+	// triggering the webhook
+	TriggerWebhooks(individualWebhookEvent, eventType)
+}
+
+// TriggerApiKeyWebhookEvent Trigger webhook for api key related events
+func TriggerApiKeyWebhookEvent(apiKey apikey.ApiKey, userId string, eventType string) {
+
+	// Constructing webhook event data attribute
+	apiKeyWebhookEvent := ApiKeyWebhookEvent{
+		ApiKeyId:        apiKey.Id,
+		Name:            apiKey.Name,
+		Scopes:          apiKey.Scopes,
+		ExpiryTimestamp: apiKey.ExpiryTimestamp,
+		Revoked:         apiKey.Revoked,
+		OrganisationId:  apiKey.OrganisationId,
+		UserId:          userId,
+	}
+
+	// triggering the webhook
+	TriggerWebhooks(apiKeyWebhookEvent, eventType)
+}
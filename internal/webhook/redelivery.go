@@ -0,0 +1,208 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/bb-consent/api/internal/actionlog"
+	"github.com/bb-consent/api/internal/common"
+	"github.com/bb-consent/api/internal/database"
+	"github.com/bb-consent/api/internal/webhook_dispatcher"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Redelivery job status const
+const (
+	RedeliveryJobStatusPending   = "pending"
+	RedeliveryJobStatusRunning   = "running"
+	RedeliveryJobStatusCompleted = "completed"
+	RedeliveryJobStatusFailed    = "failed"
+)
+
+// Number of redeliveries after which the progress of the job is saved
+const redeliveryJobProgressInterval = 50
+
+// Interval at which a running job saves its progress, the saved timestamp is
+// the heartbeat of the job
+const redeliveryJobHeartbeatInterval = 30 * time.Second
+
+// Jobs without a heartbeat within the timeout are considered interrupted,
+// for e.g. by a restart
+const redeliveryJobHeartbeatTimeout = 5 * time.Minute
+
+// WebhookRedeliveryJob Details of bulk redelivery of payloads to a webhook
+type WebhookRedeliveryJob struct {
+	ID                 string                `json:"id" bson:"_id,omitempty"` // Redelivery job ID
+	OrganisationId     string                `json:"orgId" bson:"orgid"`      // Organisation ID
+	WebhookId          string                `json:"webhookId"`               // Webhook ID
+	UserId             string                `json:"userId"`                  // ID of user who started the job
+	Filter             WebhookDeliveryFilter `json:"filter"`                  // Filter for the deliveries to be redelivered
+	Status             string                `json:"status"`                  // Status of the job for e.g. running or completed
+	StatusDescription  string                `json:"statusDescription"`       // Describe the status for e.g. Reason for failure
+	TotalDeliveries    int64                 `json:"totalDeliveries"`         // Number of deliveries matching the filter
+	RedeliveredCount   int64                 `json:"redeliveredCount"`        // Number of deliveries queued again
+	FailedCount        int64                 `json:"failedCount"`             // Number of deliveries which couldn't be queued again
+	TimeStamp          string                `json:"timestamp"`               // UTC timestamp when the job was created
+	StartedTimeStamp   string                `json:"startedTimestamp"`        // UTC timestamp when the job started
+	CompletedTimeStamp string                `json:"completedTimestamp"`      // UTC timestamp when the job completed
+	HeartbeatTimeStamp string                `json:"heartbeatTimestamp"`      // UTC timestamp when the job last saved its progress
+}
+
+func WebhookRedeliveryJobCollection() *mongo.Collection {
+	return database.DB.Client.Database(database.DB.Name).Collection("webhookRedeliveryJobs")
+}
+
+type WebhookRedeliveryJobRepository struct {
+	DefaultFilter bson.M
+}
+
+// Init
+func (jobRepo *WebhookRedeliveryJobRepository) Init(organisationId string) {
+	jobRepo.DefaultFilter = bson.M{"orgid": organisationId}
+}
+
+// Add Adds a redelivery job
+func (jobRepo *WebhookRedeliveryJobRepository) Add(job WebhookRedeliveryJob) (WebhookRedeliveryJob, error) {
+	_, err := WebhookRedeliveryJobCollection().InsertOne(context.TODO(), &job)
+	return job, err
+}
+
+// Get Gets a redelivery job of a webhook by ID
+func (jobRepo *WebhookRedeliveryJobRepository) Get(webhookId string, jobId string) (WebhookRedeliveryJob, error) {
+	var result WebhookRedeliveryJob
+
+	filter := common.CombineFilters(jobRepo.DefaultFilter, bson.M{"_id": jobId, "webhookid": webhookId})
+	err := WebhookRedeliveryJobCollection().FindOne(context.TODO(), filter).Decode(&result)
+
+	return result, err
+}
+
+// FailInterrupted Marks the jobs of the organisation which haven't saved
+// their progress within the heartbeat timeout as failed, they were
+// interrupted for e.g. by a restart and won't complete
+func (jobRepo *WebhookRedeliveryJobRepository) FailInterrupted() error {
+	now := time.Now().UTC()
+	cutoffTimestamp := now.Add(-redeliveryJobHeartbeatTimeout).Format("2006-01-02T15:04:05Z")
+
+	// Jobs created before heartbeats were saved are checked by the timestamp they were created
+	filter := bson.M{
+		"orgid":  jobRepo.DefaultFilter["orgid"],
+		"status": bson.M{"$in": []string{RedeliveryJobStatusPending, RedeliveryJobStatusRunning}},
+		"$or": []bson.M{
+			{"heartbeattimestamp": bson.M{"$lt": cutoffTimestamp}},
+			{"heartbeattimestamp": bson.M{"$exists": false}, "timestamp": bson.M{"$lt": cutoffTimestamp}},
+		},
+	}
+	update := bson.M{"$set": bson.M{
+		"status":             RedeliveryJobStatusFailed,
+		"statusdescription":  "Redelivery job was interrupted, please start a new job for the remaining deliveries",
+		"completedtimestamp": now.Format("2006-01-02T15:04:05Z"),
+	}}
+
+	_, err := WebhookRedeliveryJobCollection().UpdateMany(context.TODO(), filter, update)
+	return err
+}
+
+// ListPipeline Returns the pipeline for listing redelivery jobs of a webhook, latest jobs first
+func (jobRepo *WebhookRedeliveryJobRepository) ListPipeline(webhookId string) []bson.M {
+	return []bson.M{
+		{"$match": common.CombineFilters(jobRepo.DefaultFilter, bson.M{"webhookid": webhookId})},
+		{"$sort": bson.M{"timestamp": -1}},
+	}
+}
+
+// updateWebhookRedeliveryJob Updates a redelivery job, the heartbeat of the
+// job is updated along with it
+func updateWebhookRedeliveryJob(job WebhookRedeliveryJob) error {
+	job.HeartbeatTimeStamp = time.Now().UTC().Format("2006-01-02T15:04:05Z")
+	_, err := WebhookRedeliveryJobCollection().ReplaceOne(context.TODO(), bson.M{"_id": job.ID}, job)
+	return err
+}
+
+// RunWebhookRedeliveryJob Queues again the payload deliveries matching the filter of the job
+func RunWebhookRedeliveryJob(job WebhookRedeliveryJob, webhook Webhook) {
+	job.Status = RedeliveryJobStatusRunning
+	job.StartedTimeStamp = time.Now().UTC().Format("2006-01-02T15:04:05Z")
+
+	// Deliveries queued by the job match the filter as well, so only the
+	// deliveries started before the job was created are redelivered
+	filter := job.Filter
+	if len(filter.ToTimeStamp) == 0 || filter.ToTimeStamp > job.TimeStamp {
+		filter.ToTimeStamp = job.TimeStamp
+	}
+	query := filter.Query(job.WebhookId)
+
+	count, err := WebhookDeliveryCollection().CountDocuments(context.TODO(), query)
+	if err != nil {
+		redeliveryJobFailed(job, err)
+		return
+	}
+	job.TotalDeliveries = count
+	if err := updateWebhookRedeliveryJob(job); err != nil {
+		log.Printf("Failed to update webhook redelivery job:%v err:%v", job.ID, err)
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "executionstarttimestamp", Value: 1}})
+	cursor, err := WebhookDeliveryCollection().Find(context.TODO(), query, opts)
+	if err != nil {
+		redeliveryJobFailed(job, err)
+		return
+	}
+	defer cursor.Close(context.TODO())
+
+	lastSavedAt := time.Now()
+	for cursor.Next(context.TODO()) {
+		var webhookDelivery WebhookDelivery
+		if err := cursor.Decode(&webhookDelivery); err != nil {
+			log.Printf("Failed to decode webhook delivery for redelivery job:%v err:%v", job.ID, err)
+			job.FailedCount++
+			continue
+		}
+
+		_, err := webhook_dispatcher.Enqueue(webhookDelivery.RequestPayload, job.OrganisationId, webhookDelivery.UserID)
+		if err != nil {
+			log.Printf("Failed to queue webhook delivery:%v for redelivery job:%v err:%v", webhookDelivery.ID, job.ID, err)
+			job.FailedCount++
+		} else {
+			job.RedeliveredCount++
+		}
+
+		if (job.RedeliveredCount+job.FailedCount)%redeliveryJobProgressInterval == 0 || time.Since(lastSavedAt) >= redeliveryJobHeartbeatInterval {
+			if err := updateWebhookRedeliveryJob(job); err != nil {
+				log.Printf("Failed to update webhook redelivery job:%v err:%v", job.ID, err)
+			}
+			lastSavedAt = time.Now()
+		}
+	}
+	if err := cursor.Err(); err != nil {
+		redeliveryJobFailed(job, err)
+		return
+	}
+
+	job.Status = RedeliveryJobStatusCompleted
+	job.CompletedTimeStamp = time.Now().UTC().Format("2006-01-02T15:04:05Z")
+	if err := updateWebhookRedeliveryJob(job); err != nil {
+		log.Printf("Failed to update webhook redelivery job:%v err:%v", job.ID, err)
+	}
+
+	// Log webhook calls in webhooks category
+	userId, userName := GetTriggeredBy(job.OrganisationId, job.UserId)
+	aLog := fmt.Sprintf("Organization webhook: %v payloads redelivered: %v by user: %v", webhook.PayloadURL, job.RedeliveredCount, userName)
+	actionlog.LogOrgWebhookCalls(userId, userName, job.OrganisationId, aLog)
+}
+
+// redeliveryJobFailed Marks the redelivery job as failed
+func redeliveryJobFailed(job WebhookRedeliveryJob, err error) {
+	log.Printf("Webhook redelivery job:%v failed err:%v", job.ID, err)
+
+	job.Status = RedeliveryJobStatusFailed
+	job.StatusDescription = err.Error()
+	job.CompletedTimeStamp = time.Now().UTC().Format("2006-01-02T15:04:05Z")
+	if err := updateWebhookRedeliveryJob(job); err != nil {
+		log.Printf("Failed to update webhook redelivery job:%v err:%v", job.ID, err)
+	}
+}
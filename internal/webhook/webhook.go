@@ -1,8 +1,6 @@
 package webhook
 
 import (
-	"crypto/tls"
-	"encoding/json"
 	"fmt"
 	"log"
 	"net/http"
@@ -11,9 +9,15 @@ import (
 
 	"github.com/bb-consent/api/internal/actionlog"
 	"github.com/bb-consent/api/internal/config"
+	"github.com/bb-consent/api/internal/dataagreement"
 	daRecord "github.com/bb-consent/api/internal/dataagreement_record"
+	"github.com/bb-consent/api/internal/eventsink"
 	"github.com/bb-consent/api/internal/individual"
+	"github.com/bb-consent/api/internal/user"
 	"github.com/bb-consent/api/internal/webhook_dispatcher"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"golang.org/x/exp/slices"
 )
 
 // Event type const
@@ -27,28 +31,67 @@ const (
 	EventTypeDataUpdateCancelled   = 15
 
 	// Consent events
-	EventTypeConsentAllowed    = 30
-	EventTypeConsentDisAllowed = 31
-	EventTypeConsentAutoExpiry = 32
+	EventTypeConsentAllowed         = 30
+	EventTypeConsentDisAllowed      = 31
+	EventTypeConsentAutoExpiry      = 32
+	EventTypeConsentRenewalRequired = 33
 
 	// Organisation subscription events
 	EventTypeOrgSubscribed   = 50
 	EventTypeOrgUnSubscribed = 51
+
+	// Data agreement events
+	EventTypeDataAgreementCreated   = 70
+	EventTypeDataAgreementPublished = 71
+	EventTypeDataAgreementUpdated   = 72
+	EventTypeDataAgreementDeleted   = 73
+
+	// Policy events
+	EventTypePolicyCreated = 80
+	EventTypePolicyUpdated = 81
+	EventTypePolicyDeleted = 82
+
+	// Individual events
+	EventTypeIndividualCreated = 90
+	EventTypeIndividualUpdated = 91
+	EventTypeIndividualDeleted = 92
+
+	// Api key events
+	EventTypeApiKeyCreated = 100
+	EventTypeApiKeyUpdated = 101
+	EventTypeApiKeyRevoked = 102
+	EventTypeApiKeyDeleted = 103
 )
 
 // EventTypes Map of webhook event type id and name
 var EventTypes = map[int]string{
-	EventTypeDataDeleteInitiated:   "data.delete.initiated",
-	EventTypeDataDownloadInitiated: "data.download.initiated",
-	EventTypeDataUpdateInitiated:   "data.update.initiated",
-	EventTypeDataDeleteCancelled:   "data.delete.cancelled",
-	EventTypeDataDownloadCancelled: "data.download.cancelled",
-	EventTypeDataUpdateCancelled:   "data.update.cancelled",
-	EventTypeConsentAllowed:        "consent.allowed",
-	EventTypeConsentDisAllowed:     "consent.disallowed",
-	EventTypeConsentAutoExpiry:     "consent.auto_expiry",
-	EventTypeOrgSubscribed:         "org.subscribed",
-	EventTypeOrgUnSubscribed:       "org.unsubscribed",
+	EventTypeDataDeleteInitiated:    "data.delete.initiated",
+	EventTypeDataDownloadInitiated:  "data.download.initiated",
+	EventTypeDataUpdateInitiated:    "data.update.initiated",
+	EventTypeDataDeleteCancelled:    "data.delete.cancelled",
+	EventTypeDataDownloadCancelled:  "data.download.cancelled",
+	EventTypeDataUpdateCancelled:    "data.update.cancelled",
+	EventTypeConsentAllowed:         "consent.allowed",
+	EventTypeConsentDisAllowed:      "consent.disallowed",
+	EventTypeConsentAutoExpiry:      "consent.auto_expiry",
+	EventTypeConsentRenewalRequired: "consent.renewal_required",
+	EventTypeOrgSubscribed:          "org.subscribed",
+	EventTypeOrgUnSubscribed:        "org.unsubscribed",
+
+	EventTypeDataAgreementCreated:   "data_agreement.created",
+	EventTypeDataAgreementPublished: "data_agreement.published",
+	EventTypeDataAgreementUpdated:   "data_agreement.updated",
+	EventTypeDataAgreementDeleted:   "data_agreement.deleted",
+	EventTypePolicyCreated:          "policy.created",
+	EventTypePolicyUpdated:          "policy.updated",
+	EventTypePolicyDeleted:          "policy.deleted",
+	EventTypeIndividualCreated:      "individual.created",
+	EventTypeIndividualUpdated:      "individual.updated",
+	EventTypeIndividualDeleted:      "individual.deleted",
+	EventTypeApiKeyCreated:          "api_key.created",
+	EventTypeApiKeyUpdated:          "api_key.updated",
+	EventTypeApiKeyRevoked:          "api_key.revoked",
+	EventTypeApiKeyDeleted:          "api_key.deleted",
 }
 
 // WebhooksConfiguration Stores webhooks configuration
@@ -81,41 +124,87 @@ type WebhookEventData interface {
 	GetUserID() string
 }
 
-// WebhookEvent Defines the structure for webhook event
-type WebhookEvent struct {
-	WebhookID string      `json:"webhookID"` // Webhook endpoint ID
-	Timestamp string      `json:"timestamp"` // UTC timestamp of webhook triggered data time
-	Data      interface{} `json:"data"`      // Event data attribute
-	Type      string      `json:"type"`      // Event type for e.g. data.delete.initiated
-}
+// WebhookEvent Webhook event envelope delivered to webhooks
+type WebhookEvent = webhook_dispatcher.WebhookEvent
 
 // Payload content type const
 const (
-	// Payload will be posted as json body
-	PayloadContentTypeJSON = 112
-
-	// Payload will be stringified and posted as form under `payload` key
-	PayloadContentTypeFormURLEncoded = 113
+	PayloadContentTypeJSON                  = webhook_dispatcher.PayloadContentTypeJSON
+	PayloadContentTypeFormURLEncoded        = webhook_dispatcher.PayloadContentTypeFormURLEncoded
+	PayloadContentTypeCloudEventsStructured = webhook_dispatcher.PayloadContentTypeCloudEventsStructured
+	PayloadContentTypeCloudEventsBinary     = webhook_dispatcher.PayloadContentTypeCloudEventsBinary
 )
 
 // PayloadContentTypes Available data format for payload to be posted to webhook
-var PayloadContentTypes = map[int]string{
-	PayloadContentTypeJSON:           "application/json",
-	PayloadContentTypeFormURLEncoded: "application/x-www-form-urlencoded",
-}
+var PayloadContentTypes = webhook_dispatcher.PayloadContentTypes
 
 // Delivery status const
 const (
-	DeliveryStatusCompleted = 212
-	DeliveryStatusFailed    = 213
+	DeliveryStatusCompleted = webhook_dispatcher.DeliveryStatusCompleted
+	DeliveryStatusFailed    = webhook_dispatcher.DeliveryStatusFailed
+	DeliveryStatusPending   = webhook_dispatcher.DeliveryStatusPending
+	DeliveryStatusRetrying  = webhook_dispatcher.DeliveryStatusRetrying
+	DeliveryStatusSucceeded = webhook_dispatcher.DeliveryStatusSucceeded
 )
 
 // DeliveryStatus Indicating the payload delivery status to webhook
-var DeliveryStatus = map[int]string{
-	DeliveryStatusCompleted: "completed",
-	DeliveryStatusFailed:    "failed",
+var DeliveryStatus = webhook_dispatcher.DeliveryStatus
+
+// Signature scheme const
+const (
+	SignatureSchemeIGrant           = webhook_dispatcher.SignatureSchemeIGrant
+	SignatureSchemeStandardWebhooks = webhook_dispatcher.SignatureSchemeStandardWebhooks
+)
+
+// SignatureSchemes Available schemes for signing payload posted to webhook
+var SignatureSchemes = webhook_dispatcher.SignatureSchemes
+
+// Time allowed for the webhook endpoint to respond to ping
+const pingTimeout = 30 * time.Second
+
+// ValidateWebhookURL Checks if the outbound network policy allows the webhook endpoint
+var ValidateWebhookURL = webhook_dispatcher.ValidateWebhookURL
+
+// ValidateSkipSSLVerification Checks if the outbound network policy allows skipping SSL certificate verification
+var ValidateSkipSSLVerification = webhook_dispatcher.ValidateSkipSSLVerification
+
+// ValidateClientCertificate Checks if the client certificate and key is a valid pair
+var ValidateClientCertificate = webhook_dispatcher.ValidateClientCertificate
+
+// ValidateCACertificates Checks if the CA bundle contains valid PEM encoded certificates
+var ValidateCACertificates = webhook_dispatcher.ValidateCACertificates
+
+// ValidateHeaders Checks if the custom headers are valid and doesn't override reserved headers
+var ValidateHeaders = webhook_dispatcher.ValidateHeaders
+
+// RedactHeaders Returns the custom headers with their values redacted
+var RedactHeaders = webhook_dispatcher.RedactHeaders
+
+// RedactedHeaderValue Value the custom header values are replaced with in api responses
+const RedactedHeaderValue = webhook_dispatcher.RedactedHeaderValue
+
+// RedactSecrets Returns the webhook with its signing secrets removed, for
+// callers which can read webhooks but aren't allowed to manage them
+func RedactSecrets(webhook Webhook) Webhook {
+	webhook.SecretKey = ""
+	previousSecrets := make([]WebhookSecret, len(webhook.PreviousSecrets))
+	for i, previousSecret := range webhook.PreviousSecrets {
+		previousSecret.Secret = ""
+		previousSecrets[i] = previousSecret
+	}
+	webhook.PreviousSecrets = previousSecrets
+	return webhook
 }
 
+// EncryptSecret Encrypts the secret before storing in db
+var EncryptSecret = webhook_dispatcher.EncryptSecret
+
+// DecryptSecret Decrypts the secret encrypted using EncryptSecret
+var DecryptSecret = webhook_dispatcher.DecryptSecret
+
+// GenerateWebhookSecret Generates a random webhook secret in Standard Webhooks format
+var GenerateWebhookSecret = webhook_dispatcher.GenerateWebhookSecret
+
 type ConsentRecordWebhookEvent struct {
 	ConsentRecordId           string `json:"consentRecordId"`
 	DataAgreementId           string `json:"dataAgreementId"`
@@ -126,6 +215,8 @@ type ConsentRecordWebhookEvent struct {
 	State                     string `json:"state"`
 	SignatureId               string `json:"signatureId"`
 	OrganisationId            string `json:"organisationId"`
+	lawfulBasis               string
+	methodOfUse               string
 }
 
 // GetOrganisationID Returns organisation ID
@@ -138,22 +229,49 @@ func (e ConsentRecordWebhookEvent) GetUserID() string {
 	return e.IndividualId
 }
 
+// GetDataAgreementID Returns data agreement ID
+func (e ConsentRecordWebhookEvent) GetDataAgreementID() string {
+	return e.DataAgreementId
+}
+
+// GetLawfulBasis Returns lawful basis of the data agreement
+func (e ConsentRecordWebhookEvent) GetLawfulBasis() string {
+	return e.lawfulBasis
+}
+
+// GetMethodOfUse Returns method of use of the data agreement
+func (e ConsentRecordWebhookEvent) GetMethodOfUse() string {
+	return e.methodOfUse
+}
+
 // PingWebhook Pings webhook payload URL to check the status
 func PingWebhook(webhook Webhook) (req *http.Request, resp *http.Response, executionStartTimeStamp string, executionEndTimeStamp string, err error) {
 	executionStartTimeStamp = strconv.FormatInt(time.Now().UTC().Unix(), 10)
 
+	// Checking if the outbound network policy allows the webhook endpoint
+	err = webhook_dispatcher.ValidateWebhookURL(webhook.PayloadURL)
+	if err != nil {
+		executionEndTimeStamp = strconv.FormatInt(time.Now().UTC().Unix(), 10)
+		return nil, nil, executionStartTimeStamp, executionEndTimeStamp, err
+	}
+
 	// Initializing a http request object and configuring necessary HTTP headers
-	req, _ = http.NewRequest("POST", webhook.PayloadURL, nil)
+	req, err = http.NewRequest("POST", webhook.PayloadURL, nil)
+	if err != nil {
+		executionEndTimeStamp = strconv.FormatInt(time.Now().UTC().Unix(), 10)
+		return nil, nil, executionStartTimeStamp, executionEndTimeStamp, err
+	}
+	webhook_dispatcher.SetCustomHeaders(req, webhook.EndpointOptions())
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("User-Agent", "IGrant-Hookshot/1.0")
 	req.Header.Set("Accept", "*/*")
 
-	// Defining a custom HTTP transport to control SSL certificate verification
-	transCfg := &http.Transport{
-		TLSClientConfig: &tls.Config{InsecureSkipVerify: webhook.SkipSSLVerification},
+	// HTTP client enforcing the outbound network policy, SSL certificate verification and mutual TLS
+	client, err := webhook_dispatcher.NewHTTPClient(webhook.EndpointOptions(), pingTimeout)
+	if err != nil {
+		executionEndTimeStamp = strconv.FormatInt(time.Now().UTC().Unix(), 10)
+		return req, nil, executionStartTimeStamp, executionEndTimeStamp, err
 	}
-
-	client := &http.Client{Transport: transCfg}
 	resp, err = client.Do(req)
 
 	executionEndTimeStamp = strconv.FormatInt(time.Now().UTC().Unix(), 10)
@@ -161,30 +279,77 @@ func PingWebhook(webhook Webhook) (req *http.Request, resp *http.Response, execu
 	return req, resp, executionStartTimeStamp, executionEndTimeStamp, err
 }
 
-// TriggerWebhooks Trigger webhooks based on event type
-func TriggerWebhooks(webhookEventData WebhookEventData, webhookEventType string) {
-
+// GetTriggeredBy Returns the ID and email of the individual or admin who
+// triggered the webhook event
+func GetTriggeredBy(organisationId string, userId string) (string, string) {
 	// Repository
 	individualRepo := individual.IndividualRepository{}
-	individualRepo.Init(webhookEventData.GetOrganisationID())
+	individualRepo.Init(organisationId)
+
+	i, err := individualRepo.Get(userId)
+	if err == nil {
+		return i.Id, i.Email
+	}
+
+	u, err := user.Get(userId)
+	if err == nil {
+		return u.ID, u.Email
+	}
+
+	return userId, ""
+}
+
+// TriggerWebhooks Trigger webhooks based on event type
+func TriggerWebhooks(webhookEventData WebhookEventData, webhookEventType string) error {
+	return TriggerWebhooksForEvent(primitive.NewObjectID().Hex(), webhookEventData, webhookEventType)
+}
 
-	// Get the user
-	individual, err := individualRepo.Get(webhookEventData.GetUserID())
+// TriggerWebhooksForEvent Trigger webhooks based on event type, the event ID
+// identifies the event so it can be triggered again on failure. Sinks
+// receive the event ID as delivery ID, so the consumers can discard an event
+// they already received, event log records the event once and the event is
+// queued once for a webhook.
+func TriggerWebhooksForEvent(eventId string, webhookEventData WebhookEventData, webhookEventType string) error {
+	return triggerEvent(eventId, webhookEventData, webhookEventType, true)
+}
+
+// triggerEvent Publishes the event to the sinks and, if fan out to webhooks
+// is enabled, queues it for the webhooks subscribed to the event
+func triggerEvent(eventId string, webhookEventData WebhookEventData, webhookEventType string, fanOutToWebhooks bool) error {
+
+	// Get the user who triggered the event
+	userId, userName := GetTriggeredBy(webhookEventData.GetOrganisationID(), webhookEventData.GetUserID())
+
+	// Publishing the event to message bus sinks, the envelope is same as
+	// webhooks except that it isn't specific to a webhook
+	sinkEvent, err := webhook_dispatcher.NewWebhookEvent("", webhookEventType, time.Now().UTC().Format("2006-01-02T15:04:05Z"), webhookEventData)
 	if err != nil {
-		log.Printf("Failed to fetch user details;Failed to trigger webhook for event:<%s>, org:<%s>", webhookEventType, webhookEventData.GetOrganisationID())
-		return
+		log.Printf("Failed to convert webhook event data to bytes, error:%v, Failed to trigger webhook for event:<%s>, user:<%s>, org:<%s>", err.Error(), webhookEventType, userId, webhookEventData.GetOrganisationID())
+		return err
+	}
+	sinkEvent.DeliveryID = eventId
+	err = eventsink.Publish(sinkEvent, webhookEventData.GetOrganisationID())
+	if err != nil {
+		log.Printf("Failed to publish event to sinks, error:%v, Failed to trigger webhook for event:<%s>, user:<%s>, org:<%s>", err.Error(), webhookEventType, userId, webhookEventData.GetOrganisationID())
+		return err
+	}
+	if !fanOutToWebhooks {
+		return nil
 	}
 
 	// Get the active webhooks for the organisation
 	activeWebhooks, err := GetActiveWebhooksByOrgID(webhookEventData.GetOrganisationID())
 	if err != nil {
-		log.Printf("Failed to fetch active webhooks;Failed to trigger webhook for event:<%s>, user:<%s>, org:<%s>", webhookEventType, individual.Id, webhookEventData.GetOrganisationID())
-		return
+		log.Printf("Failed to fetch active webhooks;Failed to trigger webhook for event:<%s>, user:<%s>, org:<%s>", webhookEventType, userId, webhookEventData.GetOrganisationID())
+		return err
 	}
 
-	// Filtering the webhooks that are subscribed to the event
+	// Filtering the webhooks that are subscribed to the event and whose filter matches the event
 	var toBeProcessedWebhooks []Webhook
 	for _, activeWebhook := range activeWebhooks {
+		if !activeWebhook.Filter.Matches(webhookEventData) {
+			continue
+		}
 		for _, subscribedEvent := range activeWebhook.SubscribedEvents {
 			if subscribedEvent == webhookEventType {
 				toBeProcessedWebhooks = append(toBeProcessedWebhooks, activeWebhook)
@@ -193,29 +358,31 @@ func TriggerWebhooks(webhookEventData WebhookEventData, webhookEventType string)
 		}
 	}
 
+	var enqueueErr error
 	for _, toBeProcessedWebhook := range toBeProcessedWebhooks {
 		// Constructing webhook payload
-		we := WebhookEvent{
-			WebhookID: toBeProcessedWebhook.ID,
-			Timestamp: time.Now().UTC().Format("2006-01-02T15:04:05Z"),
-			Data:      webhookEventData,
-			Type:      webhookEventType,
+		we, err := webhook_dispatcher.NewWebhookEvent(toBeProcessedWebhook.ID, webhookEventType, time.Now().UTC().Format("2006-01-02T15:04:05Z"), webhookEventData)
+		if err != nil {
+			log.Printf("Failed to convert webhook event data to bytes, error:%v, Failed to trigger webhook for event:<%s>, user:<%s>, org:<%s>", err.Error(), webhookEventType, userId, webhookEventData.GetOrganisationID())
+			return err
 		}
 
-		// Converting the webhook event data to bytes
-		b, err := json.Marshal(we)
+		_, queued, err := webhook_dispatcher.EnqueueOnce(we, webhookEventData.GetOrganisationID(), webhookEventData.GetUserID(), eventId)
 		if err != nil {
-			log.Printf("Failed to convert webhook event data to bytes, error:%v, Failed to trigger webhook for event:<%s>, user:<%s>, org:<%s>", err.Error(), webhookEventType, individual.Id, webhookEventData.GetOrganisationID())
-			return
+			log.Printf("Failed to queue webhook delivery, error:%v, Failed to trigger webhook for event:<%s>, user:<%s>, org:<%s>", err.Error(), webhookEventType, userId, webhookEventData.GetOrganisationID())
+			enqueueErr = err
+			continue
+		}
+		if !queued {
+			continue
 		}
-
-		go webhook_dispatcher.ProcessWebhooks(webhookEventType, b)
 
 		// Log webhook calls in webhooks category
-		aLog := fmt.Sprintf("Organization webhook: %v triggered by user: %v by event: %v", toBeProcessedWebhook.PayloadURL, individual.Email, webhookEventType)
-		actionlog.LogOrgWebhookCalls(individual.Id, individual.Email, webhookEventData.GetOrganisationID(), aLog)
+		aLog := fmt.Sprintf("Organization webhook: %v triggered by user: %v by event: %v", toBeProcessedWebhook.PayloadURL, userName, webhookEventType)
+		actionlog.LogOrgWebhookCalls(userId, userName, webhookEventData.GetOrganisationID(), aLog)
 	}
 
+	return enqueueErr
 }
 
 // TriggerOrgSubscriptionWebhookEvent Trigger webhook for organisation subscription related events
@@ -231,8 +398,9 @@ func TriggerOrgSubscriptionWebhookEvent(userID, orgID string, eventType string)
 	TriggerWebhooks(orgSubscriptionWebhookEvent, eventType)
 }
 
-// TriggerConsentWebhookEvent Trigger webhook for consent related events
-func TriggerConsentWebhookEvent(consentRecord daRecord.DataAgreementRecord, organisationId string, eventType string) {
+// TriggerConsentWebhookEvent Trigger webhook for consent related events, the
+// event ID identifies the event when it is triggered again
+func TriggerConsentWebhookEvent(eventId string, consentRecord daRecord.DataAgreementRecord, organisationId string, eventType string) error {
 
 	// Constructing webhook event data attribute
 	consentRecordWebhookEvent := ConsentRecordWebhookEvent{
@@ -247,11 +415,22 @@ func TriggerConsentWebhookEvent(consentRecord daRecord.DataAgreementRecord, orga
 		OrganisationId:            consentRecord.OrganisationId,
 	}
 
-	for _, e := range WebhooksConfiguration.Events {
-		if e == eventType {
-			// triggering the webhook
-			TriggerWebhooks(consentRecordWebhookEvent, eventType)
-		}
-
+	// Repository
+	daRepo := dataagreement.DataAgreementRepository{}
+	daRepo.Init(organisationId)
+
+	// Data agreement details are used for filtering the webhooks, a data
+	// agreement which is deleted doesn't match filters on its details
+	da, err := daRepo.Get(consentRecord.DataAgreementId)
+	if err != nil && err != mongo.ErrNoDocuments {
+		log.Printf("Failed to fetch data agreement:%s for filtering webhooks for event:<%s>, org:<%s>", consentRecord.DataAgreementId, eventType, organisationId)
+		return err
 	}
+	consentRecordWebhookEvent.lawfulBasis = da.LawfulBasis
+	consentRecordWebhookEvent.methodOfUse = da.MethodOfUse
+
+	// Event is always published to the sinks and event log, events which
+	// aren't enabled for webhooks aren't queued for the webhooks
+	fanOutToWebhooks := slices.Contains(WebhooksConfiguration.Events, eventType)
+	return triggerEvent(eventId, consentRecordWebhookEvent, eventType, fanOutToWebhooks)
 }
@@ -0,0 +1,74 @@
+package webhook_dispatcher
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+)
+
+// CloudEvents specification version
+const cloudEventsSpecVersion = "1.0"
+
+// CloudEvent Webhook event in CloudEvents 1.0 structured mode
+type CloudEvent struct {
+	SpecVersion     string          `json:"specversion"`
+	Id              string          `json:"id"`
+	Source          string          `json:"source"`
+	Type            string          `json:"type"`
+	Time            string          `json:"time"`
+	DataContentType string          `json:"datacontenttype"`
+	Data            json.RawMessage `json:"data"`
+}
+
+// getCloudEventSource Returns the source attribute identifying the organisation the event occured in
+func getCloudEventSource(organisationId string) string {
+	return "/organisations/" + organisationId
+}
+
+// NewCloudEvent Maps the webhook event to a CloudEvent
+func NewCloudEvent(webhookEvent WebhookEvent, organisationId string) CloudEvent {
+	return CloudEvent{
+		SpecVersion:     cloudEventsSpecVersion,
+		Id:              webhookEvent.DeliveryID,
+		Source:          getCloudEventSource(organisationId),
+		Type:            webhookEvent.Type,
+		Time:            webhookEvent.Timestamp,
+		DataContentType: "application/json",
+		Data:            webhookEvent.Data,
+	}
+}
+
+// newCloudEventsStructuredRequest Constructs HTTP request with the event encoded in the body
+func newCloudEventsStructuredRequest(payloadURL string, cloudEvent CloudEvent) (*http.Request, []byte, error) {
+	body, err := json.Marshal(cloudEvent)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	req, err := http.NewRequest("POST", payloadURL, bytes.NewBuffer(body))
+	if err != nil {
+		return nil, nil, err
+	}
+	req.Header.Set("Content-Type", "application/cloudevents+json; charset=UTF-8")
+
+	return req, body, nil
+}
+
+// newCloudEventsBinaryRequest Constructs HTTP request with the event data in
+// the body and the event attributes in ce-* headers
+func newCloudEventsBinaryRequest(payloadURL string, cloudEvent CloudEvent) (*http.Request, []byte, error) {
+	body := []byte(cloudEvent.Data)
+
+	req, err := http.NewRequest("POST", payloadURL, bytes.NewBuffer(body))
+	if err != nil {
+		return nil, nil, err
+	}
+	req.Header.Set("Content-Type", cloudEvent.DataContentType)
+	req.Header.Set("ce-specversion", cloudEvent.SpecVersion)
+	req.Header.Set("ce-id", cloudEvent.Id)
+	req.Header.Set("ce-source", cloudEvent.Source)
+	req.Header.Set("ce-type", cloudEvent.Type)
+	req.Header.Set("ce-time", cloudEvent.Time)
+
+	return req, body, nil
+}
@@ -38,6 +38,14 @@ func AddWebhookDelivery(webhookDelivery WebhookDelivery) (WebhookDelivery, error
 	return webhookDelivery, err
 }
 
+// UpdateWebhookDelivery Updates payload delivery details of a webhook event
+func UpdateWebhookDelivery(webhookDelivery WebhookDelivery) (WebhookDelivery, error) {
+
+	_, err := webhookDeliveryCollection().ReplaceOne(context.TODO(), bson.M{"_id": webhookDelivery.ID}, &webhookDelivery)
+
+	return webhookDelivery, err
+}
+
 // GetWebhookDeliveryByID Gets payload delivery details by ID
 func GetWebhookDeliveryByID(webhookID string, webhookDeliveryId string) (result WebhookDelivery, err error) {
 
@@ -45,3 +53,22 @@ func GetWebhookDeliveryByID(webhookID string, webhookDeliveryId string) (result
 
 	return result, err
 }
+
+// DeleteByIndividualIdWithContext Deletes the deliveries of events triggered
+// by or about the individual, except the ones about the data agreements to
+// be retained, context is used for transactions
+func DeleteByIndividualIdWithContext(ctx context.Context, organisationId string, individualId string, retainedDataAgreementIds []string) (int64, error) {
+	filter := bson.M{
+		"organisationid": organisationId,
+		"$or": []bson.M{
+			{"userid": individualId},
+			{"requestpayload.data.individualId": individualId},
+		},
+		"requestpayload.data.dataAgreementId": bson.M{"$nin": retainedDataAgreementIds},
+	}
+	result, err := webhookDeliveryCollection().DeleteMany(ctx, filter)
+	if err != nil {
+		return 0, err
+	}
+	return result.DeletedCount, nil
+}
@@ -0,0 +1,132 @@
+package webhook_dispatcher
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/bb-consent/api/internal/config"
+)
+
+// Id of the encryption key if it isn't configured
+const defaultEncryptionKeyId = "default"
+
+// encryptionKeyId Id of the key webhook secrets are encrypted with, the id
+// is stored along with the encrypted secret
+var encryptionKeyId string
+
+// encryptionKeys Keys for decrypting webhook secrets stored in db by id, the
+// current key and the previous keys
+var encryptionKeys map[string][]byte
+
+// legacyEncryptionKey Key derived from the API secret key, secrets encrypted
+// before key ids were stored are decrypted with it for re-encryption
+var legacyEncryptionKey []byte
+
+// decodeEncryptionKey Decodes base64 encoded 32 byte key
+func decodeEncryptionKey(encoded string) []byte {
+	key, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil || len(key) != 32 {
+		panic("webhooks encryption key should be 32 bytes encoded as base64")
+	}
+	return key
+}
+
+// initEncryptionKey Initializes the keys for encrypting webhook secrets, the
+// encryption key is required
+func initEncryptionKey(config *config.Configuration) {
+	if len(config.Webhooks.EncryptionKey) == 0 {
+		panic("webhooks encryption key is required, please configure 32 bytes encoded as base64")
+	}
+
+	encryptionKeyId = config.Webhooks.EncryptionKeyId
+	if len(encryptionKeyId) == 0 {
+		encryptionKeyId = defaultEncryptionKeyId
+	}
+	if strings.Contains(encryptionKeyId, ":") {
+		panic("webhooks encryption key id can't contain ':'")
+	}
+
+	encryptionKeys = map[string][]byte{}
+	for keyId, encodedKey := range config.Webhooks.PreviousEncryptionKeys {
+		encryptionKeys[keyId] = decodeEncryptionKey(encodedKey)
+	}
+	encryptionKeys[encryptionKeyId] = decodeEncryptionKey(config.Webhooks.EncryptionKey)
+
+	key := sha256.Sum256([]byte(config.ApiSecretKey))
+	legacyEncryptionKey = key[:]
+}
+
+// newGCM Returns AES-GCM cipher for the key
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// EncryptSecret Encrypts the secret using AES-GCM with the current key, nonce
+// is prepended to the cipher text and the id of the key to the encrypted secret
+func EncryptSecret(plainText string) (string, error) {
+	gcm, err := newGCM(encryptionKeys[encryptionKeyId])
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", err
+	}
+
+	cipherText := gcm.Seal(nonce, nonce, []byte(plainText), nil)
+	return encryptionKeyId + ":" + base64.StdEncoding.EncodeToString(cipherText), nil
+}
+
+// IsEncryptedWithCurrentKey Checks if the secret is encrypted with the current
+// key, secrets encrypted with other keys should be re-encrypted
+func IsEncryptedWithCurrentKey(encrypted string) bool {
+	keyId, _, found := strings.Cut(encrypted, ":")
+	return found && keyId == encryptionKeyId
+}
+
+// DecryptSecret Decrypts the secret encrypted using EncryptSecret
+func DecryptSecret(encrypted string) (string, error) {
+	// Secrets without key id are encrypted with the legacy key
+	key := legacyEncryptionKey
+	if keyId, encoded, found := strings.Cut(encrypted, ":"); found {
+		var ok bool
+		key, ok = encryptionKeys[keyId]
+		if !ok {
+			return "", fmt.Errorf("unknown encryption key: %v", keyId)
+		}
+		encrypted = encoded
+	}
+
+	cipherText, err := base64.StdEncoding.DecodeString(encrypted)
+	if err != nil {
+		return "", err
+	}
+
+	gcm, err := newGCM(key)
+	if err != nil {
+		return "", err
+	}
+
+	if len(cipherText) < gcm.NonceSize() {
+		return "", errors.New("invalid encrypted secret")
+	}
+	nonce, cipherText := cipherText[:gcm.NonceSize()], cipherText[gcm.NonceSize():]
+
+	plainText, err := gcm.Open(nil, nonce, cipherText, nil)
+	if err != nil {
+		return "", err
+	}
+	return string(plainText), nil
+}
@@ -0,0 +1,162 @@
+package webhook_dispatcher
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/textproto"
+	"strings"
+
+	"golang.org/x/exp/slices"
+)
+
+// Headers which are set while posting to webhook endpoint and can't be overridden
+var reservedHeaders = []string{
+	"Accept",
+	"Connection",
+	"Content-Length",
+	"Content-Type",
+	"Host",
+	"Transfer-Encoding",
+	"User-Agent",
+	"X-Igrant-Signature",
+}
+
+// Prefixes of headers which are set while posting to webhook endpoint
+var reservedHeaderPrefixes = []string{
+	"Ce-",
+	"Webhook-",
+}
+
+// Value the custom header values are replaced with in delivery records and
+// api responses, as they can carry credentials for e.g. Authorization
+const RedactedHeaderValue = "[redacted]"
+
+// EndpointOptions TLS and HTTP options for posting to webhook endpoint
+type EndpointOptions struct {
+	SkipSSLVerification bool              // Skip SSL certificate verification or not (expiry is checked)
+	ClientCertificate   string            // PEM encoded client certificate for mutual TLS
+	ClientKey           string            // Encrypted PEM encoded private key of client certificate
+	CACertificates      string            // PEM encoded CA certificates for verifying the endpoint
+	Headers             map[string]string // Custom headers posted to webhook endpoint
+}
+
+// EndpointOptions Returns TLS and HTTP options for posting to webhook endpoint
+func (webhook Webhook) EndpointOptions() EndpointOptions {
+	return EndpointOptions{
+		SkipSSLVerification: webhook.SkipSSLVerification,
+		ClientCertificate:   webhook.ClientCertificate,
+		ClientKey:           webhook.ClientKey,
+		CACertificates:      webhook.CACertificates,
+		Headers:             webhook.Headers,
+	}
+}
+
+// ValidateClientCertificate Checks if the client certificate and plain text key is a valid pair
+func ValidateClientCertificate(clientCertificate string, clientKey string) error {
+	if len(clientCertificate) == 0 && len(clientKey) == 0 {
+		return nil
+	}
+	if len(clientCertificate) == 0 || len(clientKey) == 0 {
+		return errors.New("please provide both client certificate and key")
+	}
+	_, err := tls.X509KeyPair([]byte(clientCertificate), []byte(clientKey))
+	if err != nil {
+		return fmt.Errorf("invalid client certificate or key: %v", err)
+	}
+	return nil
+}
+
+// ValidateCACertificates Checks if the CA bundle contains valid PEM encoded certificates
+func ValidateCACertificates(caCertificates string) error {
+	if len(caCertificates) == 0 {
+		return nil
+	}
+	if !x509.NewCertPool().AppendCertsFromPEM([]byte(caCertificates)) {
+		return errors.New("invalid CA certificates, please provide PEM encoded certificates")
+	}
+	return nil
+}
+
+// ValidateHeaders Checks if the custom headers are valid and doesn't override reserved headers
+func ValidateHeaders(headers map[string]string) error {
+	for name, value := range headers {
+		if len(name) == 0 || strings.ContainsAny(name, " \t\r\n:") {
+			return fmt.Errorf("invalid header name: %v", name)
+		}
+		if strings.ContainsAny(value, "\r\n") {
+			return fmt.Errorf("invalid value for header: %v", name)
+		}
+
+		canonicalName := textproto.CanonicalMIMEHeaderKey(name)
+		if slices.Contains(reservedHeaders, canonicalName) {
+			return fmt.Errorf("header can't be overridden: %v", name)
+		}
+		for _, prefix := range reservedHeaderPrefixes {
+			if strings.HasPrefix(canonicalName, prefix) {
+				return fmt.Errorf("header can't be overridden: %v", name)
+			}
+		}
+	}
+	return nil
+}
+
+// newTLSConfig Returns TLS configuration with client certificate and CA bundle of the endpoint
+func newTLSConfig(options EndpointOptions) (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		InsecureSkipVerify: options.SkipSSLVerification && !NetworkPolicy.disallowSkipSSLVerification,
+	}
+
+	if len(options.ClientCertificate) > 0 && len(options.ClientKey) > 0 {
+		clientKey, err := DecryptSecret(options.ClientKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt client key: %v", err)
+		}
+		certificate, err := tls.X509KeyPair([]byte(options.ClientCertificate), []byte(clientKey))
+		if err != nil {
+			return nil, fmt.Errorf("invalid client certificate or key: %v", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{certificate}
+	}
+
+	if len(options.CACertificates) > 0 {
+		rootCAs := x509.NewCertPool()
+		if !rootCAs.AppendCertsFromPEM([]byte(options.CACertificates)) {
+			return nil, errors.New("invalid CA certificates")
+		}
+		tlsConfig.RootCAs = rootCAs
+	}
+
+	return tlsConfig, nil
+}
+
+// RedactHeaders Returns the custom headers with their values redacted
+func RedactHeaders(headers map[string]string) map[string]string {
+	if headers == nil {
+		return nil
+	}
+	redacted := make(map[string]string, len(headers))
+	for name := range headers {
+		redacted[name] = RedactedHeaderValue
+	}
+	return redacted
+}
+
+// redactRequestHeaders Returns a copy of the request headers with the values
+// of the custom headers of the endpoint redacted
+func redactRequestHeaders(header http.Header, options EndpointOptions) http.Header {
+	redacted := header.Clone()
+	for name := range options.Headers {
+		redacted.Set(name, RedactedHeaderValue)
+	}
+	return redacted
+}
+
+// SetCustomHeaders Adds the custom headers of the endpoint to the request
+func SetCustomHeaders(req *http.Request, options EndpointOptions) {
+	for name, value := range options.Headers {
+		req.Header.Set(name, value)
+	}
+}
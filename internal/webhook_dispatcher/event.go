@@ -0,0 +1,94 @@
+package webhook_dispatcher
+
+import (
+	"encoding/json"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// WebhookEvent Webhook event envelope, the event data is kept as raw JSON so
+// that any event type can be delivered
+type WebhookEvent struct {
+	DeliveryID string          `json:"deliveryID"` // Webhook delivery ID
+	WebhookID  string          `json:"webhookID"`  // Webhook endpoint ID
+	Timestamp  string          `json:"timestamp"`  // UTC timestamp of webhook triggered data time
+	Data       json.RawMessage `json:"data"`       // Event data attribute
+	Type       string          `json:"type"`       // Event type for e.g. data.delete.initiated
+}
+
+// webhookEventDocument Structure of the webhook event stored in db, event data
+// is stored as a document
+type webhookEventDocument struct {
+	DeliveryID string
+	WebhookID  string
+	Timestamp  string
+	Data       bson.Raw `bson:",omitempty"`
+	Type       string
+}
+
+// NewWebhookEvent Constructs the webhook event envelope for event data
+func NewWebhookEvent(webhookID string, webhookEventType string, timestamp string, data interface{}) (WebhookEvent, error) {
+	dataBytes, err := json.Marshal(data)
+	if err != nil {
+		return WebhookEvent{}, err
+	}
+
+	webhookEvent := WebhookEvent{
+		WebhookID: webhookID,
+		Timestamp: timestamp,
+		Data:      dataBytes,
+		Type:      webhookEventType,
+	}
+	return webhookEvent, nil
+}
+
+// MarshalBSON Stores the event data as a document instead of binary, event
+// data must be a JSON object
+func (e WebhookEvent) MarshalBSON() ([]byte, error) {
+	var data bson.Raw
+	if len(e.Data) > 0 && string(e.Data) != "null" {
+		err := bson.UnmarshalExtJSON(e.Data, false, &data)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return bson.Marshal(webhookEventDocument{
+		DeliveryID: e.DeliveryID,
+		WebhookID:  e.WebhookID,
+		Timestamp:  e.Timestamp,
+		Data:       data,
+		Type:       e.Type,
+	})
+}
+
+// UnmarshalBSON Restores the event data stored as a document to raw JSON
+func (e *WebhookEvent) UnmarshalBSON(b []byte) error {
+	var document struct {
+		DeliveryID string
+		WebhookID  string
+		Timestamp  string
+		Data       bson.Raw
+		Type       string
+	}
+	err := bson.Unmarshal(b, &document)
+	if err != nil {
+		return err
+	}
+
+	e.DeliveryID = document.DeliveryID
+	e.WebhookID = document.WebhookID
+	e.Timestamp = document.Timestamp
+	e.Type = document.Type
+	e.Data = nil
+
+	if len(document.Data) > 0 {
+		data, err := bson.MarshalExtJSON(document.Data, false, false)
+		if err != nil {
+			return err
+		}
+		e.Data = data
+	}
+
+	return nil
+}
@@ -0,0 +1,96 @@
+package webhook_dispatcher
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/bb-consent/api/internal/actionlog"
+	"github.com/bb-consent/api/internal/email"
+	"github.com/bb-consent/api/internal/org"
+	"github.com/bb-consent/api/internal/user"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// recordWebhookSuccess Resets the consecutive failures of the webhook
+func recordWebhookSuccess(webhook Webhook) {
+	filter := bson.M{"_id": webhook.ID}
+	update := bson.M{"$set": bson.M{
+		"consecutivefailures":  0,
+		"lastsuccesstimestamp": time.Now().UTC().Format("2006-01-02T15:04:05Z"),
+	}}
+
+	_, err := webhookCollection().UpdateOne(context.TODO(), filter, update)
+	if err != nil {
+		log.Printf("Failed to record successful delivery for webhook:%s of org:%s", webhook.ID, webhook.OrganisationId)
+	}
+}
+
+// recordWebhookFailure Increments the consecutive failures of the webhook and
+// disables the webhook once the configured limit is reached
+func recordWebhookFailure(webhook Webhook) {
+	var result Webhook
+
+	filter := bson.M{"_id": webhook.ID}
+	update := bson.M{
+		"$inc": bson.M{"consecutivefailures": 1},
+		"$set": bson.M{"lastfailuretimestamp": time.Now().UTC().Format("2006-01-02T15:04:05Z")},
+	}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	err := webhookCollection().FindOneAndUpdate(context.TODO(), filter, update, opts).Decode(&result)
+	if err != nil {
+		log.Printf("Failed to record failed delivery for webhook:%s of org:%s", webhook.ID, webhook.OrganisationId)
+		return
+	}
+
+	if result.ConsecutiveFailures < DeliveryConfiguration.DisableAfterConsecutiveFailures {
+		return
+	}
+
+	disabledReason := fmt.Sprintf("Disabled after %v consecutive failed deliveries", result.ConsecutiveFailures)
+
+	// Only the worker which disables the webhook notifies the admins
+	filter = bson.M{"_id": webhook.ID, "disabled": false}
+	update = bson.M{"$set": bson.M{"disabled": true, "disabledreason": disabledReason}}
+
+	updateResult, err := webhookCollection().UpdateOne(context.TODO(), filter, update)
+	if err != nil {
+		log.Printf("Failed to disable webhook:%s of org:%s", webhook.ID, webhook.OrganisationId)
+		return
+	}
+	if updateResult.ModifiedCount == 0 {
+		return
+	}
+
+	log.Printf("Webhook:%s of org:%s is disabled; %s", webhook.ID, webhook.OrganisationId, disabledReason)
+	go notifyWebhookDisabled(result, disabledReason)
+}
+
+// notifyWebhookDisabled Records the webhook being disabled in the action log
+// and notifies the organisation admins by email
+func notifyWebhookDisabled(webhook Webhook, disabledReason string) {
+	aLog := fmt.Sprintf("Organization webhook: %v disabled: %v", webhook.PayloadURL, disabledReason)
+	actionlog.LogOrgWebhookCalls("", "", webhook.OrganisationId, aLog)
+
+	if len(email.SMTPConfig.AdminEmail) == 0 {
+		return
+	}
+
+	o, err := org.Get(webhook.OrganisationId)
+	if err != nil {
+		log.Printf("Failed to get organisation:%s; Failed to notify admins about disabled webhook:%s", webhook.OrganisationId, webhook.ID)
+		return
+	}
+
+	for _, admin := range o.Admins {
+		u, err := user.Get(admin.UserID)
+		if err != nil {
+			log.Printf("Failed to get admin:%s; Failed to notify admin about disabled webhook:%s", admin.UserID, webhook.ID)
+			continue
+		}
+		email.SendWebhookDisabledEmail(u.Email, o.Name, webhook.PayloadURL, disabledReason, "Webhook disabled", email.SMTPConfig.AdminEmail)
+	}
+}
@@ -0,0 +1,265 @@
+package webhook_dispatcher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/bb-consent/api/internal/config"
+)
+
+// ErrBlockedDestination Webhook endpoint isn't allowed by the outbound network policy
+var ErrBlockedDestination = errors.New("webhook endpoint is not allowed by the outbound network policy")
+
+// Maximum redirects followed when posting to webhook endpoint
+const maxRedirects = 10
+
+// Address ranges which aren't covered by net.IP helpers
+var reservedNetworks = mustParseCIDRs([]string{
+	"0.0.0.0/8",     // "This" network
+	"100.64.0.0/10", // Carrier-grade NAT
+	"192.0.0.0/24",  // IETF protocol assignments
+	"198.18.0.0/15", // Benchmarking
+	"240.0.0.0/4",   // Reserved
+	"64:ff9b::/96",  // IPv4/IPv6 translation
+})
+
+// networkPolicy Outbound network policy for webhook endpoints
+type networkPolicy struct {
+	allowPrivateNetworks        bool
+	allowedHosts                []string
+	allowedNetworks             []*net.IPNet
+	blockedHosts                []string
+	blockedNetworks             []*net.IPNet
+	requireHTTPS                bool
+	disallowSkipSSLVerification bool
+}
+
+// NetworkPolicy Stores outbound network policy for webhook endpoints
+var NetworkPolicy networkPolicy
+
+func mustParseCIDRs(cidrs []string) []*net.IPNet {
+	var networks []*net.IPNet
+	for _, cidr := range cidrs {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic(err)
+		}
+		networks = append(networks, network)
+	}
+	return networks
+}
+
+// splitHostsAndNetworks Separates the hosts and CIDRs (or IPs) in the list
+func splitHostsAndNetworks(list []string) ([]string, []*net.IPNet) {
+	var hosts []string
+	var networks []*net.IPNet
+	for _, entry := range list {
+		entry = strings.ToLower(strings.TrimSpace(entry))
+		if len(entry) == 0 {
+			continue
+		}
+		if _, network, err := net.ParseCIDR(entry); err == nil {
+			networks = append(networks, network)
+			continue
+		}
+		if ip := net.ParseIP(entry); ip != nil {
+			bits := 8 * net.IPv6len
+			if ip.To4() != nil {
+				ip = ip.To4()
+				bits = 8 * net.IPv4len
+			}
+			networks = append(networks, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		hosts = append(hosts, entry)
+	}
+	return hosts, networks
+}
+
+// initNetworkPolicy Initializes outbound network policy from configuration
+func initNetworkPolicy(policyConfig config.WebhookNetworkPolicyConfig) {
+	NetworkPolicy = networkPolicy{
+		allowPrivateNetworks:        policyConfig.AllowPrivateNetworks,
+		requireHTTPS:                policyConfig.RequireHTTPS,
+		disallowSkipSSLVerification: policyConfig.DisallowSkipSSLVerification,
+	}
+	NetworkPolicy.allowedHosts, NetworkPolicy.allowedNetworks = splitHostsAndNetworks(policyConfig.AllowList)
+	NetworkPolicy.blockedHosts, NetworkPolicy.blockedNetworks = splitHostsAndNetworks(policyConfig.BlockList)
+}
+
+// matchesHost Checks if the host is in the list, entries starting with `.` match subdomains
+func matchesHost(hosts []string, host string) bool {
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	for _, h := range hosts {
+		if strings.HasPrefix(h, ".") {
+			if strings.HasSuffix(host, h) || host == strings.TrimPrefix(h, ".") {
+				return true
+			}
+		} else if host == h {
+			return true
+		}
+	}
+	return false
+}
+
+func containsIP(networks []*net.IPNet, ip net.IP) bool {
+	for _, network := range networks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// isInternalIP Checks if the IP is private, loopback, link-local or reserved
+func isInternalIP(ip net.IP) bool {
+	return ip.IsLoopback() ||
+		ip.IsPrivate() ||
+		ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() ||
+		ip.IsMulticast() ||
+		ip.IsUnspecified() ||
+		containsIP(reservedNetworks, ip)
+}
+
+// checkIP Checks if the policy allows connecting to the IP, hostAllowed
+// indicates the host name resolving to the IP is in the allow list
+func (p networkPolicy) checkIP(ip net.IP, hostAllowed bool) error {
+	if ip4 := ip.To4(); ip4 != nil {
+		ip = ip4
+	}
+	if containsIP(p.blockedNetworks, ip) {
+		return ErrBlockedDestination
+	}
+	if hostAllowed || p.allowPrivateNetworks || containsIP(p.allowedNetworks, ip) {
+		return nil
+	}
+	if isInternalIP(ip) {
+		return ErrBlockedDestination
+	}
+	return nil
+}
+
+// checkURL Checks the scheme and host of the webhook endpoint without resolving the host
+func (p networkPolicy) checkURL(payloadURL string) (*url.URL, error) {
+	u, err := url.Parse(payloadURL)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme != "https" && (p.requireHTTPS || u.Scheme != "http") {
+		if p.requireHTTPS {
+			return nil, errors.New("webhook endpoint should use https://")
+		}
+		return nil, errors.New("webhook endpoint should use https:// or http://")
+	}
+	if len(u.Hostname()) == 0 {
+		return nil, errors.New("webhook endpoint should contain a host")
+	}
+	if matchesHost(p.blockedHosts, u.Hostname()) {
+		return nil, ErrBlockedDestination
+	}
+	return u, nil
+}
+
+// ValidateWebhookURL Checks if the outbound network policy allows the
+// webhook endpoint, IPs the host resolves to are checked as well
+func ValidateWebhookURL(payloadURL string) error {
+	u, err := NetworkPolicy.checkURL(payloadURL)
+	if err != nil {
+		return err
+	}
+
+	host := u.Hostname()
+	hostAllowed := matchesHost(NetworkPolicy.allowedHosts, host)
+
+	if ip := net.ParseIP(host); ip != nil {
+		return NetworkPolicy.checkIP(ip, hostAllowed)
+	}
+
+	ips, err := net.DefaultResolver.LookupIPAddr(context.TODO(), host)
+	if err != nil {
+		return fmt.Errorf("failed to resolve webhook endpoint host: %v", host)
+	}
+	for _, ip := range ips {
+		if err := NetworkPolicy.checkIP(ip.IP, hostAllowed); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ValidateSkipSSLVerification Checks if the outbound network policy allows
+// skipping SSL certificate verification
+func ValidateSkipSSLVerification(skipSSLVerification bool) error {
+	if skipSSLVerification && NetworkPolicy.disallowSkipSSLVerification {
+		return errors.New("skipping SSL certificate verification is not allowed")
+	}
+	return nil
+}
+
+// dialContext Dials the address after checking the policy against the
+// resolved IP at connect time, so that DNS rebinding can't bypass the policy
+func dialContext(ctx context.Context, network string, address string) (net.Conn, error) {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil, err
+	}
+	if matchesHost(NetworkPolicy.blockedHosts, host) {
+		return nil, ErrBlockedDestination
+	}
+	hostAllowed := matchesHost(NetworkPolicy.allowedHosts, host)
+
+	dialer := &net.Dialer{
+		Timeout:   deliveryTimeout,
+		KeepAlive: 30 * time.Second,
+		Control: func(network string, address string, c syscall.RawConn) error {
+			ipStr, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			ip := net.ParseIP(ipStr)
+			if ip == nil {
+				return ErrBlockedDestination
+			}
+			return NetworkPolicy.checkIP(ip, hostAllowed)
+		},
+	}
+	return dialer.DialContext(ctx, network, address)
+}
+
+// NewHTTPClient Returns HTTP client for posting to webhook endpoints which
+// enforces the outbound network policy
+func NewHTTPClient(options EndpointOptions, timeout time.Duration) (*http.Client, error) {
+	tlsConfig, err := newTLSConfig(options)
+	if err != nil {
+		return nil, err
+	}
+
+	transCfg := &http.Transport{
+		// Proxy isn't used, as the policy is enforced on the connected IP
+		Proxy:               nil,
+		DialContext:         dialContext,
+		TLSHandshakeTimeout: 10 * time.Second,
+		TLSClientConfig:     tlsConfig,
+	}
+
+	return &http.Client{
+		Transport: transCfg,
+		Timeout:   timeout,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= maxRedirects {
+				return errors.New("stopped after 10 redirects")
+			}
+			_, err := NetworkPolicy.checkURL(req.URL.String())
+			return err
+		},
+	}, nil
+}
@@ -0,0 +1,200 @@
+package webhook_dispatcher
+
+import (
+	"context"
+	"log"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/bb-consent/api/internal/config"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	// Time allowed for the webhook endpoint to respond
+	deliveryTimeout = 30 * time.Second
+
+	// Time for which a worker holds a claimed delivery. Deliveries claimed by
+	// a worker which didn't complete (e.g. process restart) are picked up
+	// again once the lock expires.
+	deliveryLockDuration = 5 * time.Minute
+
+	// Response body size recorded for a delivery
+	maxResponseBodySize = 64 * 1024
+)
+
+// DeliveryConfiguration Stores webhook delivery queue configuration
+var DeliveryConfiguration config.WebhookDeliveryConfig
+
+// deliveryNotifications Wakes up the workers when deliveries are queued
+var deliveryNotifications = make(chan struct{}, 1)
+
+var startWorkersOnce sync.Once
+
+// Init Initializes webhook delivery queue configuration, defaults are used for unset values
+func Init(config *config.Configuration) {
+	DeliveryConfiguration = config.Webhooks.Delivery
+	if DeliveryConfiguration.Workers <= 0 {
+		DeliveryConfiguration.Workers = 4
+	}
+	if DeliveryConfiguration.MaxAttempts <= 0 {
+		DeliveryConfiguration.MaxAttempts = 8
+	}
+	if DeliveryConfiguration.InitialBackoffInSeconds <= 0 {
+		DeliveryConfiguration.InitialBackoffInSeconds = 10
+	}
+	if DeliveryConfiguration.MaxBackoffInSeconds <= 0 {
+		DeliveryConfiguration.MaxBackoffInSeconds = 60 * 60
+	}
+	if DeliveryConfiguration.PollIntervalInSeconds <= 0 {
+		DeliveryConfiguration.PollIntervalInSeconds = 5
+	}
+	if DeliveryConfiguration.DisableAfterConsecutiveFailures <= 0 {
+		DeliveryConfiguration.DisableAfterConsecutiveFailures = 20
+	}
+
+	initNetworkPolicy(config.Webhooks.NetworkPolicy)
+	initEncryptionKey(config)
+}
+
+// getBackoff Returns the delay before the next attempt. The delay is doubled
+// for every attempt and jitter is added so that retries are spread out.
+func getBackoff(attempts int) time.Duration {
+	backoff := time.Duration(DeliveryConfiguration.InitialBackoffInSeconds) * time.Second
+	maxBackoff := time.Duration(DeliveryConfiguration.MaxBackoffInSeconds) * time.Second
+	for i := 1; i < attempts && backoff < maxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > maxBackoff {
+		backoff = maxBackoff
+	}
+
+	// Jitter between half and the full delay
+	half := backoff / 2
+	return half + time.Duration(rand.Int63n(int64(half)+1))
+}
+
+// Enqueue Persists the webhook event as a pending delivery, the delivery is
+// processed by the workers
+func Enqueue(webhookEvent WebhookEvent, organisationId string, userId string) (WebhookDelivery, error) {
+	webhookDelivery, _, err := enqueue(webhookEvent, organisationId, userId, "")
+	return webhookDelivery, err
+}
+
+// EnqueueOnce Persists the webhook event as a pending delivery unless the
+// event was already queued for the webhook, so an event published again
+// isn't delivered twice. Returns false if the event was already queued.
+func EnqueueOnce(webhookEvent WebhookEvent, organisationId string, userId string, eventId string) (WebhookDelivery, bool, error) {
+	return enqueue(webhookEvent, organisationId, userId, eventId+":"+webhookEvent.WebhookID)
+}
+
+// enqueue Persists the webhook event as a pending delivery with the
+// idempotency key, if any
+func enqueue(webhookEvent WebhookEvent, organisationId string, userId string, idempotencyKey string) (WebhookDelivery, bool, error) {
+	now := time.Now().UTC().Format("2006-01-02T15:04:05Z")
+	webhookDelivery := WebhookDelivery{
+		ID:                      primitive.NewObjectID().Hex(),
+		WebhookID:               webhookEvent.WebhookID,
+		OrganisationID:          organisationId,
+		UserID:                  userId,
+		WebhookEventType:        webhookEvent.Type,
+		RequestPayload:          webhookEvent,
+		ExecutionStartTimeStamp: now,
+		Status:                  DeliveryStatus[DeliveryStatusPending],
+		NextAttemptTimeStamp:    now,
+		IdempotencyKey:          idempotencyKey,
+	}
+	webhookDelivery.RequestPayload.DeliveryID = webhookDelivery.ID
+
+	webhookDelivery, err := AddWebhookDelivery(webhookDelivery)
+	if err != nil {
+		if len(idempotencyKey) > 0 && mongo.IsDuplicateKeyError(err) {
+			return webhookDelivery, false, nil
+		}
+		return webhookDelivery, false, err
+	}
+
+	// Wake up a worker without blocking the caller
+	select {
+	case deliveryNotifications <- struct{}{}:
+	default:
+	}
+
+	return webhookDelivery, true, nil
+}
+
+// claimWebhookDelivery Claims a delivery which is due, the claim is atomic so
+// that a delivery is processed by a single worker across replicas
+func claimWebhookDelivery() (WebhookDelivery, error) {
+	var result WebhookDelivery
+
+	now := time.Now().UTC()
+	nowTimestamp := now.Format("2006-01-02T15:04:05Z")
+
+	filter := bson.M{
+		"status":               bson.M{"$in": []string{DeliveryStatus[DeliveryStatusPending], DeliveryStatus[DeliveryStatusRetrying]}},
+		"nextattempttimestamp": bson.M{"$lte": nowTimestamp},
+		"$or": []bson.M{
+			{"lockeduntiltimestamp": bson.M{"$exists": false}},
+			{"lockeduntiltimestamp": ""},
+			{"lockeduntiltimestamp": bson.M{"$lte": nowTimestamp}},
+		},
+	}
+	update := bson.M{"$set": bson.M{"lockeduntiltimestamp": now.Add(deliveryLockDuration).Format("2006-01-02T15:04:05Z")}}
+	opts := options.FindOneAndUpdate().
+		SetSort(bson.M{"nextattempttimestamp": 1}).
+		SetReturnDocument(options.After)
+
+	err := webhookDeliveryCollection().FindOneAndUpdate(context.TODO(), filter, update, opts).Decode(&result)
+	return result, err
+}
+
+// processWebhookDeliveries Processes the deliveries which are due
+func processWebhookDeliveries() {
+	for {
+		webhookDelivery, err := claimWebhookDelivery()
+		if err != nil {
+			if err != mongo.ErrNoDocuments {
+				log.Printf("Failed to claim webhook delivery: %v", err)
+			}
+			return
+		}
+
+		webhookDelivery = attemptDelivery(webhookDelivery)
+		webhookDelivery.LockedUntilTimeStamp = ""
+
+		_, err = UpdateWebhookDelivery(webhookDelivery)
+		if err != nil {
+			log.Printf("Failed to save webhook delivery details to db;Failed processing webhook:%s triggered by user:%s of org:%s for event:%s", webhookDelivery.WebhookID, webhookDelivery.UserID, webhookDelivery.OrganisationID, webhookDelivery.WebhookEventType)
+		}
+	}
+}
+
+// worker Processes deliveries when woken up or at poll interval
+func worker() {
+	ticker := time.NewTicker(time.Duration(DeliveryConfiguration.PollIntervalInSeconds) * time.Second)
+	defer ticker.Stop()
+
+	for {
+		processWebhookDeliveries()
+
+		select {
+		case <-deliveryNotifications:
+		case <-ticker.C:
+		}
+	}
+}
+
+// StartWorkers Starts the worker pool delivering webhook payloads
+func StartWorkers() {
+	startWorkersOnce.Do(func() {
+		for i := 0; i < DeliveryConfiguration.Workers; i++ {
+			go worker()
+		}
+		log.Printf("Started %v webhook delivery workers", DeliveryConfiguration.Workers)
+	})
+}
@@ -0,0 +1,133 @@
+package webhook_dispatcher
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Signature scheme const
+const (
+	// Payload is signed with X-IGrant-Signature header
+	SignatureSchemeIGrant = "igrant"
+
+	// Payload is signed as per Standard Webhooks specification
+	// (https://www.standardwebhooks.com)
+	SignatureSchemeStandardWebhooks = "standard-webhooks"
+)
+
+// SignatureSchemes Available schemes for signing payload posted to webhook
+var SignatureSchemes = []string{
+	SignatureSchemeIGrant,
+	SignatureSchemeStandardWebhooks,
+}
+
+// Prefix of secrets generated in Standard Webhooks format
+const standardWebhooksSecretPrefix = "whsec_"
+
+// WebhookSecret Previous secret of a webhook which is used for signing payloads until it expires
+type WebhookSecret struct {
+	Secret          string `json:"secret"`          // Secret for calculating SHA256 HMAC
+	Timestamp       string `json:"timestamp"`       // UTC timestamp when the secret was rotated
+	ExpiryTimestamp string `json:"expiryTimestamp"` // UTC timestamp after which payloads are not signed with the secret
+}
+
+// GenerateWebhookSecret Generates a random webhook secret in Standard Webhooks format
+func GenerateWebhookSecret() (string, error) {
+	b := make([]byte, 32)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", err
+	}
+	return standardWebhooksSecretPrefix + base64.StdEncoding.EncodeToString(b), nil
+}
+
+// GetActiveSecrets Returns the current secret of the webhook followed by the
+// previous secrets which haven't expired
+func GetActiveSecrets(webhook Webhook) []string {
+	var secrets []string
+	if len(strings.TrimSpace(webhook.SecretKey)) > 0 {
+		secrets = append(secrets, webhook.SecretKey)
+	}
+
+	now := time.Now().UTC().Format("2006-01-02T15:04:05Z")
+	for _, previousSecret := range webhook.PreviousSecrets {
+		if previousSecret.ExpiryTimestamp > now && len(strings.TrimSpace(previousSecret.Secret)) > 0 {
+			secrets = append(secrets, previousSecret.Secret)
+		}
+	}
+	return secrets
+}
+
+// getSigningKey Returns the HMAC key for the secret, secrets in Standard
+// Webhooks format are base64 encoded
+func getSigningKey(secret string) []byte {
+	if strings.HasPrefix(secret, standardWebhooksSecretPrefix) {
+		key, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(secret, standardWebhooksSecretPrefix))
+		if err == nil {
+			return key
+		}
+	}
+	return []byte(secret)
+}
+
+// signIGrant Adds X-IGrant-Signature header with a signature for every active secret
+func signIGrant(req *http.Request, secrets []string, payload []byte) {
+	// Current UTC timestamp
+	timestamp := time.Now().UTC().Format("2006-01-02T15:04:05Z")
+
+	// Constructing SHA256 payload
+	sha256Payload := timestamp + "." + string(payload)
+
+	signature := "t=" + timestamp
+	for _, secret := range secrets {
+		// Create a new HMAC by defining the hash type and the key (as byte array)
+		h := hmac.New(sha256.New, []byte(secret))
+		h.Write([]byte(sha256Payload))
+
+		// Get result and encode as hexadecimal string
+		signature += ",sig=" + hex.EncodeToString(h.Sum(nil))
+	}
+
+	req.Header.Set("X-IGrant-Signature", signature)
+}
+
+// signStandardWebhooks Adds webhook-id, webhook-timestamp and webhook-signature
+// headers, the signature header contains a signature for every active secret
+func signStandardWebhooks(req *http.Request, secrets []string, messageId string, payload []byte) {
+	timestamp := strconv.FormatInt(time.Now().UTC().Unix(), 10)
+	signedContent := fmt.Sprintf("%s.%s.%s", messageId, timestamp, payload)
+
+	var signatures []string
+	for _, secret := range secrets {
+		h := hmac.New(sha256.New, getSigningKey(secret))
+		h.Write([]byte(signedContent))
+		signatures = append(signatures, "v1,"+base64.StdEncoding.EncodeToString(h.Sum(nil)))
+	}
+
+	req.Header.Set("webhook-id", messageId)
+	req.Header.Set("webhook-timestamp", timestamp)
+	req.Header.Set("webhook-signature", strings.Join(signatures, " "))
+}
+
+// signRequest Signs the payload posted to the webhook with all active secrets
+// using the signature scheme chosen for the webhook
+func signRequest(req *http.Request, webhook Webhook, messageId string, payload []byte) {
+	secrets := GetActiveSecrets(webhook)
+	if len(secrets) == 0 {
+		return
+	}
+
+	if webhook.SignatureScheme == SignatureSchemeStandardWebhooks {
+		signStandardWebhooks(req, secrets, messageId, payload)
+		return
+	}
+	signIGrant(req, secrets, payload)
+}
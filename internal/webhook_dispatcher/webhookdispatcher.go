@@ -2,43 +2,17 @@ package webhook_dispatcher
 
 import (
 	"bytes"
-	"crypto/hmac"
-	"crypto/sha256"
-	"crypto/tls"
-	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"io/ioutil"
+	"io"
 	"log"
 	"net/http"
 	"net/url"
 	"strings"
 	"time"
-
-	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
-// WebhookEvent Webhook event wrapper
-type WebhookEvent struct {
-	DeliveryID string                    `json:"deliveryID"` // Webhook delivery ID
-	WebhookID  string                    `json:"webhookID"`  // Webhook endpoint ID
-	Timestamp  string                    `json:"timestamp"`  // UTC timestamp of webhook triggered data time
-	Data       ConsentRecordWebhookEvent `json:"data"`       // Event data attribute
-	Type       string                    `json:"type"`       // Event type for e.g. data.delete.initiated
-}
-
-type ConsentRecordWebhookEvent struct {
-	ConsentRecordId           string `json:"consentRecordId"`
-	DataAgreementId           string `json:"dataAgreementId"`
-	DataAgreementRevisionId   string `json:"dataAgreementRevisionId"`
-	DataAgreementRevisionHash string `json:"dataAgreementRevisionHash"`
-	IndividualId              string `json:"individualId"`
-	OptIn                     bool   `json:"optIn"`
-	State                     string `json:"state"`
-	SignatureId               string `json:"signatureId"`
-	OrganisationId            string `json:"organisationId"`
-}
-
 // Payload content type const
 const (
 	// Payload will be posted as json body
@@ -46,43 +20,70 @@ const (
 
 	// Payload will be stringified and posted as form under `payload` key
 	PayloadContentTypeFormURLEncoded = 113
+
+	// Payload will be posted as CloudEvent in structured mode
+	PayloadContentTypeCloudEventsStructured = 114
+
+	// Payload data will be posted as json body with CloudEvent attributes
+	// as ce-* headers (binary mode)
+	PayloadContentTypeCloudEventsBinary = 115
 )
 
 // PayloadContentTypes Available data format for payload to be posted to webhook
 var PayloadContentTypes = map[int]string{
-	PayloadContentTypeJSON:           "application/json",
-	PayloadContentTypeFormURLEncoded: "application/x-www-form-urlencoded",
+	PayloadContentTypeJSON:                  "application/json",
+	PayloadContentTypeFormURLEncoded:        "application/x-www-form-urlencoded",
+	PayloadContentTypeCloudEventsStructured: "application/cloudevents+json",
+	PayloadContentTypeCloudEventsBinary:     "application/cloudevents-binary",
 }
 
 // Delivery status const
 const (
 	DeliveryStatusCompleted = 212
 	DeliveryStatusFailed    = 213
+	DeliveryStatusPending   = 214
+	DeliveryStatusRetrying  = 215
+	DeliveryStatusSucceeded = 216
 )
 
 // DeliveryStatus Indicating the payload delivery status to webhook
 var DeliveryStatus = map[int]string{
 	DeliveryStatusCompleted: "completed",
 	DeliveryStatusFailed:    "failed",
+	DeliveryStatusPending:   "pending",
+	DeliveryStatusRetrying:  "retrying",
+	DeliveryStatusSucceeded: "succeeded",
 }
 
 type Webhook struct {
-	ID                  string   `json:"id" bson:"_id,omitempty"`           // Webhook ID
-	OrganisationId      string   `json:"orgId" bson:"orgid"`                // Organisation ID
-	PayloadURL          string   `json:"payloadUrl" valid:"required"`       // Webhook payload URL
-	ContentType         string   `json:"contentType" valid:"required"`      // Webhook payload content type for e.g application/json
-	SubscribedEvents    []string `json:"subscribedEvents" valid:"required"` // Events subscribed for e.g. user.data.delete
-	Disabled            bool     `json:"disabled"`                          // Disabled or not
-	SecretKey           string   `json:"secretKey" valid:"required"`        // For calculating SHA256 HMAC to verify data integrity and authenticity
-	SkipSSLVerification bool     `json:"skipSslVerification"`               // Skip SSL certificate verification or not (expiry is checked)
-	TimeStamp           string   `json:"timestamp" valid:"required"`        // UTC timestamp
-	IsDeleted           bool     `json:"-"`
+	ID                  string            `json:"id" bson:"_id,omitempty"`           // Webhook ID
+	OrganisationId      string            `json:"orgId" bson:"orgid"`                // Organisation ID
+	PayloadURL          string            `json:"payloadUrl" valid:"required"`       // Webhook payload URL
+	ContentType         string            `json:"contentType" valid:"required"`      // Webhook payload content type for e.g application/json
+	SubscribedEvents    []string          `json:"subscribedEvents" valid:"required"` // Events subscribed for e.g. user.data.delete
+	Disabled            bool              `json:"disabled"`                          // Disabled or not
+	SecretKey           string            `json:"secretKey" valid:"required"`        // For calculating SHA256 HMAC to verify data integrity and authenticity
+	SkipSSLVerification bool              `json:"skipSslVerification"`               // Skip SSL certificate verification or not (expiry is checked)
+	TimeStamp           string            `json:"timestamp" valid:"required"`        // UTC timestamp
+	IsDeleted           bool              `json:"-"`
+	PreviousSecrets     []WebhookSecret   `json:"previousSecrets"`     // Previous secrets which are used for signing until they expire
+	SignatureScheme     string            `json:"signatureScheme"`     // Scheme for signing payloads for e.g. igrant or standard-webhooks
+	ClientCertificate   string            `json:"clientCertificate"`   // PEM encoded client certificate for mutual TLS
+	ClientKey           string            `json:"clientKey,omitempty"` // PEM encoded private key of client certificate, stored encrypted
+	CACertificates      string            `json:"caCertificates"`      // PEM encoded CA certificates for verifying the endpoint
+	Headers             map[string]string `json:"headers"`             // Custom headers posted to webhook endpoint
+
+	ConsecutiveFailures  int    `json:"consecutiveFailures"`  // Failed delivery attempts in a row
+	LastSuccessTimeStamp string `json:"lastSuccessTimestamp"` // UTC timestamp of last successful delivery attempt
+	LastFailureTimeStamp string `json:"lastFailureTimestamp"` // UTC timestamp of last failed delivery attempt
+	DisabledReason       string `json:"disabledReason"`       // Reason for the webhook to be disabled automatically
 }
 
 // WebhookDelivery Details of payload delivery to webhook endpoint
 type WebhookDelivery struct {
 	ID                      string              `bson:"_id,omitempty"` // Webhook delivery ID
 	WebhookID               string              // Webhook ID
+	OrganisationID          string              // ID of organisation the webhook belongs to
 	UserID                  string              // ID of user who triggered the webhook event
 	WebhookEventType        string              // Webhook event type for e.g. data.delete.initiated
 	RequestHeaders          map[string][]string // HTTP headers posted to webhook endpoint
@@ -93,67 +94,46 @@ type WebhookDelivery struct {
 	ResponseStatusStr       string              // HTTP response status string
 	ExecutionStartTimeStamp string              // UTC timestamp when webhook execution started
 	ExecutionEndTimeStamp   string              // UTC timestamp when webhook execution ended
-	Status                  string              // Status of webhook delivery for e.g. failed or completed
+	Status                  string              // Status of webhook delivery for e.g. failed or succeeded
 	StatusDescription       string              // Describe the status for e.g. Reason for failure
+	Attempts                int                 // Number of delivery attempts made
+	NextAttemptTimeStamp    string              // UTC timestamp after which the delivery is attempted again
+	LockedUntilTimeStamp    string              // UTC timestamp until which the delivery is claimed by a worker
+	IdempotencyKey          string              `bson:"idempotencykey,omitempty"` // Event and webhook ID, the event is queued once for the webhook
 }
 
-func ProcessWebhooks(webhookEventType string, value []byte) {
-	// For recording execution times
-	var executionStartTimeStamp string
-	var executionEndTimeStamp string
-
-	// For storing webhook payload delivery details to db
-	var webhookDelivery WebhookDelivery
-
-	// Recording webhook processing start timestamp
-	executionStartTimeStamp = time.Now().UTC().Format("2006-01-02T15:04:05Z")
-
-	// To store incoming webhook events
-	var webhookEvent WebhookEvent
-
-	// Unmarshalling the incoming message value bytes to webhook event struct
-	err := json.Unmarshal([]byte(value), &webhookEvent)
-	if err != nil {
-		log.Printf("Invalid incoming webhook recieved !")
-		return
-	}
-
-	// Webhook event data attribute
-	// Converting data attribute to appropriate webhook event struct
-
-	webhookEventData := webhookEvent.Data
-
-	// Quick fix
-	// Retrieving user and organisation ID from webhook data attribute
-	userID := webhookEventData.IndividualId
-	orgID := webhookEventData.OrganisationId
+// attemptDelivery Posts the webhook payload to the webhook endpoint and
+// records the outcome of the attempt in the delivery
+func attemptDelivery(webhookDelivery WebhookDelivery) WebhookDelivery {
+	webhookEvent := webhookDelivery.RequestPayload
+	webhookEventType := webhookDelivery.WebhookEventType
+	userID := webhookDelivery.UserID
+	orgID := webhookDelivery.OrganisationID
 
 	log.Printf("Processing webhook:%s triggered by user:%s of org:%s for event:%s", webhookEvent.WebhookID, userID, orgID, webhookEventType)
 
-	// Instantiating webhook delivery
-	webhookDelivery = WebhookDelivery{
-		ID:                      primitive.NewObjectID().Hex(),
-		WebhookID:               webhookEvent.WebhookID,
-		UserID:                  userID,
-		WebhookEventType:        webhookEventType,
-		ExecutionStartTimeStamp: executionStartTimeStamp,
-	}
+	// Recording webhook processing start timestamp
+	webhookDelivery.ExecutionStartTimeStamp = time.Now().UTC().Format("2006-01-02T15:04:05Z")
+	webhookDelivery.Attempts++
 
 	// Fetch webhook by ID
 	webhook, err := GetWebhookByOrgID(webhookEvent.WebhookID, orgID)
 	if err != nil {
 		log.Printf("Failed to fetch by webhook from db;Failed processing webhook:%s triggered by user:%s of org:%s for event:%s", webhookEvent.WebhookID, userID, orgID, webhookEventType)
-		return
+		return deliveryFailed(webhookDelivery, "Webhook doesn't exist")
 	}
 
 	// Checking if the webhook is disabled or not
-	if webhook.Disabled {
+	if webhook.Disabled || webhook.IsDeleted {
 		log.Printf("Webhook is disabled;Failed processing webhook:%s triggered by user:%s of org:%s for event:%s", webhookEvent.WebhookID, userID, orgID, webhookEventType)
-		return
+		return deliveryFailed(webhookDelivery, "Webhook is disabled")
 	}
 
-	// Getting the webhook secret key
-	secretKey := webhook.SecretKey
+	// Checking if the outbound network policy allows the webhook endpoint
+	if _, err := NetworkPolicy.checkURL(webhook.PayloadURL); err != nil {
+		log.Printf("Webhook endpoint not allowed err:%v;Failed processing webhook:%s triggered by user:%s of org:%s for event:%s", err.Error(), webhookEvent.WebhookID, userID, orgID, webhookEventType)
+		return deliveryFailed(webhookDelivery, fmt.Sprintf("Webhook endpoint is not allowed: %v", err))
+	}
 
 	// Updating webhook event payload with delivery ID
 	webhookEvent.DeliveryID = webhookDelivery.ID
@@ -161,114 +141,132 @@ func ProcessWebhooks(webhookEventType string, value []byte) {
 	// Constructing webhook payload bytes
 	requestPayload, _ := json.Marshal(&webhookEvent)
 
-	// Current UTC timestamp
-	timestamp := time.Now().UTC().Format("2006-01-02T15:04:05Z")
-
-	// Constructing SHA256 payload
-	sha256Payload := timestamp + "." + string(requestPayload)
-
-	// Create a new HMAC by defining the hash type and the key (as byte array)
-	h := hmac.New(sha256.New, []byte(secretKey))
-
-	// Write Data to it
-	h.Write([]byte(sha256Payload))
-
-	// Get result and encode as hexadecimal string
-	sha := hex.EncodeToString(h.Sum(nil))
-
-	// Constructing HTTP request instance based payload content type
+	// Constructing HTTP request instance based payload content type, the
+	// signed payload is the payload posted to the webhook endpoint
 	var req *http.Request
-	if webhook.ContentType == PayloadContentTypes[PayloadContentTypeFormURLEncoded] {
+	signedPayload := requestPayload
+	switch webhook.ContentType {
+	case PayloadContentTypes[PayloadContentTypeFormURLEncoded]:
 		// x-www-form-urlencoded payload
 		data := url.Values{}
 		data.Set("payload", string(requestPayload))
 
-		req, _ = http.NewRequest("POST", webhook.PayloadURL, strings.NewReader(data.Encode()))
-	} else {
-		req, _ = http.NewRequest("POST", webhook.PayloadURL, bytes.NewBuffer(requestPayload))
+		req, err = http.NewRequest("POST", webhook.PayloadURL, strings.NewReader(data.Encode()))
+		if err == nil {
+			req.Header.Set("Content-Type", webhook.ContentType)
+		}
+	case PayloadContentTypes[PayloadContentTypeCloudEventsStructured]:
+		req, signedPayload, err = newCloudEventsStructuredRequest(webhook.PayloadURL, NewCloudEvent(webhookEvent, orgID))
+	case PayloadContentTypes[PayloadContentTypeCloudEventsBinary]:
+		req, signedPayload, err = newCloudEventsBinaryRequest(webhook.PayloadURL, NewCloudEvent(webhookEvent, orgID))
+	default:
+		req, err = http.NewRequest("POST", webhook.PayloadURL, bytes.NewBuffer(requestPayload))
+		if err == nil {
+			req.Header.Set("Content-Type", webhook.ContentType)
+		}
+	}
+	if err != nil {
+		return deliveryFailed(webhookDelivery, fmt.Sprintf("Invalid webhook endpoint:%s", webhook.PayloadURL))
 	}
 
 	// Adding HTTP headers
-	// If secret key is defined, then add X-IGrant-Signature header for checking data integrity and authenticity
-	if len(strings.TrimSpace(secretKey)) > 0 {
-		req.Header.Set("X-IGrant-Signature", fmt.Sprintf("t=%s,sig=%s", timestamp, sha))
-	}
+	// Custom headers are added first, so that they don't override the headers below
+	SetCustomHeaders(req, webhook.EndpointOptions())
+
+	// If secrets are defined, then sign the payload for checking data integrity and authenticity
+	signRequest(req, webhook, webhookDelivery.ID, signedPayload)
 
-	req.Header.Set("Content-Type", webhook.ContentType)
 	req.Header.Set("User-Agent", "IGrant-Hookshot/1.0")
 	req.Header.Set("Accept", "*/*")
 
+	// Custom headers aren't stored in the delivery record
+	webhookDelivery.RequestHeaders = redactRequestHeaders(req.Header, webhook.EndpointOptions())
+	webhookDelivery.RequestPayload = webhookEvent
+
 	// Skip SSL certificate verification or not
-	transCfg := &http.Transport{
-		TLSClientConfig: &tls.Config{InsecureSkipVerify: webhook.SkipSSLVerification},
+	client, err := NewHTTPClient(webhook.EndpointOptions(), deliveryTimeout)
+	if err != nil {
+		log.Printf("Invalid TLS configuration err:%v;Failed processing webhook:%s triggered by user:%s of org:%s for event:%s", err.Error(), webhookEvent.WebhookID, userID, orgID, webhookEventType)
+		return deliveryFailed(webhookDelivery, fmt.Sprintf("Invalid TLS configuration for the webhook endpoint:%s", webhook.PayloadURL))
 	}
-
-	client := &http.Client{Transport: transCfg}
 	resp, err := client.Do(req)
 	if err != nil {
 		log.Printf("HTTP POST request failed err:%v;Failed processing webhook:%s triggered by user:%s of org:%s for event:%s", err.Error(), webhookEvent.WebhookID, userID, orgID, webhookEventType)
 
-		// Recording webhook processing end timestamp
-		executionEndTimeStamp = time.Now().UTC().Format("2006-01-02T15:04:05Z")
-
-		// Recording webhook delivery details to db
-		webhookDelivery.RequestHeaders = req.Header
-		webhookDelivery.RequestPayload = webhookEvent
-		webhookDelivery.StatusDescription = fmt.Sprintf("Error performing HTTP POST for the webhook endpoint:%s", webhook.PayloadURL)
-		webhookDelivery.Status = DeliveryStatus[DeliveryStatusFailed]
-		webhookDelivery.ExecutionEndTimeStamp = executionEndTimeStamp
+		webhookDelivery.ResponseHeaders = nil
+		webhookDelivery.ResponseBody = ""
+		webhookDelivery.ResponseStatusCode = 0
+		webhookDelivery.ResponseStatusStr = ""
+		recordWebhookFailure(webhook)
 
-		_, err = AddWebhookDelivery(webhookDelivery)
-		if err != nil {
-			log.Printf("Failed to save webhook delivery details to db;Failed processing webhook:%s triggered by user:%s of org:%s for event:%s", webhookEvent.WebhookID, userID, orgID, webhookEventType)
-			return
+		// Endpoints blocked by the outbound network policy aren't retried
+		if errors.Is(err, ErrBlockedDestination) {
+			return deliveryFailed(webhookDelivery, fmt.Sprintf("Webhook endpoint:%s is not allowed by the outbound network policy", webhook.PayloadURL))
 		}
-
-		return
+		return deliveryAttemptFailed(webhookDelivery, fmt.Sprintf("Error performing HTTP POST for the webhook endpoint:%s", webhook.PayloadURL))
 	}
 	defer resp.Body.Close()
 
-	respBodyBytes, err := ioutil.ReadAll(resp.Body)
+	// Recording the response
+	webhookDelivery.ResponseHeaders = resp.Header
+	webhookDelivery.ResponseStatusCode = resp.StatusCode
+	webhookDelivery.ResponseStatusStr = resp.Status
+
+	respBodyBytes, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBodySize))
 	if err != nil {
+		log.Printf("Failed to read webhook endpoint response;Failed processing webhook:%s triggered by user:%s of org:%s for event:%s", webhookEvent.WebhookID, userID, orgID, webhookEventType)
+	}
+	webhookDelivery.ResponseBody = string(respBodyBytes)
 
-		// Recording webhook processing end timestamp
-		executionEndTimeStamp = time.Now().UTC().Format("2006-01-02T15:04:05Z")
-
-		// Recording webhook delivery details to db
-		webhookDelivery.RequestHeaders = req.Header
-		webhookDelivery.RequestPayload = webhookEvent
-		webhookDelivery.ResponseHeaders = resp.Header
-		webhookDelivery.ResponseStatusCode = resp.StatusCode
-		webhookDelivery.ResponseStatusStr = resp.Status
-		webhookDelivery.ExecutionEndTimeStamp = executionEndTimeStamp
-		webhookDelivery.Status = DeliveryStatus[DeliveryStatusCompleted]
-
-		_, err = AddWebhookDelivery(webhookDelivery)
-		if err != nil {
-			log.Printf("Failed to save webhook delivery details to db;Failed processing webhook:%s triggered by user:%s of org:%s for event:%s", webhookEvent.WebhookID, userID, orgID, webhookEventType)
-			return
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		recordWebhookFailure(webhook)
+		statusDescription := fmt.Sprintf("Webhook endpoint responded with status:%s", resp.Status)
+		if !isRetryableStatusCode(resp.StatusCode) {
+			return deliveryFailed(webhookDelivery, statusDescription)
 		}
-
-		log.Printf("Failed to read webhook endpoint response;Failed processing webhook:%s triggered by user:%s of org:%s for event:%s", webhookEvent.WebhookID, userID, orgID, webhookEventType)
-		return
+		return deliveryAttemptFailed(webhookDelivery, statusDescription)
 	}
+	recordWebhookSuccess(webhook)
 
 	// Recording webhook processing end timestamp
-	executionEndTimeStamp = time.Now().UTC().Format("2006-01-02T15:04:05Z")
+	webhookDelivery.ExecutionEndTimeStamp = time.Now().UTC().Format("2006-01-02T15:04:05Z")
+	webhookDelivery.Status = DeliveryStatus[DeliveryStatusSucceeded]
+	webhookDelivery.StatusDescription = ""
+	webhookDelivery.NextAttemptTimeStamp = ""
+	return webhookDelivery
+}
 
-	// Recording webhook delivery details to db
-	webhookDelivery.RequestHeaders = req.Header
-	webhookDelivery.RequestPayload = webhookEvent
-	webhookDelivery.ResponseHeaders = resp.Header
-	webhookDelivery.ResponseBody = string(respBodyBytes)
-	webhookDelivery.ResponseStatusCode = resp.StatusCode
-	webhookDelivery.ResponseStatusStr = resp.Status
-	webhookDelivery.ExecutionEndTimeStamp = executionEndTimeStamp
-	webhookDelivery.Status = DeliveryStatus[DeliveryStatusCompleted]
+// isRetryableStatusCode Checks if the delivery is retried for the response
+// status. Client errors other than timeout and rate limiting won't succeed
+// on retry, so the delivery fails right away.
+func isRetryableStatusCode(statusCode int) bool {
+	if statusCode >= 400 && statusCode <= 499 {
+		return statusCode == http.StatusRequestTimeout || statusCode == http.StatusTooManyRequests
+	}
+	return true
+}
 
-	_, err = AddWebhookDelivery(webhookDelivery)
-	if err != nil {
-		log.Printf("Failed to save webhook delivery details to db;Failed processing webhook:%s triggered by user:%s of org:%s for event:%s", webhookEvent.WebhookID, userID, orgID, webhookEventType)
-		return
+// deliveryAttemptFailed Schedules a retry for the delivery, the delivery
+// is marked as failed once the attempts are exhausted
+func deliveryAttemptFailed(webhookDelivery WebhookDelivery, statusDescription string) WebhookDelivery {
+	if webhookDelivery.Attempts >= DeliveryConfiguration.MaxAttempts {
+		return deliveryFailed(webhookDelivery, statusDescription)
 	}
+
+	// Recording webhook processing end timestamp
+	webhookDelivery.ExecutionEndTimeStamp = time.Now().UTC().Format("2006-01-02T15:04:05Z")
+	webhookDelivery.Status = DeliveryStatus[DeliveryStatusRetrying]
+	webhookDelivery.StatusDescription = statusDescription
+	webhookDelivery.NextAttemptTimeStamp = time.Now().Add(getBackoff(webhookDelivery.Attempts)).UTC().Format("2006-01-02T15:04:05Z")
+	return webhookDelivery
+}
+
+// deliveryFailed Marks the delivery as failed, the delivery isn't attempted again
+func deliveryFailed(webhookDelivery WebhookDelivery, statusDescription string) WebhookDelivery {
+	// Recording webhook processing end timestamp
+	webhookDelivery.ExecutionEndTimeStamp = time.Now().UTC().Format("2006-01-02T15:04:05Z")
+	webhookDelivery.Status = DeliveryStatus[DeliveryStatusFailed]
+	webhookDelivery.StatusDescription = statusDescription
+	webhookDelivery.NextAttemptTimeStamp = ""
+	return webhookDelivery
 }
@@ -22,8 +22,82 @@ func main() {
 	// Define the "config" flag
 	startAPICmd.Flags().StringVarP(&cmd.ConfigFileName, "config", "c", "config-development.json", "configuration file")
 
-	// Add the "start-api" commands to the root command
+	// Define the "tenant" command and its sub commands
+	var tenantCmd = &cobra.Command{
+		Use:   "tenant",
+		Short: "Manages the organisations (tenants) in a multi tenant deployment",
+	}
+	tenantCmd.PersistentFlags().StringVarP(&cmd.ConfigFileName, "config", "c", "config-development.json", "configuration file")
+
+	var tenantCreateCmd = &cobra.Command{
+		Use:   "create",
+		Short: "Creates an organisation along with its admin",
+		Run:   cmd.TenantCreateCmdHandler,
+	}
+	tenantCreateCmd.Flags().StringVar(&cmd.TenantOrganisation.Name, "name", "", "organisation name")
+	tenantCreateCmd.Flags().StringVar(&cmd.TenantOrganisation.Location, "location", "", "organisation location")
+	tenantCreateCmd.Flags().StringVar(&cmd.TenantOrganisation.Description, "description", "", "organisation description")
+	tenantCreateCmd.Flags().StringVar(&cmd.TenantOrganisation.EulaURL, "eula-url", "", "organisation EULA url")
+	tenantCreateCmd.Flags().StringVar(&cmd.TenantAdmin.Username, "admin-username", "", "organisation admin username (email)")
+	tenantCreateCmd.Flags().StringVar(&cmd.TenantAdmin.Password, "admin-password", "", "organisation admin password, required unless an existing user is reused")
+	tenantCreateCmd.Flags().BoolVar(&cmd.ReuseExistingAdmin, "reuse-existing-admin", false, "make the existing user with the admin username the organisation admin, its password isn't changed")
+	tenantCreateCmd.MarkFlagRequired("name")
+	tenantCreateCmd.MarkFlagRequired("location")
+	tenantCreateCmd.MarkFlagRequired("admin-username")
+
+	var tenantListCmd = &cobra.Command{
+		Use:   "list",
+		Short: "Lists all organisations",
+		Run:   cmd.TenantListCmdHandler,
+	}
+
+	var tenantDisableCmd = &cobra.Command{
+		Use:   "disable",
+		Short: "Disables an organisation",
+		Run:   cmd.TenantDisableCmdHandler,
+	}
+	tenantDisableCmd.Flags().StringVar(&cmd.TenantId, "id", "", "organisation id")
+	tenantDisableCmd.MarkFlagRequired("id")
+
+	var tenantDeleteCmd = &cobra.Command{
+		Use:   "delete",
+		Short: "Deletes a disabled organisation",
+		Run:   cmd.TenantDeleteCmdHandler,
+	}
+	tenantDeleteCmd.Flags().StringVar(&cmd.TenantId, "id", "", "organisation id")
+	tenantDeleteCmd.MarkFlagRequired("id")
+
+	tenantCmd.AddCommand(tenantCreateCmd, tenantListCmd, tenantDisableCmd, tenantDeleteCmd)
+
+	// Define the "apikey-signing-secret" command and its sub commands
+	var signingSecretCmd = &cobra.Command{
+		Use:   "apikey-signing-secret",
+		Short: "Manages the secrets legacy api keys of an organisation were signed with",
+	}
+	signingSecretCmd.PersistentFlags().StringVarP(&cmd.ConfigFileName, "config", "c", "config-development.json", "configuration file")
+	signingSecretCmd.PersistentFlags().StringVar(&cmd.SigningSecretOrganisationId, "organisation-id", "", "organisation id")
+	signingSecretCmd.MarkPersistentFlagRequired("organisation-id")
+
+	var signingSecretListCmd = &cobra.Command{
+		Use:   "list",
+		Short: "Lists signing secrets",
+		Run:   cmd.ApiKeySigningSecretListCmdHandler,
+	}
+
+	var signingSecretRetireCmd = &cobra.Command{
+		Use:   "retire",
+		Short: "Retires a signing secret, api keys signed with it stop working",
+		Run:   cmd.ApiKeySigningSecretRetireCmdHandler,
+	}
+	signingSecretRetireCmd.Flags().StringVar(&cmd.SigningSecretId, "id", "", "signing secret id")
+	signingSecretRetireCmd.MarkFlagRequired("id")
+
+	signingSecretCmd.AddCommand(signingSecretListCmd, signingSecretRetireCmd)
+
+	// Add the "start-api", "tenant" and "apikey-signing-secret" commands to the root command
 	rootCmd.AddCommand(startAPICmd)
+	rootCmd.AddCommand(tenantCmd)
+	rootCmd.AddCommand(signingSecretCmd)
 
 	// Execute the CLI
 	if err := rootCmd.Execute(); err != nil {